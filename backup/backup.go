@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/eldelto/core/web"
+	"github.com/go-chi/chi/v5"
+	"go.etcd.io/bbolt"
+)
+
+const timestampFormat = "20060102T150405Z"
+
+// WriteSnapshot writes a consistent copy of the whole database to w.
+// Readers and writers are not blocked while the snapshot is taken.
+func WriteSnapshot(db *bbolt.DB, w io.Writer) (int64, error) {
+	var written int64
+	err := db.View(func(tx *bbolt.Tx) error {
+		var err error
+		written, err = tx.WriteTo(w)
+		return err
+	})
+	if err != nil {
+		return written, fmt.Errorf("write snapshot of %q: %w", db.Path(), err)
+	}
+
+	return written, nil
+}
+
+func snapshotName(db *bbolt.DB, t time.Time) string {
+	name := strings.TrimSuffix(filepath.Base(db.Path()), filepath.Ext(db.Path()))
+	return name + "-" + t.UTC().Format(timestampFormat) + ".db"
+}
+
+// TokenMiddleware only lets requests through that carry the given
+// token as bearer token in the Authorization header.
+func TokenMiddleware(token string) func(next http.Handler) http.Handler {
+	expected := []byte("Bearer " + token)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			actual := []byte(r.Header.Get("Authorization"))
+			if subtle.ConstantTimeCompare(expected, actual) != 1 {
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// NewModule returns a router that streams a snapshot of the database
+// to clients authenticated with the given token. The token must not
+// be empty.
+func NewModule(db *bbolt.DB, token string) (chi.Router, error) {
+	if token == "" {
+		return nil, fmt.Errorf("backup module for %q requires a token", db.Path())
+	}
+
+	r := chi.NewRouter()
+	eh := web.NewErrorHandlers()
+
+	r.Use(TokenMiddleware(token))
+	r.Get("/", eh.Handle(getSnapshot(db)))
+
+	return r, nil
+}
+
+func getSnapshot(db *bbolt.DB) web.Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		return db.View(func(tx *bbolt.Tx) error {
+			w.Header().Set(web.ContentTypeHeader, web.ContentTypeOctetStream)
+			w.Header().Set(web.ContentDispositionHeader,
+				fmt.Sprintf("attachment; filename=%q", snapshotName(db, time.Now())))
+			w.Header().Set("Content-Length", strconv.FormatInt(tx.Size(), 10))
+
+			// Once the first bytes are sent the status can't be changed
+			// anymore, so errors are only visible as a truncated body.
+			_, err := tx.WriteTo(w)
+			return err
+		})
+	}
+}
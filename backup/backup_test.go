@@ -0,0 +1,143 @@
+package backup_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/eldelto/core/backup"
+	. "github.com/eldelto/core/internal/testutils"
+	"go.etcd.io/bbolt"
+)
+
+func newDB(t *testing.T, dir string) *bbolt.DB {
+	db, err := bbolt.Open(filepath.Join(dir, "app.db"), 0600, nil)
+	AssertNoError(t, err, "bbolt.Open")
+
+	err = db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte("values"))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte("key"), []byte("value"))
+	})
+	AssertNoError(t, err, "db.Update")
+
+	return db
+}
+
+func assertValue(t *testing.T, path string) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{ReadOnly: true})
+	AssertNoError(t, err, "bbolt.Open")
+	defer db.Close()
+
+	err = db.View(func(tx *bbolt.Tx) error {
+		value := tx.Bucket([]byte("values")).Get([]byte("key"))
+		AssertEquals(t, "value", string(value), "restored value")
+		return nil
+	})
+	AssertNoError(t, err, "db.View")
+}
+
+func TestModule(t *testing.T) {
+	dir := t.TempDir()
+	db := newDB(t, dir)
+	defer db.Close()
+
+	_, err := backup.NewModule(db, "")
+	AssertError(t, err, "backup.NewModule without token")
+
+	module, err := backup.NewModule(db, "secret")
+	AssertNoError(t, err, "backup.NewModule")
+
+	server := httptest.NewServer(module)
+	defer server.Close()
+
+	res, err := http.Get(server.URL)
+	AssertNoError(t, err, "http.Get")
+	res.Body.Close()
+	AssertEquals(t, http.StatusUnauthorized, res.StatusCode, "status without token")
+
+	req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+	AssertNoError(t, err, "http.NewRequest")
+	req.Header.Set("Authorization", "Bearer secret")
+
+	res, err = http.DefaultClient.Do(req)
+	AssertNoError(t, err, "http.Do")
+	defer res.Body.Close()
+	AssertEquals(t, http.StatusOK, res.StatusCode, "status with token")
+
+	path := filepath.Join(dir, "downloaded.db")
+	file, err := os.Create(path)
+	AssertNoError(t, err, "os.Create")
+	_, err = file.ReadFrom(res.Body)
+	AssertNoError(t, err, "file.ReadFrom")
+	file.Close()
+
+	AssertNoError(t, backup.Validate(path), "backup.Validate")
+	assertValue(t, path)
+}
+
+func TestPruneSnapshots(t *testing.T) {
+	dir := t.TempDir()
+	db := newDB(t, dir)
+	defer db.Close()
+
+	snapshotDir := filepath.Join(dir, "snapshots")
+	for _, name := range []string{
+		"app-20240101T000000Z.db",
+		"app-20240102T000000Z.db",
+		"app-20240103T000000Z.db",
+		"other-20240101T000000Z.db",
+		"app-notes.db",
+	} {
+		err := os.MkdirAll(snapshotDir, 0700)
+		AssertNoError(t, err, "os.MkdirAll")
+		err = os.WriteFile(filepath.Join(snapshotDir, name), nil, 0600)
+		AssertNoError(t, err, "os.WriteFile")
+	}
+
+	path, err := backup.SnapshotFile(db, snapshotDir)
+	AssertNoError(t, err, "backup.SnapshotFile")
+
+	removed, err := backup.PruneSnapshots(db, snapshotDir, 2)
+	AssertNoError(t, err, "backup.PruneSnapshots")
+	AssertEquals(t, []string{
+		filepath.Join(snapshotDir, "app-20240101T000000Z.db"),
+		filepath.Join(snapshotDir, "app-20240102T000000Z.db"),
+	}, removed, "removed snapshots")
+
+	snapshots, err := backup.Snapshots(db, snapshotDir)
+	AssertNoError(t, err, "backup.Snapshots")
+	AssertEquals(t, []string{
+		filepath.Join(snapshotDir, "app-20240103T000000Z.db"),
+		path,
+	}, snapshots, "remaining snapshots")
+}
+
+func TestRestore(t *testing.T) {
+	dir := t.TempDir()
+	db := newDB(t, dir)
+	dbPath := db.Path()
+
+	snapshot, err := backup.SnapshotFile(db, filepath.Join(dir, "snapshots"))
+	AssertNoError(t, err, "backup.SnapshotFile")
+
+	_, err = backup.Restore(snapshot, dbPath)
+	AssertError(t, err, "backup.Restore while the database is open")
+	db.Close()
+
+	invalid := filepath.Join(dir, "invalid.db")
+	err = os.WriteFile(invalid, []byte("not a database"), 0600)
+	AssertNoError(t, err, "os.WriteFile")
+
+	_, err = backup.Restore(invalid, dbPath)
+	AssertError(t, err, "backup.Restore of an invalid snapshot")
+
+	backupPath, err := backup.Restore(snapshot, dbPath)
+	AssertNoError(t, err, "backup.Restore")
+	assertValue(t, dbPath)
+	assertValue(t, backupPath)
+}
@@ -0,0 +1,112 @@
+package backup
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.etcd.io/bbolt"
+)
+
+// Validate opens the snapshot at path read-only and checks the
+// consistency of all its pages.
+func Validate(path string) error {
+	if _, err := os.Stat(path); err != nil {
+		return fmt.Errorf("validate snapshot %q: %w", path, err)
+	}
+
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{
+		ReadOnly: true,
+		Timeout:  time.Second,
+	})
+	if err != nil {
+		return fmt.Errorf("validate snapshot %q: %w", path, err)
+	}
+	defer db.Close()
+
+	err = db.View(func(tx *bbolt.Tx) error {
+		errs := []error{}
+		for err := range tx.Check() {
+			errs = append(errs, err)
+		}
+		return errors.Join(errs...)
+	})
+	if err != nil {
+		return fmt.Errorf("validate snapshot %q: %w", path, err)
+	}
+
+	return nil
+}
+
+func copyFile(from, to string) error {
+	src, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(to, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, err := io.Copy(dst, src); err != nil {
+		return err
+	}
+	if err := dst.Sync(); err != nil {
+		return err
+	}
+
+	return dst.Close()
+}
+
+// Restore validates the snapshot and replaces the database at dbPath
+// with a copy of it. An existing database is kept next to it with a
+// timestamped .bak suffix and its path is returned. The database must
+// not be opened by any process while restoring.
+func Restore(snapshot, dbPath string) (string, error) {
+	if err := Validate(snapshot); err != nil {
+		return "", err
+	}
+
+	tmp := filepath.Join(filepath.Dir(dbPath),
+		fmt.Sprintf(".%s.restore-%d", filepath.Base(dbPath), time.Now().UnixNano()))
+	if err := copyFile(snapshot, tmp); err != nil {
+		os.Remove(tmp)
+		return "", fmt.Errorf("restore snapshot %q: %w", snapshot, err)
+	}
+	defer os.Remove(tmp)
+
+	// Opening the database fails if a running service holds the lock.
+	if _, err := os.Stat(dbPath); err == nil {
+		db, err := bbolt.Open(dbPath, 0600, &bbolt.Options{Timeout: time.Second})
+		if err != nil {
+			return "", fmt.Errorf("restore snapshot %q: database %q is in use: %w",
+				snapshot, dbPath, err)
+		}
+		db.Close()
+	}
+
+	var backupPath string
+	if _, err := os.Stat(dbPath); err == nil {
+		backupPath = dbPath + "." + time.Now().UTC().Format(timestampFormat) + ".bak"
+		if err := os.Rename(dbPath, backupPath); err != nil {
+			return "", fmt.Errorf("restore snapshot %q: back up %q: %w", snapshot, dbPath, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("restore snapshot %q: %w", snapshot, err)
+	}
+
+	if err := os.Rename(tmp, dbPath); err != nil {
+		if backupPath != "" {
+			err = errors.Join(err, os.Rename(backupPath, dbPath))
+		}
+		return "", fmt.Errorf("restore snapshot %q: %w", snapshot, err)
+	}
+
+	return backupPath, nil
+}
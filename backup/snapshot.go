@@ -0,0 +1,132 @@
+package backup
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-co-op/gocron/v2"
+	"go.etcd.io/bbolt"
+)
+
+// SnapshotFile writes a snapshot of the database into dir and returns
+// its path. The file only shows up under its final name once it has
+// been written completely.
+func SnapshotFile(db *bbolt.DB, dir string) (string, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", fmt.Errorf("create snapshot directory %q: %w", dir, err)
+	}
+
+	path := filepath.Join(dir, snapshotName(db, time.Now()))
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return "", fmt.Errorf("create snapshot file in %q: %w", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := WriteSnapshot(db, tmp); err != nil {
+		return "", err
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", fmt.Errorf("sync snapshot %q: %w", tmp.Name(), err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("close snapshot %q: %w", tmp.Name(), err)
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("move snapshot to %q: %w", path, err)
+	}
+
+	return path, nil
+}
+
+// Snapshots returns the paths of all snapshots of the database in dir
+// ordered from oldest to newest.
+func Snapshots(db *bbolt.DB, dir string) ([]string, error) {
+	prefix := strings.TrimSuffix(snapshotName(db, time.Time{}), "00010101T000000Z.db")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("list snapshots in %q: %w", dir, err)
+	}
+
+	paths := []string{}
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ".db") {
+			continue
+		}
+
+		timestamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".db")
+		if _, err := time.Parse(timestampFormat, timestamp); err != nil {
+			continue
+		}
+
+		paths = append(paths, filepath.Join(dir, name))
+	}
+
+	// The timestamp format sorts chronologically.
+	slices.Sort(paths)
+	return paths, nil
+}
+
+// PruneSnapshots removes all but the newest keep snapshots of the
+// database in dir and returns the removed paths.
+func PruneSnapshots(db *bbolt.DB, dir string, keep int) ([]string, error) {
+	paths, err := Snapshots(db, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) <= keep {
+		return nil, nil
+	}
+
+	removed := paths[:len(paths)-max(keep, 0)]
+	for _, path := range removed {
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("prune snapshot %q: %w", path, err)
+		}
+	}
+
+	return removed, nil
+}
+
+// ScheduleSnapshots registers a job with the given scheduler that
+// writes a snapshot of the database into dir every interval and only
+// keeps the newest keep snapshots.
+func ScheduleSnapshots(scheduler gocron.Scheduler, db *bbolt.DB, dir string,
+	interval time.Duration, keep int) error {
+	if keep < 1 {
+		return fmt.Errorf("schedule snapshots of %q: keep must be at least 1 but was %d",
+			db.Path(), keep)
+	}
+
+	_, err := scheduler.NewJob(gocron.DurationJob(interval),
+		gocron.NewTask(func() {
+			path, err := SnapshotFile(db, dir)
+			if err != nil {
+				log.Printf("failed to snapshot database: %v", err)
+				return
+			}
+
+			removed, err := PruneSnapshots(db, dir, keep)
+			if err != nil {
+				log.Printf("failed to prune snapshots: %v", err)
+				return
+			}
+
+			log.Printf("wrote snapshot %q and pruned %d old snapshots", path, len(removed))
+		}))
+	if err != nil {
+		return fmt.Errorf("schedule snapshots of %q: %w", db.Path(), err)
+	}
+
+	return nil
+}
@@ -10,12 +10,13 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/eldelto/core/backup"
 	"github.com/eldelto/core/internal/blog"
 	"github.com/eldelto/core/internal/blog/server"
 	"github.com/eldelto/core/internal/boltfs"
 	web "github.com/eldelto/core/internal/legacyweb"
 	"github.com/go-chi/chi/v5"
-	"github.com/go-co-op/gocron"
+	"github.com/go-co-op/gocron/v2"
 	"go.etcd.io/bbolt"
 )
 
@@ -24,6 +25,8 @@ const (
 	gitHostEnv     = "GIT_HOST"
 	hostEnv        = "HOST"
 	readOnlyEnv    = "READ_ONLY"
+	backupTokenEnv = "BACKUP_TOKEN"
+	backupDirEnv   = "BACKUP_DIR"
 	dbPath         = "blog.db"
 )
 
@@ -88,6 +91,9 @@ func main() {
 		readOnly = value
 	}
 
+	backupToken := os.Getenv(backupTokenEnv)
+	backupDir := os.Getenv(backupDirEnv)
+
 	sitemapContoller := web.NewSitemapController()
 
 	// Services
@@ -105,13 +111,23 @@ func main() {
 	updateArticles(service, destination, false)
 
 	// Schedulers
-	articleUpdater := gocron.NewScheduler(time.UTC)
-	defer articleUpdater.Stop()
-	if _, err := articleUpdater.Every(1).Hour().Do(updateArticles, service, destination, !readOnly); err != nil {
-		log.Fatalf("failed to start articleUpdater scheduled job: %v", err)
+	scheduler, err := gocron.NewScheduler(gocron.WithLocation(time.UTC))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer scheduler.Shutdown()
+
+	_, err = scheduler.NewJob(gocron.DurationJob(time.Hour),
+		gocron.NewTask(updateArticles, service, destination, !readOnly))
+	if err != nil {
+		log.Fatalf("failed to schedule article updates: %v", err)
 	}
-	articleUpdater.WaitForSchedule()
-	articleUpdater.StartAsync()
+	if backupDir != "" {
+		if err := backup.ScheduleSnapshots(scheduler, db, backupDir, 24*time.Hour, 7); err != nil {
+			log.Fatal(err)
+		}
+	}
+	scheduler.Start()
 
 	// Controllers
 	r := chi.NewRouter()
@@ -132,6 +148,14 @@ func main() {
 	server.NewDiatomController().Register(r)
 	statsModule.Controller().Register(r)
 
+	if backupToken != "" {
+		backupModule, err := backup.NewModule(db, backupToken)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.Mount("/backup", backupModule)
+	}
+
 	http.Handle("/", r)
 
 	log.Printf("Blog listening on localhost:%d", port)
@@ -0,0 +1,9 @@
+package main
+
+import (
+	"github.com/eldelto/core/cmd/boltctl/cmd"
+)
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/eldelto/core/internal/boltutil"
+	"github.com/spf13/cobra"
+	"go.etcd.io/bbolt"
+)
+
+func listBuckets(db *bbolt.DB) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tKEYS\tBUCKETS\tCODEC")
+
+	err := db.View(func(tx *bbolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bbolt.Bucket) error {
+			codec, err := boltutil.CodecOf(tx, string(name))
+			if err != nil {
+				return err
+			}
+
+			keys, buckets := 0, 0
+			err = b.ForEach(func(k, v []byte) error {
+				if v == nil {
+					buckets++
+				} else {
+					keys++
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintf(w, "%s\t%d\t%d\t%s\n", formatKey(name), keys, buckets, codec.Name())
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("list buckets: %w", err)
+	}
+
+	return w.Flush()
+}
+
+var bucketsCmd = &cobra.Command{
+	Use:   "buckets",
+	Short: "Lists all top-level buckets",
+	Long: `buckets lists all top-level buckets of the database together with the
+number of keys and nested buckets they contain and the codec their values are
+encoded with.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		db, err := openDB(true)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+
+		if err := listBuckets(db); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(bucketsCmd)
+}
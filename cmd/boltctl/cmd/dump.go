@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.etcd.io/bbolt"
+)
+
+func dumpBucket(w io.Writer, db *bbolt.DB, bucketName string, key []byte) error {
+	err := db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return fmt.Errorf("bucket %q does not exist", bucketName)
+		}
+
+		root := []string{bucketName}
+		if key != nil {
+			if nested := bucket.Bucket(key); nested != nil {
+				bucket = nested
+				root = append(root, formatKey(key))
+			} else if value := bucket.Get(key); value != nil {
+				return dumpValue(w, tx, root, key, value)
+			} else {
+				return fmt.Errorf("key %q does not exist", formatKey(key))
+			}
+		}
+
+		return walkBucket(bucket, root, func(path []string, k, v []byte) error {
+			return dumpValue(w, tx, path, k, v)
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("dump bucket %q: %w", bucketName, err)
+	}
+
+	return nil
+}
+
+func dumpValue(w io.Writer, tx *bbolt.Tx, path []string, k, v []byte) error {
+	value, err := decodeValue(tx, path, v)
+	if err != nil {
+		return fmt.Errorf("key %q: %w", formatKey(k), err)
+	}
+	if value == nil {
+		value = rawValue(v)
+	}
+
+	prefix := ""
+	if len(path) > 1 {
+		prefix = strings.Join(path[1:], "/") + "/"
+	}
+
+	_, err = fmt.Fprintf(w, "%s%s: %s\n", prefix, formatKey(k), value)
+	return err
+}
+
+var dumpCmd = &cobra.Command{
+	Use:   "dump <bucket> [key]",
+	Short: "Prints the keys and values of a bucket",
+	Long: `dump prints all keys and values of the given bucket including the ones of
+nested buckets, one per line.
+
+Values of known buckets and records written by the storage package get decoded
+and printed as JSON. All other values are printed as string if they are
+printable or hex encoded otherwise.
+
+If a key is given, only its value or the content of the nested bucket with that
+name is printed. Keys starting with 0x are interpreted as hex.`,
+	Args: cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		var key []byte
+		if len(args) > 1 {
+			k, err := parseKey(args[1])
+			if err != nil {
+				log.Fatal(err)
+			}
+			key = k
+		}
+
+		db, err := openDB(true)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+
+		if err := dumpBucket(os.Stdout, db, args[0], key); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dumpCmd)
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/eldelto/core/storage"
+	"github.com/spf13/cobra"
+)
+
+func printHistory(w io.Writer, store *storage.Storage, bucketName string, id []byte) error {
+	records, err := storage.ReadValue(store, func(tx *storage.ReadTx) ([]storage.Record, error) {
+		return storage.RecordsOf(tx, bucketName, id)
+	})
+	if err != nil {
+		return fmt.Errorf("history of %q in bucket %q: %w", formatKey(id), bucketName, err)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "INSERTED AT\tINSERTED BY\tFIELD\tVALUE")
+	for _, r := range records {
+		value, err := json.Marshal(r.Attribute)
+		if err != nil {
+			value = []byte(fmt.Sprintf("%v", r.Attribute))
+		}
+		if r.Retraction {
+			value = []byte("(retracted)")
+		}
+
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			time.UnixMilli(r.InsertedAt).Format(time.RFC3339),
+			r.InsertedBy, r.Value, value)
+	}
+
+	return tw.Flush()
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history <bucket> <id>",
+	Short: "Shows the history of a storage entity",
+	Long: `history prints every record of the entity with the given ID in the order
+they were written. This only works for buckets written by the storage package.
+
+IDs starting with 0x are interpreted as hex.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		id, err := parseKey(args[1])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		db, err := openDB(true)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+
+		if err := printHistory(os.Stdout, storage.New(db), args[0], id); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
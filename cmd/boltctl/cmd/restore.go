@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"log"
+
+	"github.com/eldelto/core/backup"
+	"github.com/spf13/cobra"
+)
+
+var validateOnly bool
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore <snapshot>",
+	Short: "Replaces the database with a snapshot",
+	Long: `restore validates the given snapshot and replaces the database with it.
+
+The current database is kept next to it with a .bak suffix. The service using
+the database needs to be stopped before restoring.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if validateOnly {
+			if err := backup.Validate(args[0]); err != nil {
+				log.Fatal(err)
+			}
+			log.Printf("snapshot %q is valid", args[0])
+			return
+		}
+
+		if dbPath == "" {
+			log.Fatal("no database given, please set --db")
+		}
+
+		backupPath, err := backup.Restore(args[0], dbPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		if backupPath != "" {
+			log.Printf("moved previous database to %q", backupPath)
+		}
+		log.Printf("restored %q from snapshot %q", dbPath, args[0])
+	},
+}
+
+func init() {
+	restoreCmd.Flags().BoolVar(&validateOnly, "validate", false,
+		"Only validate the snapshot without restoring it.")
+	rootCmd.AddCommand(restoreCmd)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+	"go.etcd.io/bbolt"
+)
+
+var dbPath string
+
+func openDB(readOnly bool) (*bbolt.DB, error) {
+	if dbPath == "" {
+		return nil, fmt.Errorf("no database given, please set --db")
+	}
+	// Only writing commands are allowed to create a new database.
+	if _, err := os.Stat(dbPath); err != nil && readOnly {
+		return nil, fmt.Errorf("open database %q: %w", dbPath, err)
+	}
+
+	// The timeout prevents waiting forever on a database that is
+	// locked by a running service.
+	db, err := bbolt.Open(dbPath, 0600, &bbolt.Options{
+		ReadOnly: readOnly,
+		Timeout:  time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("open database %q: %w", dbPath, err)
+	}
+
+	return db, nil
+}
+
+// formatKey returns printable keys as is and all others hex encoded
+// with a 0x prefix so parseKey can reverse it.
+func formatKey(key []byte) string {
+	printable := utf8.Valid(key) && !strings.HasPrefix(string(key), "0x") &&
+		strings.IndexFunc(string(key), func(r rune) bool {
+			return !unicode.IsPrint(r)
+		}) < 0
+	if printable && len(key) > 0 {
+		return string(key)
+	}
+
+	return "0x" + hex.EncodeToString(key)
+}
+
+func parseKey(key string) ([]byte, error) {
+	if !strings.HasPrefix(key, "0x") {
+		return []byte(key), nil
+	}
+
+	b, err := hex.DecodeString(key[2:])
+	if err != nil {
+		return nil, fmt.Errorf("parse key %q: %w", key, err)
+	}
+
+	return b, nil
+}
+
+var rootCmd = &cobra.Command{
+	Use:   "boltctl",
+	Short: "A CLI tool to inspect bbolt databases.",
+	Long: `A CLI tool to inspect the bbolt databases of our services.
+
+Values of known buckets and records written by the storage package get
+decoded, everything else is printed as is.
+
+Refer to the help page of the individual sub-commands for more information.`,
+}
+
+func Execute() {
+	err := rootCmd.Execute()
+	if err != nil {
+		os.Exit(1)
+	}
+}
+
+func init() {
+	rootCmd.PersistentFlags().StringVarP(&dbPath, "db", "d", "",
+		"Path to the bbolt database file.")
+}
@@ -0,0 +1,215 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/eldelto/core/internal/boltutil"
+	"github.com/eldelto/core/storage"
+	"github.com/spf13/cobra"
+	"go.etcd.io/bbolt"
+)
+
+var (
+	rawExport   bool
+	importCodec string
+)
+
+// exportLine is a single value of an exported bucket. Path contains
+// the names of the nested buckets leading to the value. Values that
+// can not be decoded are exported as Raw.
+type exportLine struct {
+	Path  []string        `json:"path,omitempty"`
+	Key   string          `json:"key"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Raw   []byte          `json:"raw,omitempty"`
+}
+
+func exportBucket(w io.Writer, db *bbolt.DB, bucketName string, raw bool) error {
+	encoder := json.NewEncoder(w)
+	err := db.View(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return fmt.Errorf("bucket %q does not exist", bucketName)
+		}
+
+		return walkBucket(bucket, []string{bucketName}, func(path []string, k, v []byte) error {
+			line := exportLine{Path: path[1:], Key: formatKey(k)}
+			if !raw {
+				value, err := decodeValue(tx, path, v)
+				if err != nil {
+					return fmt.Errorf("key %q: %w", line.Key, err)
+				}
+				line.Value = value
+			}
+			if line.Value == nil {
+				line.Raw = v
+			}
+
+			return encoder.Encode(line)
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("export bucket %q: %w", bucketName, err)
+	}
+
+	return nil
+}
+
+func importLine(tx *bbolt.Tx, bucketName string, line exportLine) error {
+	bucket, err := tx.CreateBucketIfNotExists([]byte(bucketName))
+	if err != nil {
+		return err
+	}
+
+	for _, name := range line.Path {
+		key, err := parseKey(name)
+		if err != nil {
+			return err
+		}
+
+		bucket, err = bucket.CreateBucketIfNotExists(key)
+		if err != nil {
+			return err
+		}
+	}
+
+	key, err := parseKey(line.Key)
+	if err != nil {
+		return err
+	}
+
+	value := line.Raw
+	if value == nil {
+		path := append([]string{bucketName}, line.Path...)
+		value, err = encodeValue(tx, path, line.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := bucket.Put(key, value); err != nil {
+		return err
+	}
+
+	// The storage package keys records by the bucket's sequence which
+	// needs to continue after the imported ones.
+	if len(line.Path) > 0 && len(key) == 8 {
+		if sequence := binary.BigEndian.Uint64(key); sequence > bucket.Sequence() {
+			return bucket.SetSequence(sequence)
+		}
+	}
+
+	return nil
+}
+
+func importBucket(r io.Reader, db *bbolt.DB, bucketName string, codec boltutil.Codec) (int, error) {
+	imported := 0
+	err := db.Update(func(tx *bbolt.Tx) error {
+		if _, err := boltutil.EnsureCodec(tx, bucketName, codec); err != nil {
+			return err
+		}
+
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+		for scanner.Scan() {
+			if len(scanner.Bytes()) == 0 {
+				continue
+			}
+
+			var line exportLine
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				return fmt.Errorf("line %d: %w", imported+1, err)
+			}
+
+			if err := importLine(tx, bucketName, line); err != nil {
+				return fmt.Errorf("line %d: %w", imported+1, err)
+			}
+			imported++
+		}
+
+		return scanner.Err()
+	})
+	if err != nil {
+		return 0, fmt.Errorf("import bucket %q: %w", bucketName, err)
+	}
+
+	// Indexes and the cache get rebuilt the next time the bucket is
+	// registered.
+	if err := storage.ResetDerivedData(db, bucketName); err != nil {
+		return imported, err
+	}
+
+	return imported, nil
+}
+
+var exportCmd = &cobra.Command{
+	Use:   "export <bucket>",
+	Short: "Exports a bucket as JSON lines",
+	Long: `export writes every value of the given bucket and its nested buckets as a
+single JSON object per line to stdout.
+
+Values that can be decoded are exported as JSON in the value field, all others
+base64 encoded in the raw field. The output can be read again with import.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		db, err := openDB(true)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+
+		w := bufio.NewWriter(os.Stdout)
+		if err := exportBucket(w, db, args[0], rawExport); err != nil {
+			log.Fatal(err)
+		}
+		if err := w.Flush(); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+var importCmd = &cobra.Command{
+	Use:   "import <bucket>",
+	Short: "Imports a bucket from JSON lines",
+	Long: `import reads JSON lines as written by export from stdin and stores them in
+the given bucket. Existing keys get overwritten.
+
+Decoded values are encoded with the codec of the bucket. New buckets use the
+codec given by --codec.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		codec, err := boltutil.CodecByName(importCodec)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		db, err := openDB(false)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer db.Close()
+
+		imported, err := importBucket(os.Stdin, db, args[0], codec)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.Printf("imported %d values into bucket %q", imported, args[0])
+	},
+}
+
+func init() {
+	exportCmd.Flags().BoolVar(&rawExport, "raw", false,
+		"Export all values as raw bytes without decoding them.")
+	rootCmd.AddCommand(exportCmd)
+
+	importCmd.Flags().StringVar(&importCodec, "codec", boltutil.Gob.Name(),
+		"The codec to encode values of a new bucket with (gob, json or cbor).")
+	rootCmd.AddCommand(importCmd)
+}
@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/eldelto/core/internal/boltutil"
+	. "github.com/eldelto/core/internal/testutils"
+	"github.com/eldelto/core/storage"
+	"go.etcd.io/bbolt"
+)
+
+// recordExport returns an exported record whose attribute has a named
+// type boltctl doesn't know about.
+func recordExport(t *testing.T) []byte {
+	record, err := storage.EncodeRecord(boltutil.JSON, storage.Record{
+		ID:         []byte("order-1"),
+		Value:      "Status",
+		Attribute:  "open",
+		InsertedAt: 1,
+	})
+	AssertNoError(t, err, "storage.EncodeRecord")
+	record = bytes.Replace(record, []byte(`"AttributeType":"string"`),
+		[]byte(`"AttributeType":"example.com/shop.Status"`), 1)
+
+	key := binary.BigEndian.AppendUint64(nil, 1)
+	line, err := json.Marshal(exportLine{Path: []string{"order-1"}, Key: formatKey(key), Value: record})
+	AssertNoError(t, err, "json.Marshal")
+	return line
+}
+
+func TestImportNamedType(t *testing.T) {
+	db, err := bbolt.Open(filepath.Join(t.TempDir(), "boltctl-test.db"), 0600, nil)
+	AssertNoError(t, err, "bbolt.Open")
+	defer db.Close()
+
+	line := recordExport(t)
+	imported, err := importBucket(bytes.NewReader(line), db, "orders", boltutil.JSON)
+	AssertNoError(t, err, "importBucket")
+	AssertEquals(t, 1, imported, "imported values")
+
+	// The record is kept as is and can be exported again.
+	out := &bytes.Buffer{}
+	AssertNoError(t, exportBucket(out, db, "orders", false), "exportBucket")
+	AssertEquals(t, string(line), strings.TrimSpace(out.String()), "exported line")
+
+	// Other codecs need the type to encode the attribute.
+	_, err = importBucket(bytes.NewReader(line), db, "cbor-orders", boltutil.CBOR)
+	AssertError(t, err, "importBucket into cbor bucket")
+	AssertStringContains(t, "--raw", err.Error(), "error")
+}
@@ -0,0 +1,190 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/eldelto/core/internal/blog"
+	"github.com/eldelto/core/internal/boltutil"
+	"github.com/eldelto/core/internal/legacyweb"
+	"github.com/eldelto/core/internal/lucklog"
+	"github.com/eldelto/core/internal/mealplanner"
+	"github.com/eldelto/core/internal/solvent"
+	"github.com/eldelto/core/storage"
+	"go.etcd.io/bbolt"
+)
+
+// knownTypes maps the buckets our services write with boltutil to the
+// type of their values.
+var knownTypes = map[string]func() any{
+	"auth.tokens":       func() any { return &legacyweb.Token{} },
+	"auth.sessions":     func() any { return &legacyweb.Session{} },
+	"auth.emailMapping": func() any { return &legacyweb.UserID{} },
+	"statistics.views":  func() any { return new(uint) },
+	"config-provider":   func() any { return new(string) },
+	"notebooks":         func() any { return &solvent.Notebook{} },
+	"logbook":           func() any { return &lucklog.Logbook{} },
+	"recipes":           func() any { return &mealplanner.Recipe{} },
+	blog.PageBucket:     func() any { return &blog.Article{} },
+}
+
+const (
+	cacheBucket  = "_cache"
+	schemaBucket = "_schema"
+)
+
+// isRecord reports if the value at path is a storage.Record, which is
+// the case for all values of entity buckets and the storage cache.
+// It returns the name of the bucket the record belongs to.
+func isRecord(path []string) (string, bool) {
+	if len(path) != 2 {
+		return "", false
+	}
+
+	if path[0] == cacheBucket {
+		return path[1], true
+	}
+	if strings.HasPrefix(path[0], "_") {
+		return "", false
+	}
+
+	return path[0], true
+}
+
+// rawValue returns printable values as JSON string and all others hex
+// encoded.
+func rawValue(value []byte) json.RawMessage {
+	data, _ := json.Marshal(formatKey(value))
+	return data
+}
+
+func compactJSON(value json.RawMessage) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	if err := json.Compact(&buffer, value); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// decodeValue returns the JSON representation of a value stored under
+// the given path of bucket names or nil if it can not be decoded.
+func decodeValue(tx *bbolt.Tx, path []string, value []byte) (json.RawMessage, error) {
+	if bucketName, ok := isRecord(path); ok {
+		codec, err := boltutil.CodecOf(tx, bucketName)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := storage.DecodeRecord(codec, value)
+		if errors.Is(err, storage.ErrTypeNotRegistered) {
+			// Records of JSON buckets are readable without knowing
+			// the type of their attribute, all others stay raw.
+			log.Printf("warning: %s: %v, keeping the record as is",
+				strings.Join(path, "/"), err)
+			if codec.Name() == boltutil.JSON.Name() {
+				return value, nil
+			}
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode record: %w", err)
+		}
+		return storage.EncodeRecord(boltutil.JSON, r)
+	}
+
+	if len(path) != 1 {
+		return nil, nil
+	}
+
+	if path[0] == schemaBucket && len(value) == 8 {
+		return json.Marshal(binary.BigEndian.Uint64(value))
+	}
+
+	codec, err := boltutil.CodecOf(tx, path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	newValue, ok := knownTypes[path[0]]
+	if !ok {
+		if codec.Name() == boltutil.JSON.Name() {
+			return value, nil
+		}
+		return nil, nil
+	}
+
+	v := newValue()
+	if err := codec.Unmarshal(value, v); err != nil {
+		return nil, fmt.Errorf("decode value: %w", err)
+	}
+
+	return json.Marshal(v)
+}
+
+// encodeValue is the counterpart of decodeValue.
+func encodeValue(tx *bbolt.Tx, path []string, value json.RawMessage) ([]byte, error) {
+	if bucketName, ok := isRecord(path); ok {
+		codec, err := boltutil.CodecOf(tx, bucketName)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := storage.DecodeRecord(boltutil.JSON, value)
+		if errors.Is(err, storage.ErrTypeNotRegistered) {
+			// The value already is the record encoded as JSON.
+			if codec.Name() == boltutil.JSON.Name() {
+				log.Printf("warning: %s: %v, storing the record as is",
+					strings.Join(path, "/"), err)
+				return compactJSON(value)
+			}
+			return nil, fmt.Errorf("decode record: %w, export the bucket with --raw to copy it as is",
+				err)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("decode record: %w", err)
+		}
+		return storage.EncodeRecord(codec, r)
+	}
+
+	if len(path) == 1 {
+		codec, err := boltutil.CodecOf(tx, path[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if newValue, ok := knownTypes[path[0]]; ok {
+			v := newValue()
+			if err := json.Unmarshal(value, v); err != nil {
+				return nil, fmt.Errorf("decode value: %w", err)
+			}
+			return codec.Marshal(v)
+		}
+
+		if codec.Name() == boltutil.JSON.Name() {
+			return value, nil
+		}
+	}
+
+	return nil, fmt.Errorf("values of bucket %q can not be encoded, please use raw values",
+		strings.Join(path, "/"))
+}
+
+// walkBucket calls f for every value of the bucket and its nested
+// buckets with the names of the buckets leading to it.
+func walkBucket(bucket *bbolt.Bucket, path []string,
+	f func(path []string, k, v []byte) error) error {
+	return bucket.ForEach(func(k, v []byte) error {
+		if v != nil {
+			return f(path, k, v)
+		}
+
+		nestedPath := append(path[:len(path):len(path)], formatKey(k))
+		return walkBucket(bucket.Bucket(k), nestedPath, f)
+	})
+}
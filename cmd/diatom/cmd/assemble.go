@@ -4,25 +4,35 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strings"
 
-	"github.com/eldelto/core/internal/diatom"
+	"github.com/eldelto/core/internal/diatom/v2"
 	"github.com/spf13/cobra"
 )
 
-var intermediateFlag = false
+var (
+	intermediateFlag = false
+	optimizeFlag     = false
+)
 
-func assemble(path string, intermediate bool) error {
+func assemble(path string, intermediate, optimize bool) error {
 	in, err := os.Open(path)
 	if err != nil {
 		return fmt.Errorf("failed to open file %q: %w", path, err)
 	}
 	defer in.Close()
 
-	dexp, dins, dopc, err := diatom.Assemble(in)
+	program, err := diatom.AssembleProgramWithOptions(filepath.Base(path), in,
+		diatom.AssemblerOptions{Optimize: optimize})
 	if err != nil {
 		return err
 	}
+	dexp, dins, dopc := program.Dexp, program.Dins, program.Dopc
+
+	if program.Optimization != nil {
+		fmt.Printf("optimized %s: %s\n", path, program.Optimization)
+	}
 
 	dopcPath := strings.Replace(path, ".dasm", ".dopc", 1)
 	if err := os.WriteFile(dopcPath, dopc, 0664); err != nil {
@@ -54,6 +64,7 @@ code file at the same location.
 During assembly the following steps are performend:
 
   - Expand macros and number constants
+  - Optimize the code (only with --optimize)
   - Resolve labels
   - Translate instructions to machine code
 
@@ -61,7 +72,7 @@ Partial assembly steps can be performed by using one of the available flags.
 Please see assemble -h for more details.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		path := args[0]
-		if err := assemble(path, false); err != nil {
+		if err := assemble(path, intermediateFlag, optimizeFlag); err != nil {
 			log.Fatal(err)
 		}
 	},
@@ -75,5 +86,9 @@ the assembly process:
   - .dexp - Assembly after macro expansion
   - .dins - Assembly with resolved labels
   - .dopc - Machine code`)
+	assembleCmd.Flags().BoolVarP(&optimizeFlag, "optimize", "O", false,
+		`If true, assemble will inline calls to trivial subroutines and simplify short
+instruction sequences before resolving labels. The size savings are printed
+once done.`)
 	rootCmd.AddCommand(assembleCmd)
 }
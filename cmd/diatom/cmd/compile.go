@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/eldelto/core/internal/diatom/v2"
+	"github.com/spf13/cobra"
+)
+
+var (
+	compileTarget     = "go"
+	compileOutputFlag = ""
+	compilePackage    = ""
+	compileName       = ""
+	compileRepl       = false
+)
+
+func compile(path, outputPath string) error {
+	if compileTarget != "go" {
+		return fmt.Errorf("unknown target %q, only 'go' is supported", compileTarget)
+	}
+
+	var program diatom.Program
+	var err error
+	switch {
+	case compileRepl && path != "":
+		return errors.New("--repl doesn't take a path")
+	case compileRepl:
+		program, err = diatom.AssembleRepl(vmOptions)
+	case path == "":
+		return errors.New("a path or --repl is required")
+	default:
+		program, err = loadProgram(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create file %q: %w", outputPath, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	// Without labels the compiler falls back to the dictionary.
+	opts := diatom.CompilerOptions{Package: compilePackage, Name: compileName}
+	if err := diatom.CompileGo(program.Dopc, program.Labels, out, opts); err != nil {
+		return fmt.Errorf("failed to compile to Go: %w", err)
+	}
+
+	return nil
+}
+
+var compileCmd = &cobra.Command{
+	Use:   "compile [path]",
+	Args:  cobra.MatchAll(cobra.MaximumNArgs(1)),
+	Short: "Compiles the .dasm or .dopc file at the given path to Go",
+	Long: `compile translates the machine code of the given .dasm or .dopc file into a
+Go source file that can be embedded in Go programs.
+
+Every codeword becomes a Go function that implements its instructions
+without going through the instruction dispatch of the interpreter. The
+labels of .dasm files mark the codewords, for .dopc files the dictionary
+found in memory is used instead. The generated file declares a
+diatom.CompiledProgram that is run with:
+
+  vm, err := diatom.NewVM(Program.Image, os.Stdin, os.Stdout)
+  ...
+  err = vm.ExecuteCompiled(Program)
+
+Computed jumps into the middle of a codeword and code created at runtime,
+e.g. words defined in the Forth interpreter, are executed by the
+interpreter. Programs that modify their own code can't be compiled.
+
+With --repl the Forth interpreter of the preamble is compiled, configured
+by the memory and stack size flags.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		path := ""
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if err := compile(path, compileOutputFlag); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	compileCmd.Flags().StringVar(&compileTarget, "target", compileTarget,
+		"Language to compile to, only 'go' is supported")
+	compileCmd.Flags().StringVarP(&compileOutputFlag, "output", "o", "",
+		"Write the generated code to the given file instead of stdout")
+	compileCmd.Flags().StringVar(&compilePackage, "package", "main",
+		"Package of the generated Go file")
+	compileCmd.Flags().StringVar(&compileName, "name", "Program",
+		"Name of the generated diatom.CompiledProgram variable")
+	compileCmd.Flags().BoolVar(&compileRepl, "repl", false,
+		"Compile the Forth interpreter of the preamble instead of a file")
+	addVMOptionFlags(compileCmd)
+	rootCmd.AddCommand(compileCmd)
+}
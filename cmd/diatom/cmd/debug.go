@@ -0,0 +1,295 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/eldelto/core/internal/diatom/v2"
+	"github.com/spf13/cobra"
+)
+
+var debugInputPath string
+
+const debugHelp = `Commands:
+  s, step               Execute a single instruction
+  n, next               Execute a single instruction but step over calls
+  c, continue           Execute until the next breakpoint is hit
+  b, break <loc>        Set a breakpoint at a label or address
+  d, delete <loc>       Remove the breakpoint at a label or address
+  breakpoints           List all breakpoints
+  w, where              Show the current instruction
+  st, stack             Show the data and return stack
+  m, mem <loc> [len]    Show len bytes of memory (default 64)
+  sym <addr>            Resolve an address to the closest label
+  h, help               Show this help
+  q, quit               Stop debugging
+
+An empty line repeats the previous command.`
+
+type debugSession struct {
+	debugger  *diatom.Debugger
+	sourceMap *diatom.SourceMap
+	out       io.Writer
+}
+
+func (s *debugSession) location(addr diatom.Word) string {
+	symbol := s.debugger.Symbols().Resolve(addr)
+	if symbol == strconv.Itoa(int(addr)) {
+		return symbol
+	}
+
+	return fmt.Sprintf("%d <%s>", addr, symbol)
+}
+
+func (s *debugSession) where() error {
+	if s.debugger.Done() {
+		_, err := fmt.Fprintln(s.out, "program finished")
+		return err
+	}
+
+	pc := s.debugger.VM().ProgramCounter()
+	instruction, _, err := s.debugger.Instruction(pc)
+	if err != nil {
+		return err
+	}
+
+	source := ""
+	if location, ok := s.sourceMap.Lookup(pc); ok {
+		source = "  ; " + location.String()
+	}
+
+	_, err = fmt.Fprintf(s.out, "%s  %s%s\n", s.location(pc), instruction, source)
+	return err
+}
+
+func (s *debugSession) stacks() error {
+	vm := s.debugger.VM()
+	if _, err := fmt.Fprintf(s.out, "data:   %v\n", vm.DataStack()); err != nil {
+		return err
+	}
+
+	returnStack := vm.ReturnStack()
+	locations := make([]string, len(returnStack))
+	for i, addr := range returnStack {
+		locations[i] = s.location(addr)
+	}
+
+	_, err := fmt.Fprintf(s.out, "return: [%s]\n", strings.Join(locations, ", "))
+	return err
+}
+
+func (s *debugSession) memory(args []string) error {
+	if len(args) < 1 {
+		return errors.New("mem requires a location")
+	}
+
+	addr, err := s.debugger.Symbols().Address(args[0])
+	if err != nil {
+		return err
+	}
+
+	length := 64
+	if len(args) > 1 {
+		if length, err = strconv.Atoi(args[1]); err != nil || length < 1 {
+			return fmt.Errorf("%q is not a valid length", args[1])
+		}
+	}
+
+	data, err := s.debugger.VM().Memory(addr, length)
+	if err != nil {
+		return err
+	}
+
+	for i := 0; i < len(data); i += 16 {
+		line := data[i:min(i+16, len(data))]
+		printable := bytes.Map(func(r rune) rune {
+			if r < 32 || r > 126 {
+				return '.'
+			}
+			return r
+		}, line)
+
+		if _, err := fmt.Fprintf(s.out, "%6d  %-47s  %s\n", int(addr)+i,
+			fmt.Sprintf("% x", line), printable); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *debugSession) breakpoint(args []string, add bool) error {
+	if len(args) < 1 {
+		return errors.New("a breakpoint requires a location")
+	}
+
+	addr, err := s.debugger.Symbols().Address(args[0])
+	if err != nil {
+		return err
+	}
+
+	if add {
+		s.debugger.AddBreakpoint(addr)
+		_, err = fmt.Fprintf(s.out, "breakpoint at %s\n", s.location(addr))
+		return err
+	}
+
+	if !s.debugger.RemoveBreakpoint(addr) {
+		return fmt.Errorf("no breakpoint at %s", s.location(addr))
+	}
+	return nil
+}
+
+// execute runs a single debugger command and reports if the session
+// should end.
+func (s *debugSession) execute(line string) (bool, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 1 {
+		return false, nil
+	}
+	command, args := fields[0], fields[1:]
+
+	switch command {
+	case "s", "step":
+		if err := s.debugger.Step(); err != nil {
+			return false, err
+		}
+		return false, s.where()
+	case "n", "next":
+		if err := s.debugger.StepOver(); err != nil {
+			return false, err
+		}
+		return false, s.where()
+	case "c", "continue":
+		if err := s.debugger.Continue(); err != nil {
+			return false, err
+		}
+		return false, s.where()
+	case "b", "break":
+		return false, s.breakpoint(args, true)
+	case "d", "delete":
+		return false, s.breakpoint(args, false)
+	case "breakpoints":
+		for _, addr := range s.debugger.Breakpoints() {
+			if _, err := fmt.Fprintln(s.out, s.location(addr)); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	case "w", "where":
+		return false, s.where()
+	case "st", "stack":
+		return false, s.stacks()
+	case "m", "mem":
+		return false, s.memory(args)
+	case "sym":
+		if len(args) < 1 {
+			return false, errors.New("sym requires an address")
+		}
+		addr, err := s.debugger.Symbols().Address(args[0])
+		if err != nil {
+			return false, err
+		}
+		_, err = fmt.Fprintln(s.out, s.location(addr))
+		return false, err
+	case "h", "help":
+		_, err := fmt.Fprintln(s.out, debugHelp)
+		return false, err
+	case "q", "quit":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q - type 'help' for a list of commands", command)
+	}
+}
+
+func (s *debugSession) run(in io.Reader) error {
+	if err := s.where(); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(in)
+	previous := ""
+	for {
+		if _, err := fmt.Fprint(s.out, "(ddb) "); err != nil {
+			return err
+		}
+		if !scanner.Scan() {
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			line = previous
+		}
+		previous = line
+
+		quit, err := s.execute(line)
+		if err != nil {
+			if _, err := fmt.Fprintf(s.out, "error: %v\n", err); err != nil {
+				return err
+			}
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+var debugCmd = &cobra.Command{
+	Use:   "debug <path>",
+	Args:  cobra.MatchAll(cobra.ExactArgs(1)),
+	Short: "Debugs a program interactively",
+	Long: `debug loads the given .dasm or .dopc file into the VM and reads debugger
+commands from stdin instead of executing it right away.
+
+Programs loaded from .dasm files can use their labels as locations for
+breakpoints and memory views. As stdin is used for the debugger, the program
+reads its input from the file given by --input.
+
+Type 'help' inside the debugger for a list of commands.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		program, err := loadProgram(args[0])
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		var input io.Reader = &bytes.Buffer{}
+		if debugInputPath != "" {
+			file, err := os.Open(debugInputPath)
+			if err != nil {
+				log.Fatalf("failed to open input file %q: %v", debugInputPath, err)
+			}
+			defer file.Close()
+			input = file
+		}
+
+		vm, err := diatom.NewVMWithOptions(program.Dopc, input, os.Stdout, vmOptions)
+		if err != nil {
+			log.Fatal(err)
+		}
+		vm.SetSourceMap(program.SourceMap)
+
+		session := debugSession{
+			debugger:  diatom.NewDebugger(vm, program.Labels),
+			sourceMap: program.SourceMap,
+			out:       os.Stdout,
+		}
+		if err := session.run(os.Stdin); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	debugCmd.Flags().StringVar(&debugInputPath, "input", "",
+		"Path to a file the program reads its input from.")
+	addVMOptionFlags(debugCmd)
+	rootCmd.AddCommand(debugCmd)
+}
@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/eldelto/core/internal/diatom/v2"
+	"github.com/spf13/cobra"
+)
+
+var disassembleOutputFlag = ""
+
+func disassemble(path, outputPath string) error {
+	image, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file %q: %w", path, err)
+	}
+
+	var out io.Writer = os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create file %q: %w", outputPath, err)
+		}
+		defer f.Close()
+		out = f
+	}
+
+	if err := diatom.Disassemble(image, out); err != nil {
+		return fmt.Errorf("failed to disassemble %q: %w", path, err)
+	}
+
+	return nil
+}
+
+var disassembleCmd = &cobra.Command{
+	Use:   "disassemble [path]",
+	Args:  cobra.MatchAll(cobra.ExactArgs(1)),
+	Short: "Disassembles the .dopc file at the given path",
+	Long: `disassemble reads the given .dopc machine code file or memory dump and prints
+it as annotated assembly.
+
+The dictionary is reconstructed from the word headers found in memory and
+every header is annotated with its name and flags. Codewords and the
+targets of jumps and calls are labeled, const operands are decoded and
+strings embedded in the code are shown as such.
+
+The output uses the format of the macro expanded assembly (.dexp) and can
+be assembled again.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := disassemble(args[0], disassembleOutputFlag); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	disassembleCmd.Flags().StringVarP(&disassembleOutputFlag, "output", "o", "",
+		"Write the disassembly to the given file instead of stdout")
+	rootCmd.AddCommand(disassembleCmd)
+}
@@ -0,0 +1,263 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/eldelto/core/internal/diatom/v2"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var replImagePath string
+
+const replHelp = `Type Forth words to evaluate them. Word definitions can span multiple lines.
+
+Commands:
+  .load <path>   Evaluate the given .dia file
+  .save <path>   Save the current dictionary as .dopc image
+  .help          Show this help
+  .quit          Leave the REPL (or press Ctrl-D)`
+
+// lastByteWriter remembers if the last write ended with a newline so
+// the stack display always starts on a fresh line.
+type lastByteWriter struct {
+	w    io.Writer
+	last byte
+}
+
+func (w *lastByteWriter) Write(p []byte) (int, error) {
+	if len(p) > 0 {
+		w.last = p[len(p)-1]
+	}
+	return w.w.Write(p)
+}
+
+type replSession struct {
+	repl        *diatom.Repl
+	out         *lastByteWriter
+	interactive bool
+}
+
+func newReplSession(out io.Writer, interactive bool) (*replSession, error) {
+	writer := &lastByteWriter{w: out, last: '\n'}
+
+	var repl *diatom.Repl
+	var err error
+	if replImagePath != "" {
+		image, readErr := os.ReadFile(replImagePath)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read image %q: %w", replImagePath, readErr)
+		}
+		repl, err = diatom.LoadReplWithOptions(image, writer, vmOptions)
+	} else {
+		repl, err = diatom.NewReplWithOptions(writer, vmOptions)
+		if err == nil {
+			err = repl.Eval(diatom.Stdlib)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &replSession{repl: repl, out: writer, interactive: interactive}, nil
+}
+
+func (s *replSession) prompt() string {
+	if s.repl.Compiling() {
+		return "... "
+	}
+	return "> "
+}
+
+func (s *replSession) printf(format string, args ...any) error {
+	if s.out.last != '\n' {
+		format = "\n" + format
+	}
+
+	_, err := fmt.Fprintf(s.out, format, args...)
+	return err
+}
+
+func (s *replSession) printStack() error {
+	stack := s.repl.VM().DataStack()
+	values := make([]string, len(stack))
+	for i, w := range stack {
+		values[i] = strconv.Itoa(int(w))
+	}
+
+	if len(values) < 1 {
+		return s.printf("<0> ok\n")
+	}
+	return s.printf("<%d> %s ok\n", len(values), strings.Join(values, " "))
+}
+
+func (s *replSession) load(path string) error {
+	code, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read file %q: %w", path, err)
+	}
+
+	return s.repl.Eval(string(code))
+}
+
+func (s *replSession) save(path string) error {
+	image, err := s.repl.Image()
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path, image, 0664); err != nil {
+		return fmt.Errorf("failed to create file %q: %w", path, err)
+	}
+
+	return s.printf("saved %d bytes to %q\n", len(image), path)
+}
+
+// handle evaluates a single line and reports if the session should
+// end.
+func (s *replSession) handle(line string) (bool, error) {
+	fields := strings.Fields(line)
+	command := ""
+	if len(fields) > 0 {
+		command = fields[0]
+	}
+
+	var err error
+	switch command {
+	case ".quit":
+		return true, nil
+	case ".help":
+		return false, s.printf("%s\n", replHelp)
+	case ".load", ".save":
+		if len(fields) != 2 {
+			return false, fmt.Errorf("%s requires a single path", command)
+		}
+		if command == ".load" {
+			err = s.load(fields[1])
+		} else {
+			return false, s.save(fields[1])
+		}
+	default:
+		err = s.repl.Eval(line)
+	}
+
+	if errors.Is(err, diatom.ErrProgramFinished) {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if s.interactive && !s.repl.Compiling() {
+		return false, s.printStack()
+	}
+	return false, nil
+}
+
+func (s *replSession) run(readLine func(prompt string) (string, error)) error {
+	for {
+		line, err := readLine(s.prompt())
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		quit, err := s.handle(line)
+		if err != nil {
+			if err := s.printf("error: %v\n", err); err != nil {
+				return err
+			}
+		}
+		if quit {
+			return nil
+		}
+	}
+}
+
+func runRepl(files []string) error {
+	fd := int(os.Stdin.Fd())
+	interactive := term.IsTerminal(fd)
+
+	var out io.Writer = os.Stdout
+	var readLine func(prompt string) (string, error)
+	if interactive {
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("failed to configure terminal: %w", err)
+		}
+		defer term.Restore(fd, state)
+
+		terminal := term.NewTerminal(struct {
+			io.Reader
+			io.Writer
+		}{os.Stdin, os.Stdout}, "")
+		out = terminal
+		readLine = func(prompt string) (string, error) {
+			terminal.SetPrompt(prompt)
+			return terminal.ReadLine()
+		}
+	} else {
+		scanner := bufio.NewScanner(os.Stdin)
+		readLine = func(string) (string, error) {
+			if !scanner.Scan() {
+				if err := scanner.Err(); err != nil {
+					return "", err
+				}
+				return "", io.EOF
+			}
+			return scanner.Text(), nil
+		}
+	}
+
+	session, err := newReplSession(out, interactive)
+	if err != nil {
+		return err
+	}
+
+	for _, path := range files {
+		if err := session.load(path); err != nil {
+			return fmt.Errorf("failed to load %q: %w", path, err)
+		}
+	}
+
+	return session.run(readLine)
+}
+
+var replCmd = &cobra.Command{
+	Use:   "repl [files...]",
+	Short: "Starts an interactive Diatom REPL",
+	Long: `repl starts an interactive read-eval-print-loop (REPL) on the v2 VM.
+
+A fresh REPL starts with the standard library (words like 'times', 'recurse'
+and 'factorial') and evaluates the given .dia files before the first prompt. After every line
+the current data stack is displayed. If stdin is not a terminal, the lines are
+read from it without any prompts so programs can be piped into the REPL. The
+'.s' word prints the data stack in both cases.
+
+The current dictionary can be saved with '.save <path>' and restored with
+--image. Type '.help' inside the REPL for a list of commands.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		if err := runRepl(args); err != nil {
+			log.Fatal(err)
+		}
+	},
+}
+
+func init() {
+	// The root command starts the REPL as well and takes the same flags.
+	for _, cmd := range []*cobra.Command{replCmd, rootCmd} {
+		cmd.Flags().StringVar(&replImagePath, "image", "",
+			"Path to a .dopc image saved with '.save' to continue from.")
+		addVMOptionFlags(cmd)
+	}
+	rootCmd.AddCommand(replCmd)
+}
@@ -2,45 +2,172 @@ package cmd
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
-	"strings"
+	"time"
 
-	"github.com/eldelto/core/internal/diatom"
+	"github.com/eldelto/core/internal/diatom/v2"
 	"github.com/spf13/cobra"
 )
 
-var filePath string
+var (
+	filePath      string
+	vmOptions     diatom.VMOptions
+	sandboxPath   string
+	randomSeed    uint64
+	profilePath   string
+	profileFormat string
+)
 
-func loadProgram(path string) ([]byte, error) {
-	var program []byte
-	needsAssembly := false
+// loadProgram reads a .dopc file as is or assembles a .dasm file
+// including its labels and source map.
+func loadProgram(path string) (diatom.Program, error) {
+	in, err := os.ReadFile(path)
+	if err != nil {
+		return diatom.Program{}, fmt.Errorf("failed to read file %q: %w", path, err)
+	}
 
-	switch {
-	case strings.HasSuffix(path, ".dopc"):
-	case strings.HasSuffix(path, ".dasm"):
-		needsAssembly = true
+	switch filepath.Ext(path) {
+	case ".dopc":
+		return diatom.Program{Dopc: in}, nil
+	case ".dasm":
+		return diatom.AssembleProgram(filepath.Base(path), bytes.NewBuffer(in))
 	default:
-		return nil, fmt.Errorf("%q is not a supported file format", filepath.Ext(path))
+		return diatom.Program{}, fmt.Errorf("%q is not a supported file format", filepath.Ext(path))
 	}
+}
 
-	in, err := os.ReadFile(path)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read file %q: %w", path, err)
+// addVMOptionFlags registers the flags that configure the VM's
+// memory and stack sizes on cmd.
+func addVMOptionFlags(cmd *cobra.Command) {
+	defaults := diatom.DefaultVMOptions()
+	cmd.Flags().IntVar(&vmOptions.MemorySize, "memory-size", defaults.MemorySize,
+		"Size of the VM's memory in bytes.")
+	cmd.Flags().IntVar(&vmOptions.DataStackSize, "stack-size", defaults.DataStackSize,
+		"Depth of the data stack.")
+	cmd.Flags().IntVar(&vmOptions.ReturnStackSize, "return-stack-size", defaults.ReturnStackSize,
+		"Depth of the return stack.")
+	cmd.Flags().IntVar(&vmOptions.TraceLength, "trace-length", defaults.TraceLength,
+		"Number of executed instructions shown in stack traces (-1 disables tracing).")
+}
+
+// registerStandardExtensions makes the extensions wrapped by the
+// preamble available to vm. The file extension is only registered if
+// --sandbox is set, the returned file system is nil otherwise and has
+// to be closed once the program finished.
+func registerStandardExtensions(vm *diatom.VM) (*diatom.FileSystem, error) {
+	seed := randomSeed
+	if seed == 0 {
+		seed = uint64(time.Now().UnixNano())
+	}
+
+	extensions := []diatom.Extension{
+		diatom.NewClockExtension(),
+		diatom.NewRandomExtension(seed),
+		diatom.FormatExtension,
+		diatom.NewTaskExtension(),
 	}
-	program = in
 
-	if needsAssembly {
-		_, _, dopc, err := diatom.Assemble(bytes.NewBuffer(in))
+	var fs *diatom.FileSystem
+	if sandboxPath != "" {
+		var err error
+		fs, err = diatom.OpenFileSystem(sandboxPath)
 		if err != nil {
 			return nil, err
 		}
-		program = dopc
+		extensions = append(extensions, fs.Extension())
+	}
+
+	for _, ext := range extensions {
+		if err := vm.RegisterExtension(ext); err != nil {
+			if fs != nil {
+				fs.Close()
+			}
+			return nil, err
+		}
+	}
+
+	return fs, nil
+}
+
+// writeProfile writes the profile of vm in the format given by
+// --profile-format.
+func writeProfile(vm *diatom.VM, symbols diatom.Symbols) error {
+	f, err := os.Create(profilePath)
+	if err != nil {
+		return fmt.Errorf("failed to create profile %q: %w", profilePath, err)
+	}
+	defer f.Close()
+
+	switch profileFormat {
+	case "text":
+		err = vm.Profile().WriteReport(f, symbols)
+	case "pprof":
+		err = vm.Profile().WritePprof(f, symbols)
+	default:
+		return fmt.Errorf("unknown profile format %q", profileFormat)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write profile %q: %w", profilePath, err)
+	}
+
+	return f.Close()
+}
+
+// Exit codes of the vm command for the different errors of the VM.
+const (
+	exitFailure            = 1
+	exitAbort              = 2
+	exitStackOverflow      = 3
+	exitStackUnderflow     = 4
+	exitMemoryAccess       = 5
+	exitDivisionByZero     = 6
+	exitUnknownInstruction = 7
+	exitExtension          = 8
+	exitDeadlock           = 9
+)
+
+func exitCode(err error) int {
+	switch {
+	case errors.As(err, new(*diatom.AbortError)):
+		return exitAbort
+	case errors.As(err, new(*diatom.StackOverflowError)):
+		return exitStackOverflow
+	case errors.As(err, new(*diatom.StackUnderflowError)):
+		return exitStackUnderflow
+	case errors.As(err, new(*diatom.MemoryAccessError)):
+		return exitMemoryAccess
+	case errors.As(err, new(*diatom.DivisionByZeroError)):
+		return exitDivisionByZero
+	case errors.As(err, new(*diatom.UnknownInstructionError)):
+		return exitUnknownInstruction
+	case errors.As(err, new(*diatom.UnknownExtensionError)),
+		errors.As(err, new(*diatom.ExtensionError)):
+		return exitExtension
+	case errors.As(err, new(*diatom.DeadlockError)):
+		return exitDeadlock
+	default:
+		return exitFailure
+	}
+}
+
+// exitWithError reports err and exits with the matching exit code.
+// Programs that aborted leave a core dump behind.
+func exitWithError(vm *diatom.VM, err error) {
+	log.Print(err)
+
+	if errors.As(err, new(*diatom.AbortError)) {
+		if err := vm.CoreDump(); err != nil {
+			log.Print(err)
+		} else {
+			log.Printf("core dumped to %q", diatom.CoreDumpFile)
+		}
 	}
 
-	return program, err
+	os.Exit(exitCode(err))
 }
 
 var vmCmd = &cobra.Command{
@@ -51,31 +178,66 @@ var vmCmd = &cobra.Command{
 executing once it completed to read the code. The DASM code gets assembled on the fly and
 the resulting instructions are then executed by the VM.
 
+The memory and stack sizes of the VM can be changed with the respective flags.
+
+The standard extensions for time, random numbers, string formatting and tasks
+are registered with the VM. The file extension is only registered if a
+directory is given by --sandbox and files can only be accessed inside of it.
+Without --sandbox the file words fail with an unknown extension error.
+
+With --profile the executed instructions are counted per address and codeword
+and written to the given file once the program finished. Codewords are taken
+from the assembler labels or, for .dopc files, from the dictionary headers.
+
+If the program fails, vm exits with a code describing the error:
+
+  1 - any other error
+  2 - abort (the memory is written to core-dump.dopc)
+  3 - stack overflow
+  4 - stack underflow
+  5 - out of bound memory access
+  6 - division by zero
+  7 - unknown instruction
+  8 - unknown or failing extension function
+  9 - deadlock, all tasks wait for messages
+
 Please see vm -h for more details.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		var program []byte
+		var program diatom.Program
+		var err error
 		if filePath != "" {
-			dopc, err := loadProgram(filePath)
-			if err != nil {
-				log.Fatal(err)
-			}
-			program = dopc
+			program, err = loadProgram(filePath)
 		} else {
-			_, _, dopc, err := diatom.Assemble(os.Stdin)
-			if err != nil {
-				log.Fatal(err)
-			}
-			program = dopc
+			program, err = diatom.AssembleProgram("stdin", os.Stdin)
+		}
+		if err != nil {
+			log.Fatal(err)
 		}
 
-		vm, err := diatom.NewDefaultVM(program)
+		vmOptions.Profile = profilePath != ""
+		vm, err := diatom.NewVMWithOptions(program.Dopc, os.Stdin, os.Stdout, vmOptions)
 		if err != nil {
 			log.Fatal(err)
 		}
+		vm.SetSourceMap(program.SourceMap)
 
-		if err := vm.Execute(); err != nil {
+		fs, err := registerStandardExtensions(vm)
+		if err != nil {
 			log.Fatal(err)
 		}
+
+		execErr := vm.Execute()
+		if fs != nil {
+			fs.Close()
+		}
+		if vmOptions.Profile {
+			if err := writeProfile(vm, program.Labels); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if execErr != nil {
+			exitWithError(vm, execErr)
+		}
 	},
 }
 
@@ -86,5 +248,14 @@ func init() {
 If path points to a .dopc file it will be executed directly, if it points to a
 .dasm file it will assemble it first (see diatom assemble -h for more
 information).`)
+	addVMOptionFlags(vmCmd)
+	vmCmd.Flags().StringVar(&sandboxPath, "sandbox", "",
+		"Directory the file extension is restricted to (no file access if empty).")
+	vmCmd.Flags().Uint64Var(&randomSeed, "seed", 0,
+		"Seed of the random extension (0 picks a seed based on the current time).")
+	vmCmd.Flags().StringVar(&profilePath, "profile", "",
+		"Path to write an execution profile to.")
+	vmCmd.Flags().StringVar(&profileFormat, "profile-format", "text",
+		"Format of the profile, either 'text' or 'pprof' (see go tool pprof).")
 	rootCmd.AddCommand(vmCmd)
 }
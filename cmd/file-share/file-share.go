@@ -7,7 +7,9 @@ import (
 	"os"
 
 	"strconv"
+	"time"
 
+	"github.com/eldelto/core/backup"
 	"github.com/eldelto/core/internal/conf"
 	"github.com/eldelto/core/internal/fileshare"
 	"github.com/eldelto/core/storage"
@@ -16,6 +18,7 @@ import (
 	lweb "github.com/eldelto/core/internal/legacyweb"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/go-co-op/gocron/v2"
 	"go.etcd.io/bbolt"
 )
 
@@ -33,6 +36,10 @@ func main() {
 	smtpHost := conf.EnvVarWithDefault("SMTP_HOST", "localhost")
 	smtpPort := conf.IntEnvVarWithDefault("SMTP_PORT", 587)
 
+	backupToken := conf.EnvVarWithDefault("BACKUP_TOKEN", "")
+	backupDir := conf.EnvVarWithDefault("BACKUP_DIR", "")
+	compactionRetention := conf.IntEnvVarWithDefault("COMPACTION_RETENTION_DAYS", 0)
+
 	// Services
 	bolt, err := bbolt.Open(dbPath, 0600, nil)
 	if err != nil {
@@ -48,6 +55,26 @@ func main() {
 		Name: "chunked-file",
 	})
 
+	// Schedulers
+	scheduler, err := gocron.NewScheduler(gocron.WithLocation(time.UTC))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer scheduler.Shutdown()
+
+	if compactionRetention > 0 {
+		retention := time.Duration(compactionRetention) * 24 * time.Hour
+		if err := db.ScheduleCompaction(scheduler, 24*time.Hour, retention); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if backupDir != "" {
+		if err := backup.ScheduleSnapshots(scheduler, bolt, backupDir, 24*time.Hour, 7); err != nil {
+			log.Fatal(err)
+		}
+	}
+	scheduler.Start()
+
 	root, err := os.OpenRoot(workdir)
 	if err != nil {
 		log.Fatalf("failed to open workdir: %v", err)
@@ -79,6 +106,14 @@ func main() {
 
 	r.With(auth.Middleware).Mount("/file", fileshare.NewDirectoryController(service))
 
+	if backupToken != "" {
+		backupModule, err := backup.NewModule(bolt, backupToken)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.Mount("/backup", backupModule)
+	}
+
 	http.Handle("/", r)
 
 	log.Printf("File-Share listening on localhost:%d with host %q", port, host)
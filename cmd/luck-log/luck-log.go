@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/eldelto/core/backup"
 	"github.com/eldelto/core/internal/conf"
 	web "github.com/eldelto/core/internal/legacyweb"
 	"github.com/eldelto/core/internal/lucklog"
@@ -23,6 +24,8 @@ const (
 	smtpPasswordEnv = "SMTP_PASSWORD"
 	smtpHostEnv     = "SMTP_HOST"
 	smtpPortEnv     = "SMTP_PORT"
+	backupTokenEnv  = "BACKUP_TOKEN"
+	backupDirEnv    = "BACKUP_DIR"
 
 	dbPath = "luck-log.db"
 )
@@ -37,6 +40,9 @@ func main() {
 	smtpHost := conf.EnvVarWithDefault(smtpHostEnv, "localhost")
 	smtpPort := conf.IntEnvVarWithDefault(smtpPortEnv, 587)
 
+	backupToken := conf.EnvVarWithDefault(backupTokenEnv, "")
+	backupDir := conf.EnvVarWithDefault(backupDirEnv, "")
+
 	// Services
 	db, err := bbolt.Open(dbPath, 0600, nil)
 	if err != nil {
@@ -77,6 +83,11 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	if backupDir != "" {
+		if err := backup.ScheduleSnapshots(scheduler, db, backupDir, 24*time.Hour, 7); err != nil {
+			log.Fatal(err)
+		}
+	}
 	scheduler.Start()
 
 	r := chi.NewRouter()
@@ -94,6 +105,14 @@ func main() {
 		r.Mount("/log-entries", server.NewLogbookController(service).Handler())
 	})
 
+	if backupToken != "" {
+		backupModule, err := backup.NewModule(db, backupToken)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.Mount("/backup", backupModule)
+	}
+
 	http.Handle("/", r)
 
 	log.Printf("Luck-Log listening on localhost:%d with host %q", port, host)
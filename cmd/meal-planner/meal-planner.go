@@ -6,12 +6,15 @@ import (
 	"net/http"
 	"net/smtp"
 	"strconv"
+	"time"
 
+	"github.com/eldelto/core/backup"
 	"github.com/eldelto/core/internal/conf"
 	web "github.com/eldelto/core/internal/legacyweb"
 	"github.com/eldelto/core/internal/mealplanner"
 	"github.com/eldelto/core/internal/mealplanner/server"
 	"github.com/go-chi/chi/v5"
+	"github.com/go-co-op/gocron/v2"
 	"go.etcd.io/bbolt"
 )
 
@@ -22,6 +25,8 @@ const (
 	smtpPasswordEnv = "SMTP_PASSWORD"
 	smtpHostEnv     = "SMTP_HOST"
 	smtpPortEnv     = "SMTP_PORT"
+	backupTokenEnv  = "BACKUP_TOKEN"
+	backupDirEnv    = "BACKUP_DIR"
 
 	dbPath = "meal-planner.db"
 )
@@ -36,6 +41,9 @@ func main() {
 	smtpHost := conf.EnvVarWithDefault(smtpHostEnv, "localhost")
 	smtpPort := conf.IntEnvVarWithDefault(smtpPortEnv, 587)
 
+	backupToken := conf.EnvVarWithDefault(backupTokenEnv, "")
+	backupDir := conf.EnvVarWithDefault(backupDirEnv, "")
+
 	// Services
 	db, err := bbolt.Open(dbPath, 0600, nil)
 	if err != nil {
@@ -65,6 +73,20 @@ func main() {
 
 	auth.TokenCallback = service.SendLoginEmail
 
+	// Schedulers
+	scheduler, err := gocron.NewScheduler(gocron.WithLocation(time.UTC))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer scheduler.Shutdown()
+
+	if backupDir != "" {
+		if err := backup.ScheduleSnapshots(scheduler, db, backupDir, 24*time.Hour, 7); err != nil {
+			log.Fatal(err)
+		}
+	}
+	scheduler.Start()
+
 	r := chi.NewRouter()
 
 	// Controllers
@@ -80,6 +102,14 @@ func main() {
 		r.Mount("/shares", server.NewShareController(service).Handler())
 	})
 
+	if backupToken != "" {
+		backupModule, err := backup.NewModule(db, backupToken)
+		if err != nil {
+			log.Fatal(err)
+		}
+		r.Mount("/backup", backupModule)
+	}
+
 	http.Handle("/", r)
 
 	log.Printf("Meal-Planner listening on localhost:%d with host %q", port, host)
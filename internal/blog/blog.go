@@ -303,6 +303,12 @@ func TextNodeToHtml(t TextNode) string {
 		content = replaceInlineElements(content)
 		b.WriteString(tagged(content, "p"))
 	case *CodeBlock:
+		if t.runnable() {
+			b.WriteString(`<diatom-playground><pre>`)
+			b.WriteString(content)
+			b.WriteString(`</pre></diatom-playground>`)
+			break
+		}
 		b.WriteString(`<div class="code-block"><pre>`)
 		b.WriteString(content)
 		b.WriteString(`</pre></div>`)
@@ -337,6 +343,21 @@ func TextNodeToHtml(t TextNode) string {
 	return b.String()
 }
 
+// hasRunnableCode reports if any of the nodes contains a code block
+// that is rendered as diatom playground.
+func hasRunnableCode(nodes []TextNode) bool {
+	for _, node := range nodes {
+		if cb, ok := node.(*CodeBlock); ok && cb.runnable() {
+			return true
+		}
+		if hasRunnableCode(node.GetChildren()) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // TODO: Move to a template?
 func writeTableOfContents(a *Article) string {
 	b := strings.Builder{}
@@ -390,5 +411,9 @@ func ArticleToHtml(a Article) string {
 	}
 	b.WriteString("</div>")
 
+	if hasRunnableCode(a.Children) {
+		b.WriteString(`<script src="/diatom/diatom.js" defer></script>`)
+	}
+
 	return b.String()
 }
@@ -59,3 +59,21 @@ func TestArticlesToHtml(t *testing.T) {
 	AssertStringContains(t, `<source src="/dynamic/assets/riff1.mp3" type="audio/mpeg">`,
 		html, "link to music")
 }
+
+func TestDiatomCodeBlockToHtml(t *testing.T) {
+	diatom := &CodeBlock{Language: "diatom", Content: "\nconst 65 emit exit"}
+	bash := &CodeBlock{Language: "bash", Content: "\necho <hi>"}
+
+	html := ArticleToHtml(Article{
+		Title:    "Diatom",
+		Children: []TextNode{&Headline{Content: "Run", Level: 3, Children: []TextNode{diatom, bash}}},
+	})
+	AssertStringContains(t, "<diatom-playground><pre>\nconst 65 emit exit</pre></diatom-playground>",
+		html, "diatom code block")
+	AssertStringContains(t, `<div class="code-block"><pre>`+"\necho &lt;hi&gt;</pre></div>",
+		html, "bash code block")
+	AssertStringContains(t, `<script src="/diatom/diatom.js" defer></script>`, html, "diatom script")
+
+	html = ArticleToHtml(Article{Title: "No Diatom", Children: []TextNode{bash}})
+	AssertEquals(t, false, strings.Contains(html, "diatom.js"), "script without diatom code")
+}
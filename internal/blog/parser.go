@@ -105,6 +105,13 @@ func (cb *CodeBlock) GetChildren() []TextNode {
 	return nil
 }
 
+// runnable reports if the code block holds diatom assembly
+// ('#+begin_src diatom') that can be run in the browser.
+func (cb *CodeBlock) runnable() bool {
+	fields := strings.Fields(cb.Language)
+	return len(fields) > 0 && fields[0] == "diatom"
+}
+
 type CommentBlock struct {
 	Content string
 }
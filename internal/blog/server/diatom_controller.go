@@ -1,19 +1,27 @@
 package server
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sync"
 
-	"github.com/eldelto/core/internal/diatom"
 	"github.com/eldelto/core/internal/diatom/diatomjs"
+	"github.com/eldelto/core/internal/diatom/v2"
 	web "github.com/eldelto/core/internal/legacyweb"
 )
 
+var playgroundTemplate = templater.GetP("playground.html")
+
 func NewDiatomController() *web.Controller {
 	return &web.Controller{
 		BasePath: "/diatom",
 		Handlers: map[web.Endpoint]web.Handler{
-			{Method: http.MethodGet, Path: "/repl.dopc"}: getCompiledRepl(),
-			{Method: http.MethodGet, Path: "/diatom.js"}: getDiatomJs(),
+			{Method: http.MethodGet, Path: "/repl.dopc"}:  getCompiledRepl(),
+			{Method: http.MethodGet, Path: "/diatom.js"}:  getDiatomJs(),
+			{Method: http.MethodGet, Path: "/playground"}: getPlayground(),
+			{Method: http.MethodPost, Path: "/assemble"}:  postAssemble(),
 		},
 		Middleware: []web.Middleware{
 			web.CachingMiddleware(3600),
@@ -21,10 +29,25 @@ func NewDiatomController() *web.Controller {
 	}
 }
 
+// replDopc is the REPL for the JavaScript VM which implements the
+// instruction set of the v2 VM.
+var replDopc = sync.OnceValues(func() ([]byte, error) {
+	program, err := diatom.AssembleRepl(diatom.VMOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assemble diatom REPL: %w", err)
+	}
+	return program.Dopc, nil
+})
+
 func getCompiledRepl() web.Handler {
 	return func(w http.ResponseWriter, r *http.Request) error {
+		dopc, err := replDopc()
+		if err != nil {
+			return err
+		}
+
 		w.Header().Add(web.ContentTypeHeader, web.ContentTypeOctetStream)
-		_, err := w.Write(diatom.ReplDopc)
+		_, err = w.Write(dopc)
 		return err
 	}
 }
@@ -36,3 +59,47 @@ func getDiatomJs() web.Handler {
 		return err
 	}
 }
+
+func getPlayground() web.Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		w.Header().Add(web.ContentTypeHeader, web.ContentTypeHTML)
+		return playgroundTemplate.Execute(w, nil)
+	}
+}
+
+// maxSourceSize limits the assembly source accepted by the assemble
+// endpoint.
+const maxSourceSize = 64 * 1024
+
+type assembleResponse struct {
+	Dins  string `json:"dins,omitempty"`
+	Dopc  []byte `json:"dopc,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+func writeAssembleResponse(w http.ResponseWriter, status int, response assembleResponse) error {
+	w.Header().Add(web.ContentTypeHeader, web.ContentTypeJSON)
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(response)
+}
+
+// postAssemble assembles the diatom assembly in the request body and
+// responds with the resolved instructions (.dins) and the machine
+// code (.dopc, base64 encoded) as JSON. Assembly errors are returned
+// in the error field with status 400.
+func postAssemble() web.Handler {
+	return func(w http.ResponseWriter, r *http.Request) error {
+		program, err := diatom.AssembleProgram("playground.dasm",
+			http.MaxBytesReader(w, r.Body, maxSourceSize))
+		if err != nil {
+			status := http.StatusBadRequest
+			if errors.As(err, new(*http.MaxBytesError)) {
+				status = http.StatusRequestEntityTooLarge
+			}
+			return writeAssembleResponse(w, status, assembleResponse{Error: err.Error()})
+		}
+
+		return writeAssembleResponse(w, http.StatusOK,
+			assembleResponse{Dins: program.Dins, Dopc: program.Dopc})
+	}
+}
@@ -1,8 +1,6 @@
 package boltutil
 
 import (
-	"bytes"
-	"encoding/gob"
 	"fmt"
 
 	"github.com/eldelto/core/internal/errs"
@@ -40,13 +38,18 @@ func Find[T any](db *bbolt.DB, bucketName, key string) (T, error) {
 			return fmt.Errorf("get bucket %q", bucketName)
 		}
 
+		codec, err := CodecOf(tx, bucketName)
+		if err != nil {
+			return err
+		}
+
 		value := bucket.Get([]byte(key))
 		if value == nil {
 			return fmt.Errorf("get value - bucket=%q, key=%q: %w",
 				bucketName, key, errs.NotFound(key, bucketName))
 		}
 
-		if err := gob.NewDecoder(bytes.NewBuffer(value)).Decode(&result); err != nil {
+		if err := codec.Unmarshal(value, &result); err != nil {
 			return fmt.Errorf("decode value - bucket=%q, key=%q: %w",
 				bucketName, key, err)
 		}
@@ -70,10 +73,15 @@ func FindFirst[T any](db *bbolt.DB, bucketName string, f func(T) bool) (T, error
 			return fmt.Errorf("get bucket %q", bucketName)
 		}
 
+		codec, err := CodecOf(tx, bucketName)
+		if err != nil {
+			return err
+		}
+
 		found := false
 		cursor := bucket.Cursor()
 		for key, value := cursor.Next(); key != nil; key, value = cursor.Next() {
-			if err := gob.NewDecoder(bytes.NewBuffer(value)).Decode(&result); err != nil {
+			if err := codec.Unmarshal(value, &result); err != nil {
 				return fmt.Errorf("decode value - bucket=%q, key=%q: %w",
 					bucketName, key, err)
 			}
@@ -106,10 +114,15 @@ func List[T any](db *bbolt.DB, bucketName string) (map[string]T, error) {
 			return fmt.Errorf("get bucket %q", bucketName)
 		}
 
+		codec, err := CodecOf(tx, bucketName)
+		if err != nil {
+			return err
+		}
+
 		c := bucket.Cursor()
 		for k, v := c.First(); k != nil; k, v = c.Next() {
 			var result T
-			if err := gob.NewDecoder(bytes.NewBuffer(v)).Decode(&result); err != nil {
+			if err := codec.Unmarshal(v, &result); err != nil {
 				return fmt.Errorf("decode value - bucket=%q, key=%q: %w",
 					bucketName, k, err)
 			}
@@ -133,13 +146,18 @@ func Store[T any](db *bbolt.DB, bucketName, key string, value T) error {
 			return fmt.Errorf("get bucket - bucket=%q", bucketName)
 		}
 
-		buffer := bytes.Buffer{}
-		if err := gob.NewEncoder(&buffer).Encode(value); err != nil {
+		codec, err := CodecOf(tx, bucketName)
+		if err != nil {
+			return err
+		}
+
+		data, err := codec.Marshal(value)
+		if err != nil {
 			return fmt.Errorf("encode value - bucket=%q, key=%q: %w",
 				bucketName, key, err)
 		}
 
-		if err := bucket.Put([]byte(key), buffer.Bytes()); err != nil {
+		if err := bucket.Put([]byte(key), data); err != nil {
 			return fmt.Errorf("persist value - bucket=%q, key=%q: %w",
 				bucketName, key, err)
 		}
@@ -160,10 +178,15 @@ func Update[T any](db *bbolt.DB, bucketName, key string, f func(old T) T) error
 			return fmt.Errorf("get bucket - bucket=%q", bucketName)
 		}
 
+		codec, err := CodecOf(tx, bucketName)
+		if err != nil {
+			return err
+		}
+
 		var oldValue T
 		byteValue := bucket.Get([]byte(key))
 		if byteValue != nil {
-			if err := gob.NewDecoder(bytes.NewBuffer(byteValue)).Decode(&oldValue); err != nil {
+			if err := codec.Unmarshal(byteValue, &oldValue); err != nil {
 				return fmt.Errorf("decode value - bucket=%q, key=%q: %w",
 					bucketName, key, err)
 			}
@@ -171,13 +194,13 @@ func Update[T any](db *bbolt.DB, bucketName, key string, f func(old T) T) error
 
 		newValue := f(oldValue)
 
-		buffer := bytes.Buffer{}
-		if err := gob.NewEncoder(&buffer).Encode(newValue); err != nil {
+		data, err := codec.Marshal(newValue)
+		if err != nil {
 			return fmt.Errorf("encode value - bucket=%q, key=%q: %w",
 				bucketName, key, err)
 		}
 
-		if err := bucket.Put([]byte(key), buffer.Bytes()); err != nil {
+		if err := bucket.Put([]byte(key), data); err != nil {
 			return fmt.Errorf("persist value - bucket=%q, key=%q: %w",
 				bucketName, key, err)
 		}
@@ -0,0 +1,45 @@
+package boltutil
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// cborCodec encodes values as CBOR (RFC 8949) which tools outside of
+// Go can read as well. Struct fields are stored by name so fields can
+// be added or removed without breaking existing data. Maps are sorted
+// to make the encoding deterministic and times are stored as RFC 3339
+// strings so they keep their precision.
+type cborCodec struct {
+	enc cbor.EncMode
+	dec cbor.DecMode
+}
+
+func newCBORCodec() cborCodec {
+	encOpts := cbor.CoreDetEncOptions()
+	encOpts.Time = cbor.TimeRFC3339Nano
+	enc, err := encOpts.EncMode()
+	if err != nil {
+		panic(fmt.Errorf("cbor codec: %w", err))
+	}
+
+	dec, err := cbor.DecOptions{}.DecMode()
+	if err != nil {
+		panic(fmt.Errorf("cbor codec: %w", err))
+	}
+
+	return cborCodec{enc: enc, dec: dec}
+}
+
+func (c cborCodec) Name() string {
+	return "cbor"
+}
+
+func (c cborCodec) Marshal(v any) ([]byte, error) {
+	return c.enc.Marshal(v)
+}
+
+func (c cborCodec) Unmarshal(data []byte, v any) error {
+	return c.dec.Unmarshal(data, v)
+}
@@ -0,0 +1,247 @@
+package boltutil
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"go.etcd.io/bbolt"
+)
+
+// codecBucket holds the name of the codec every bucket is encoded
+// with keyed by the bucket's name. Buckets without an entry predate
+// codecs and are encoded with gob.
+const codecBucket = "_codec"
+
+// Codec converts values to and from their persisted representation.
+type Codec interface {
+	Name() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+type gobCodec struct{}
+
+func (c gobCodec) Name() string {
+	return "gob"
+}
+
+func (c gobCodec) Marshal(v any) ([]byte, error) {
+	buffer := bytes.Buffer{}
+	if err := gob.NewEncoder(&buffer).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+func (c gobCodec) Unmarshal(data []byte, v any) error {
+	return gob.NewDecoder(bytes.NewBuffer(data)).Decode(v)
+}
+
+type jsonCodec struct{}
+
+func (c jsonCodec) Name() string {
+	return "json"
+}
+
+func (c jsonCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (c jsonCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+var (
+	Gob  Codec = gobCodec{}
+	JSON Codec = jsonCodec{}
+	CBOR Codec = newCBORCodec()
+)
+
+var (
+	codecsMutex sync.RWMutex
+	codecs      = map[string]Codec{
+		Gob.Name():  Gob,
+		JSON.Name(): JSON,
+		CBOR.Name(): CBOR,
+	}
+)
+
+// RegisterCodec makes a custom codec known so buckets encoded with it
+// can be read.
+func RegisterCodec(c Codec) {
+	codecsMutex.Lock()
+	defer codecsMutex.Unlock()
+
+	codecs[c.Name()] = c
+}
+
+func CodecByName(name string) (Codec, error) {
+	codecsMutex.RLock()
+	defer codecsMutex.RUnlock()
+
+	c, ok := codecs[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown codec %q", name)
+	}
+
+	return c, nil
+}
+
+func lookupCodec(tx *bbolt.Tx, bucketName string) (Codec, bool, error) {
+	bucket := tx.Bucket([]byte(codecBucket))
+	if bucket == nil {
+		return nil, false, nil
+	}
+
+	name := bucket.Get([]byte(bucketName))
+	if name == nil {
+		return nil, false, nil
+	}
+
+	c, err := CodecByName(string(name))
+	if err != nil {
+		return nil, false, fmt.Errorf("codec of bucket %q: %w", bucketName, err)
+	}
+
+	return c, true, nil
+}
+
+// DetectCodec returns the codec recorded for the given bucket. Without
+// a recorded codec, buckets already containing data predate codecs and
+// are gob encoded while empty ones use fallback.
+func DetectCodec(tx *bbolt.Tx, bucketName string, fallback Codec) (Codec, error) {
+	c, ok, err := lookupCodec(tx, bucketName)
+	if err != nil {
+		return nil, err
+	}
+	if ok {
+		return c, nil
+	}
+
+	if bucket := tx.Bucket([]byte(bucketName)); bucket != nil {
+		if k, _ := bucket.Cursor().First(); k != nil {
+			return Gob, nil
+		}
+	}
+
+	return fallback, nil
+}
+
+// CodecOf returns the codec the given bucket is encoded with.
+func CodecOf(tx *bbolt.Tx, bucketName string) (Codec, error) {
+	return DetectCodec(tx, bucketName, Gob)
+}
+
+// SetCodec records the codec of the given bucket without touching its
+// data.
+func SetCodec(tx *bbolt.Tx, bucketName string, c Codec) error {
+	bucket, err := tx.CreateBucketIfNotExists([]byte(codecBucket))
+	if err != nil {
+		return fmt.Errorf("set codec of bucket %q: %w", bucketName, err)
+	}
+
+	if err := bucket.Put([]byte(bucketName), []byte(c.Name())); err != nil {
+		return fmt.Errorf("set codec of bucket %q: %w", bucketName, err)
+	}
+
+	return nil
+}
+
+// EnsureCodec works like DetectCodec but additionally records the
+// codec if there was none.
+func EnsureCodec(tx *bbolt.Tx, bucketName string, fallback Codec) (Codec, error) {
+	c, ok, err := lookupCodec(tx, bucketName)
+	if err != nil || ok {
+		return c, err
+	}
+
+	c, err = DetectCodec(tx, bucketName, fallback)
+	if err != nil {
+		return nil, err
+	}
+
+	return c, SetCodec(tx, bucketName, c)
+}
+
+// UseCodec selects the codec of a bucket. Buckets already containing
+// data encoded differently need to be converted with ConvertValues.
+func UseCodec(db *bbolt.DB, bucketName string, c Codec) error {
+	return db.Update(func(tx *bbolt.Tx) error {
+		existing, err := EnsureCodec(tx, bucketName, c)
+		if err != nil {
+			return err
+		}
+
+		if existing.Name() != c.Name() {
+			return fmt.Errorf("bucket %q is encoded with codec %q instead of %q",
+				bucketName, existing.Name(), c.Name())
+		}
+		return nil
+	})
+}
+
+// ConvertValues decodes all values of a bucket as T with its current
+// codec and rewrites them with the given one. It returns the number of
+// converted values.
+func ConvertValues[T any](db *bbolt.DB, bucketName string, to Codec) (int, error) {
+	converted := 0
+	err := db.Update(func(tx *bbolt.Tx) error {
+		from, err := CodecOf(tx, bucketName)
+		if err != nil {
+			return err
+		}
+		if from.Name() == to.Name() {
+			return nil
+		}
+
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return fmt.Errorf("get bucket %q", bucketName)
+		}
+
+		updates := map[string][]byte{}
+		err = bucket.ForEach(func(k, v []byte) error {
+			if v == nil {
+				return nil
+			}
+
+			var value T
+			if err := from.Unmarshal(v, &value); err != nil {
+				return fmt.Errorf("decode value - bucket=%q, key=%q: %w",
+					bucketName, k, err)
+			}
+
+			data, err := to.Marshal(value)
+			if err != nil {
+				return fmt.Errorf("encode value - bucket=%q, key=%q: %w",
+					bucketName, k, err)
+			}
+
+			updates[string(k)] = data
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		for k, v := range updates {
+			if err := bucket.Put([]byte(k), v); err != nil {
+				return fmt.Errorf("persist value - bucket=%q, key=%q: %w",
+					bucketName, k, err)
+			}
+		}
+		converted = len(updates)
+
+		return SetCodec(tx, bucketName, to)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("convert bucket %q to codec %q: %w",
+			bucketName, to.Name(), err)
+	}
+
+	return converted, nil
+}
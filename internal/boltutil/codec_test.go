@@ -0,0 +1,145 @@
+package boltutil_test
+
+import (
+	"encoding/hex"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/eldelto/core/internal/boltutil"
+	. "github.com/eldelto/core/internal/testutils"
+	"go.etcd.io/bbolt"
+)
+
+type Embedded struct {
+	Note string
+}
+
+type codecValue struct {
+	Embedded
+	Name    string
+	Count   int
+	Ratio   float64
+	Flags   []bool
+	Data    []byte
+	Tags    map[string]int
+	Created time.Time
+	Parent  *codecValue
+	private int
+}
+
+func newCodecValue() codecValue {
+	return codecValue{
+		Embedded: Embedded{Note: "note"},
+		Name:     "name",
+		Count:    -42,
+		Ratio:    0.5,
+		Flags:    []bool{true, false},
+		Data:     []byte{1, 2, 3},
+		Tags:     map[string]int{"a": 1, "b": 2},
+		Created:  time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		Parent:   &codecValue{Name: "parent"},
+	}
+}
+
+func TestCodecs(t *testing.T) {
+	for _, codec := range []boltutil.Codec{boltutil.Gob, boltutil.JSON, boltutil.CBOR} {
+		t.Run(codec.Name(), func(t *testing.T) {
+			value := newCodecValue()
+			data, err := codec.Marshal(value)
+			AssertNoError(t, err, "codec.Marshal")
+
+			var decoded codecValue
+			err = codec.Unmarshal(data, &decoded)
+			AssertNoError(t, err, "codec.Unmarshal")
+			AssertEquals(t, value, decoded, "decoded value")
+		})
+	}
+}
+
+func TestCBORCodec(t *testing.T) {
+	created := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"int", 100, "1864"},
+		{"negative int", -42, "3829"},
+		{"bool", true, "f5"},
+		{"float", 0.5, "f93800"},
+		{"string", "name", "646e616d65"},
+		{"bytes", []byte{1, 2, 3}, "43010203"},
+		{"slice", []bool{true, false}, "82f5f4"},
+		{"map", map[string]int{"b": 2, "a": 1}, "a2616101616202"},
+		{"time", created, "74323032302d30312d30315430303a30303a30305a"},
+		{"struct", newValue{Name: "a", Score: 10}, "a2644e616d6561616553636f72650a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := boltutil.CBOR.Marshal(tt.value)
+			AssertNoError(t, err, "boltutil.CBOR.Marshal")
+			AssertEquals(t, tt.want, hex.EncodeToString(data), "encoded value")
+
+			decoded := reflect.New(reflect.TypeOf(tt.value))
+			err = boltutil.CBOR.Unmarshal(data, decoded.Interface())
+			AssertNoError(t, err, "boltutil.CBOR.Unmarshal")
+			AssertEquals(t, tt.value, decoded.Elem().Interface(), "decoded value")
+		})
+	}
+}
+
+func TestCBORCodecSkipsUnknownFields(t *testing.T) {
+	data, err := boltutil.CBOR.Marshal(newValue{Name: "a", Score: 10})
+	AssertNoError(t, err, "boltutil.CBOR.Marshal")
+
+	var decoded oldValue
+	err = boltutil.CBOR.Unmarshal(data, &decoded)
+	AssertNoError(t, err, "boltutil.CBOR.Unmarshal")
+	AssertEquals(t, oldValue{Name: "a"}, decoded, "decoded value")
+
+	err = boltutil.CBOR.Unmarshal(data[:len(data)-1], &decoded)
+	AssertError(t, err, "boltutil.CBOR.Unmarshal truncated")
+}
+
+func TestConvertValues(t *testing.T) {
+	dbPath := "boltutil-test.db"
+	db, err := bbolt.Open(dbPath, 0600, nil)
+	AssertNoError(t, err, "bbolt.Open")
+	defer os.Remove(dbPath)
+	defer db.Close()
+
+	err = boltutil.EnsureBucketExists(db, "values")
+	AssertNoError(t, err, "boltutil.EnsureBucketExists")
+	err = boltutil.Store(db, "values", "a", newValue{Name: "a", Score: 1})
+	AssertNoError(t, err, "boltutil.Store")
+
+	err = boltutil.UseCodec(db, "values", boltutil.JSON)
+	AssertError(t, err, "boltutil.UseCodec on gob data")
+
+	converted, err := boltutil.ConvertValues[newValue](db, "values", boltutil.JSON)
+	AssertNoError(t, err, "boltutil.ConvertValues")
+	AssertEquals(t, 1, converted, "converted values")
+
+	err = boltutil.UseCodec(db, "values", boltutil.JSON)
+	AssertNoError(t, err, "boltutil.UseCodec")
+
+	err = boltutil.Store(db, "values", "b", newValue{Name: "b", Score: 2})
+	AssertNoError(t, err, "boltutil.Store")
+
+	values, err := boltutil.List[newValue](db, "values")
+	AssertNoError(t, err, "boltutil.List")
+	AssertEquals(t, map[string]newValue{
+		"a": {Name: "a", Score: 1},
+		"b": {Name: "b", Score: 2},
+	}, values, "values")
+
+	err = db.View(func(tx *bbolt.Tx) error {
+		raw := tx.Bucket([]byte("values")).Get([]byte("b"))
+		AssertEquals(t, `{"Name":"b","Score":2}`, string(raw), "raw JSON value")
+		return nil
+	})
+	AssertNoError(t, err, "db.View")
+}
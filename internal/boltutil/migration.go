@@ -0,0 +1,210 @@
+package boltutil
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.etcd.io/bbolt"
+)
+
+// schemaBucket holds the current schema version of every migrated
+// bucket keyed by the bucket's name.
+const schemaBucket = "_schema"
+
+var errDryRun = errors.New("dry run")
+
+// MigrationFunc migrates the data of the given bucket and returns the
+// number of changed entries.
+type MigrationFunc func(tx *bbolt.Tx, bucketName string) (int, error)
+
+type Migration struct {
+	Version     uint64
+	Description string
+	Func        MigrationFunc
+}
+
+type MigrationResult struct {
+	Version     uint64
+	Description string
+	Changed     int
+}
+
+type MigrationReport struct {
+	Bucket  string
+	From    uint64
+	To      uint64
+	DryRun  bool
+	Results []MigrationResult
+}
+
+func (mr *MigrationReport) String() string {
+	b := strings.Builder{}
+	fmt.Fprintf(&b, "bucket %q: version %d -> %d", mr.Bucket, mr.From, mr.To)
+	if mr.DryRun {
+		b.WriteString(" (dry run)")
+	}
+
+	for _, r := range mr.Results {
+		fmt.Fprintf(&b, "\n  %d: %s - %d entries changed",
+			r.Version, r.Description, r.Changed)
+	}
+
+	return b.String()
+}
+
+func schemaVersion(tx *bbolt.Tx, bucketName string) uint64 {
+	bucket := tx.Bucket([]byte(schemaBucket))
+	if bucket == nil {
+		return 0
+	}
+
+	value := bucket.Get([]byte(bucketName))
+	if len(value) != 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(value)
+}
+
+// SchemaVersion returns the version of the latest migration applied
+// to the given bucket or 0 if none has been applied yet.
+func SchemaVersion(db *bbolt.DB, bucketName string) (uint64, error) {
+	var version uint64
+	err := db.View(func(tx *bbolt.Tx) error {
+		version = schemaVersion(tx, bucketName)
+		return nil
+	})
+
+	return version, err
+}
+
+// Migrate applies all migrations with a version higher than the
+// bucket's current schema version in ascending order within a single
+// transaction. With dryRun set, the transaction is rolled back after
+// all migrations ran so the report shows what would have changed.
+func Migrate(db *bbolt.DB, bucketName string, migrations []Migration, dryRun bool) (MigrationReport, error) {
+	report := MigrationReport{Bucket: bucketName, DryRun: dryRun}
+
+	migrations = slices.Clone(migrations)
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return cmp.Compare(a.Version, b.Version)
+	})
+	for i := 1; i < len(migrations); i++ {
+		if migrations[i].Version == migrations[i-1].Version {
+			return report, fmt.Errorf("migrate bucket %q: duplicate migration version %d",
+				bucketName, migrations[i].Version)
+		}
+	}
+
+	err := db.Update(func(tx *bbolt.Tx) error {
+		report.From = schemaVersion(tx, bucketName)
+		report.To = report.From
+
+		for _, m := range migrations {
+			if m.Version <= report.From {
+				continue
+			}
+
+			changed, err := m.Func(tx, bucketName)
+			if err != nil {
+				return fmt.Errorf("migration %d %q: %w", m.Version, m.Description, err)
+			}
+
+			report.To = m.Version
+			report.Results = append(report.Results, MigrationResult{
+				Version:     m.Version,
+				Description: m.Description,
+				Changed:     changed,
+			})
+		}
+
+		if report.To == report.From {
+			return nil
+		}
+
+		bucket, err := tx.CreateBucketIfNotExists([]byte(schemaBucket))
+		if err != nil {
+			return err
+		}
+		if err := bucket.Put([]byte(bucketName),
+			binary.BigEndian.AppendUint64(nil, report.To)); err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		return report, fmt.Errorf("migrate bucket %q: %w", bucketName, err)
+	}
+
+	return report, nil
+}
+
+// MigrateValues returns a MigrationFunc that decodes every value of a
+// bucket as Old and replaces it with the result of f.
+func MigrateValues[Old, New any](f func(key []byte, old Old) (New, error)) MigrationFunc {
+	return func(tx *bbolt.Tx, bucketName string) (int, error) {
+		bucket := tx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return 0, fmt.Errorf("get bucket %q", bucketName)
+		}
+
+		codec, err := CodecOf(tx, bucketName)
+		if err != nil {
+			return 0, err
+		}
+
+		changed := 0
+		updates := map[string][]byte{}
+		err = bucket.ForEach(func(k, v []byte) error {
+			if v == nil {
+				return nil
+			}
+
+			var oldValue Old
+			if err := codec.Unmarshal(v, &oldValue); err != nil {
+				return fmt.Errorf("decode value - bucket=%q, key=%q: %w",
+					bucketName, k, err)
+			}
+
+			newValue, err := f(k, oldValue)
+			if err != nil {
+				return fmt.Errorf("migrate value - bucket=%q, key=%q: %w",
+					bucketName, k, err)
+			}
+
+			data, err := codec.Marshal(newValue)
+			if err != nil {
+				return fmt.Errorf("encode value - bucket=%q, key=%q: %w",
+					bucketName, k, err)
+			}
+
+			if !bytes.Equal(v, data) {
+				updates[string(k)] = data
+				changed++
+			}
+			return nil
+		})
+		if err != nil {
+			return 0, err
+		}
+
+		// Modifying a bucket while iterating it is not allowed.
+		for k, v := range updates {
+			if err := bucket.Put([]byte(k), v); err != nil {
+				return 0, fmt.Errorf("persist value - bucket=%q, key=%q: %w",
+					bucketName, k, err)
+			}
+		}
+
+		return changed, nil
+	}
+}
@@ -0,0 +1,59 @@
+package boltutil_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/eldelto/core/internal/boltutil"
+	. "github.com/eldelto/core/internal/testutils"
+	"go.etcd.io/bbolt"
+)
+
+type oldValue struct {
+	Name string
+}
+
+type newValue struct {
+	Name  string
+	Score int
+}
+
+func TestMigrateValues(t *testing.T) {
+	dbPath := "boltutil-test.db"
+	db, err := bbolt.Open(dbPath, 0600, nil)
+	AssertNoError(t, err, "bbolt.Open")
+	defer os.Remove(dbPath)
+	defer db.Close()
+
+	err = boltutil.EnsureBucketExists(db, "values")
+	AssertNoError(t, err, "boltutil.EnsureBucketExists")
+	err = boltutil.Store(db, "values", "a", oldValue{Name: "a"})
+	AssertNoError(t, err, "boltutil.Store")
+
+	migrations := []boltutil.Migration{{
+		Version:     1,
+		Description: "add score",
+		Func: boltutil.MigrateValues(func(key []byte, old oldValue) (newValue, error) {
+			return newValue{Name: old.Name, Score: 10}, nil
+		}),
+	}}
+
+	report, err := boltutil.Migrate(db, "values", migrations, true)
+	AssertNoError(t, err, "boltutil.Migrate")
+	AssertEquals(t, 1, report.Results[0].Changed, "changed entries")
+
+	value, err := boltutil.Find[newValue](db, "values", "a")
+	AssertNoError(t, err, "boltutil.Find")
+	AssertEquals(t, 0, value.Score, "value after dry run")
+
+	_, err = boltutil.Migrate(db, "values", migrations, false)
+	AssertNoError(t, err, "boltutil.Migrate")
+
+	value, err = boltutil.Find[newValue](db, "values", "a")
+	AssertNoError(t, err, "boltutil.Find")
+	AssertEquals(t, newValue{Name: "a", Score: 10}, value, "migrated value")
+
+	version, err := boltutil.SchemaVersion(db, "values")
+	AssertNoError(t, err, "boltutil.SchemaVersion")
+	AssertEquals(t, uint64(1), version, "schema version")
+}
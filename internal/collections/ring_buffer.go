@@ -19,6 +19,15 @@ func (r *RingBuffer[T]) Append(x T) {
 	r.cursor++
 }
 
+// Next advances the ring buffer and returns a pointer to the element
+// that has to be overwritten so its memory can be reused.
+func (r *RingBuffer[T]) Next() *T {
+	r.cursor = r.cursor % r.len
+	x := &r.buff[r.cursor]
+	r.cursor++
+	return x
+}
+
 func (r *RingBuffer[T]) Slice() []T {
 	slice := make([]T, r.len)
 	for i := 0; i < r.len; i++ {
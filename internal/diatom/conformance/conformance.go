@@ -0,0 +1,217 @@
+// Package conformance holds programs with their expected behaviour
+// that every diatom VM implementation has to agree on.
+//
+// Each case in the programs directory consists of a source file and a
+// .want file with the same name. Assembly (.dasm) is run as is while
+// Forth source (.dia) is fed as input into the interpreter of the
+// preamble. An optional .in file provides the input of assembly
+// programs. The .want file lists the expectations line by line:
+//
+//	output: "12\n"
+//	data-stack: 1 2
+//	return-stack:
+//	error: true
+//
+// where output is a Go string literal. Expectations that are left out
+// are not checked but still have to match between the VMs.
+package conformance
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/eldelto/core/internal/diatom/v2"
+)
+
+//go:embed programs
+var programs embed.FS
+
+// Want describes the expected state of the VM after running a case.
+// Nil fields are not checked.
+type Want struct {
+	Output      *string
+	DataStack   []diatom.Word
+	ReturnStack []diatom.Word
+	Error       bool
+}
+
+type Case struct {
+	Name    string
+	Program []byte
+	Input   string
+	Want    Want
+}
+
+// Result is the state of a VM after running a case.
+type Result struct {
+	Output      string
+	DataStack   []diatom.Word
+	ReturnStack []diatom.Word
+	Err         error
+}
+
+func parseWords(s string) ([]diatom.Word, error) {
+	words := []diatom.Word{}
+	for _, field := range strings.Fields(s) {
+		w, err := strconv.ParseInt(field, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid word %q: %w", field, err)
+		}
+		words = append(words, diatom.Word(w))
+	}
+
+	return words, nil
+}
+
+func parseWant(data []byte) (Want, error) {
+	want := Want{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return want, fmt.Errorf("expected 'key: value' but got %q", line)
+		}
+		value = strings.TrimSpace(value)
+
+		var err error
+		switch key {
+		case "output":
+			var output string
+			output, err = strconv.Unquote(value)
+			want.Output = &output
+		case "data-stack":
+			want.DataStack, err = parseWords(value)
+		case "return-stack":
+			want.ReturnStack, err = parseWords(value)
+		case "error":
+			want.Error, err = strconv.ParseBool(value)
+		default:
+			err = errors.New("unknown key")
+		}
+		if err != nil {
+			return want, fmt.Errorf("%s: %w", key, err)
+		}
+	}
+
+	return want, scanner.Err()
+}
+
+func loadCase(fsys fs.FS, file string, repl []byte) (Case, error) {
+	ext := path.Ext(file)
+	name := strings.TrimSuffix(file, ext)
+
+	source, err := fs.ReadFile(fsys, file)
+	if err != nil {
+		return Case{}, err
+	}
+
+	c := Case{Name: path.Base(name)}
+	switch ext {
+	case ".dasm":
+		program, err := diatom.AssembleProgram(path.Base(file), bytes.NewReader(source))
+		if err != nil {
+			return c, fmt.Errorf("failed to assemble %q: %w", file, err)
+		}
+		c.Program = program.Dopc
+
+		input, err := fs.ReadFile(fsys, name+".in")
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return c, err
+		}
+		c.Input = string(input)
+	case ".dia":
+		c.Program = repl
+		c.Input = string(source)
+	}
+
+	want, err := fs.ReadFile(fsys, name+".want")
+	if err != nil {
+		return c, err
+	}
+	if c.Want, err = parseWant(want); err != nil {
+		return c, fmt.Errorf("invalid expectations for %q: %w", file, err)
+	}
+
+	return c, nil
+}
+
+// Cases returns all cases of the conformance suite.
+func Cases() ([]Case, error) {
+	repl, err := diatom.AssembleRepl(diatom.VMOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := fs.Glob(programs, "programs/*.d*")
+	if err != nil {
+		return nil, err
+	}
+
+	cases := []Case{}
+	for _, file := range files {
+		switch path.Ext(file) {
+		case ".dasm", ".dia":
+		default:
+			continue
+		}
+
+		c, err := loadCase(programs, file, repl.Dopc)
+		if err != nil {
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+
+	return cases, nil
+}
+
+// Run executes a case on the Go VM.
+func Run(c Case) Result {
+	output := &bytes.Buffer{}
+	vm, err := diatom.NewVM(c.Program, strings.NewReader(c.Input), output)
+	if err != nil {
+		return Result{Err: err}
+	}
+
+	err = vm.Execute()
+	return Result{
+		Output:      output.String(),
+		DataStack:   vm.DataStack(),
+		ReturnStack: vm.ReturnStack(),
+		Err:         err,
+	}
+}
+
+// Check returns a description of every expectation the result doesn't
+// meet.
+func (w Want) Check(r Result) []string {
+	failures := []string{}
+	if w.Error != (r.Err != nil) {
+		failures = append(failures, fmt.Sprintf("error: want %t but got %v", w.Error, r.Err))
+	}
+	if w.Output != nil && *w.Output != r.Output {
+		failures = append(failures, fmt.Sprintf("output: want %q but got %q", *w.Output, r.Output))
+	}
+	if w.DataStack != nil && !slices.Equal(w.DataStack, r.DataStack) {
+		failures = append(failures, fmt.Sprintf("data stack: want %v but got %v", w.DataStack, r.DataStack))
+	}
+	if w.ReturnStack != nil && !slices.Equal(w.ReturnStack, r.ReturnStack) {
+		failures = append(failures, fmt.Sprintf("return stack: want %v but got %v", w.ReturnStack, r.ReturnStack))
+	}
+
+	return failures
+}
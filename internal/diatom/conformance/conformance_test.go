@@ -0,0 +1,33 @@
+package conformance
+
+import (
+	"strings"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+func TestGoVM(t *testing.T) {
+	cases, err := Cases()
+	AssertNoError(t, err, "Cases")
+
+	for _, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			if failures := c.Want.Check(Run(c)); len(failures) > 0 {
+				t.Fatal(strings.Join(failures, "\n"))
+			}
+		})
+	}
+}
+
+func TestParseWant(t *testing.T) {
+	want, err := parseWant([]byte("output: \"a\\n\"\ndata-stack: 1 -2\n\nreturn-stack:\nerror: true\n"))
+	AssertNoError(t, err, "parseWant")
+	AssertEquals(t, "a\n", *want.Output, "output")
+	AssertEquals(t, 2, len(want.DataStack), "data stack length")
+	AssertEquals(t, 0, len(want.ReturnStack), "return stack length")
+	AssertEquals(t, true, want.Error, "error")
+
+	_, err = parseWant([]byte("stack: 1"))
+	AssertError(t, err, "parseWant with unknown key")
+}
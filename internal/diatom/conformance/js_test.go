@@ -0,0 +1,202 @@
+package conformance
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/eldelto/core/internal/diatom/diatomjs"
+	"github.com/eldelto/core/internal/diatom/v2"
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+//go:embed testdata/runner.js
+var runner string
+
+// domStubs replace the browser APIs diatom.js needs to be loaded.
+const domStubs = `globalThis.HTMLElement = class {};
+globalThis.customElements = { define() {} };
+`
+
+type jsCase struct {
+	Name     string           `json:"name"`
+	Program  []byte           `json:"program"`
+	Input    string           `json:"input"`
+	Snapshot *diatom.Snapshot `json:"snapshot,omitempty"`
+}
+
+type jsResult struct {
+	Name        string        `json:"name"`
+	Output      []byte        `json:"output"`
+	DataStack   []diatom.Word `json:"dataStack"`
+	ReturnStack []diatom.Word `json:"returnStack"`
+	Error       *string       `json:"error"`
+	// Snapshot is the state of the VM after the case has been run.
+	Snapshot diatom.Snapshot `json:"snapshot"`
+}
+
+func (r jsResult) result() Result {
+	result := Result{
+		Output:      string(r.Output),
+		DataStack:   r.DataStack,
+		ReturnStack: r.ReturnStack,
+	}
+	if r.Error != nil {
+		result.Err = errors.New(*r.Error)
+	}
+	return result
+}
+
+// runJS runs all cases in a single node process.
+func runJS(t *testing.T, node string, cases []jsCase) []jsResult {
+	dir := t.TempDir()
+
+	harness := filepath.Join(dir, "harness.js")
+	err := os.WriteFile(harness, []byte(domStubs+diatomjs.Runtime+"\n"+runner), 0644)
+	AssertNoError(t, err, "write harness")
+
+	data, err := json.Marshal(cases)
+	AssertNoError(t, err, "marshal cases")
+	casesPath := filepath.Join(dir, "cases.json")
+	AssertNoError(t, os.WriteFile(casesPath, data, 0644), "write cases")
+
+	cmd := exec.Command(node, harness, casesPath)
+	cmd.Stderr = &strings.Builder{}
+	out, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("failed to run the JavaScript VM: %v\n%s", err, cmd.Stderr)
+	}
+
+	jsResults := []jsResult{}
+	AssertNoError(t, json.Unmarshal(out, &jsResults), "unmarshal results")
+	AssertEquals(t, len(cases), len(jsResults), "number of results")
+
+	return jsResults
+}
+
+// divergences describes the differences between the results of the Go
+// and the JavaScript VM. The stacks are only compared if both succeed
+// as they don't have to agree on the state after an error.
+func divergences(goResult, jsResult Result) []string {
+	d := []string{}
+	if (goResult.Err == nil) != (jsResult.Err == nil) {
+		d = append(d, fmt.Sprintf("error: Go returned %v but JavaScript %v", goResult.Err, jsResult.Err))
+	}
+	if goResult.Output != jsResult.Output {
+		d = append(d, fmt.Sprintf("output: Go wrote %q but JavaScript %q", goResult.Output, jsResult.Output))
+	}
+	if goResult.Err != nil || jsResult.Err != nil {
+		return d
+	}
+
+	if !slices.Equal(goResult.DataStack, jsResult.DataStack) {
+		d = append(d, fmt.Sprintf("data stack: Go has %v but JavaScript %v", goResult.DataStack, jsResult.DataStack))
+	}
+	if !slices.Equal(goResult.ReturnStack, jsResult.ReturnStack) {
+		d = append(d, fmt.Sprintf("return stack: Go has %v but JavaScript %v", goResult.ReturnStack, jsResult.ReturnStack))
+	}
+
+	return d
+}
+
+func TestJSVM(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is required to run the JavaScript VM")
+	}
+
+	cases, err := Cases()
+	AssertNoError(t, err, "Cases")
+
+	jsCases := make([]jsCase, len(cases))
+	for i, c := range cases {
+		jsCases[i] = jsCase{Name: c.Name, Program: c.Program, Input: c.Input}
+	}
+
+	results := runJS(t, node, jsCases)
+	for i, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := results[i].result()
+			failures := c.Want.Check(result)
+			failures = append(failures, divergences(Run(c), result)...)
+			if len(failures) > 0 {
+				t.Fatal(strings.Join(failures, "\n"))
+			}
+		})
+	}
+}
+
+// runHalf runs the first half of the instructions of a case on the Go
+// VM and returns its snapshot together with the output written and the
+// input not yet read.
+func runHalf(t *testing.T, c Case) (diatom.Snapshot, string, string) {
+	vm, err := diatom.NewVM(c.Program, strings.NewReader(c.Input), io.Discard)
+	AssertNoError(t, err, "NewVM")
+	steps := 0
+	for done := false; !done; steps++ {
+		done, err = vm.Step()
+		AssertNoError(t, err, "vm.Step")
+	}
+
+	input := strings.NewReader(c.Input)
+	output := &strings.Builder{}
+	vm, err = diatom.NewVM(c.Program, input, output)
+	AssertNoError(t, err, "NewVM")
+	for range steps / 2 {
+		_, err := vm.Step()
+		AssertNoError(t, err, "vm.Step")
+	}
+
+	remaining, err := io.ReadAll(input)
+	AssertNoError(t, err, "read remaining input")
+	return vm.Snapshot(), output.String(), string(remaining)
+}
+
+// TestJSSnapshot pauses the successful cases halfway on the Go VM,
+// resumes them on the JavaScript VM and restores the final snapshot of
+// the JavaScript VM on the Go VM again.
+func TestJSSnapshot(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node is required to run the JavaScript VM")
+	}
+
+	cases, err := Cases()
+	AssertNoError(t, err, "Cases")
+	cases = slices.DeleteFunc(cases, func(c Case) bool { return c.Want.Error })
+
+	jsCases := make([]jsCase, len(cases))
+	outputs := make([]string, len(cases))
+	for i, c := range cases {
+		snapshot, output, input := runHalf(t, c)
+		jsCases[i] = jsCase{Name: c.Name, Input: input, Snapshot: &snapshot}
+		outputs[i] = output
+	}
+
+	results := runJS(t, node, jsCases)
+	for i, c := range cases {
+		t.Run(c.Name, func(t *testing.T) {
+			result := results[i].result()
+			result.Output = outputs[i] + result.Output
+			if failures := c.Want.Check(result); len(failures) > 0 {
+				t.Fatal(strings.Join(failures, "\n"))
+			}
+
+			vm, err := diatom.NewVM(c.Program, strings.NewReader(c.Input), io.Discard)
+			AssertNoError(t, err, "NewVM")
+			AssertNoError(t, vm.Execute(), "vm.Execute")
+
+			restored, err := diatom.RestoreVM(results[i].Snapshot, strings.NewReader(""), io.Discard)
+			AssertNoError(t, err, "RestoreVM")
+			AssertEquals(t, vm.Snapshot(), restored.Snapshot(), "snapshot")
+		})
+	}
+}
@@ -0,0 +1,44 @@
+// Runs the conformance cases of the JSON file given as argument on the
+// JavaScript VM and writes the results as JSON to stdout. The Go tests
+// append this file to diatom.js and run it with node.
+const fs = require("fs");
+
+// runCase starts with the program of the case or resumes from its
+// snapshot if one is given.
+async function runCase(c) {
+	const output = [];
+	const result = { name: c.name, error: null };
+	let vm = new DiatomVM();
+
+	try {
+		if (c.snapshot) {
+			vm = DiatomVM.restore(c.snapshot);
+		} else {
+			vm.load(Buffer.from(c.program, "base64"));
+		}
+		vm.withOutput(b => output.push(b));
+		vm.pushInput(c.input).closeInput();
+		await vm.execute();
+	} catch (error) {
+		result.error = String(error);
+	}
+
+	result.output = Buffer.from(output).toString("base64");
+	result.dataStack = vm.dataStack.slice();
+	result.returnStack = vm.returnStack.slice();
+	result.snapshot = vm.snapshot();
+	return result;
+}
+
+async function main() {
+	const cases = JSON.parse(fs.readFileSync(process.argv[2], "utf8"));
+
+	const results = [];
+	for (const c of cases) {
+		results.push(await runCase(c));
+	}
+
+	process.stdout.write(JSON.stringify(results));
+}
+
+main();
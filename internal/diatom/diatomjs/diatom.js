@@ -1,45 +1,66 @@
 // Instructions
-const EXIT = 0;
-const NOP = 1;
+const ABORT = 0;
+const EXIT = 1;
 const RET = 2;
-const CONST = 3;
-const FETCH = 4;
-const STORE = 5;
-const ADD = 6;
-const SUBTRACT = 7;
-const MULTIPLY = 8;
-const DIVIDE = 9;
-const MODULO = 10;
-const DUP = 11;
-const DROP = 12;
-const SWAP = 13;
-const OVER = 14;
-const CJMP = 15;
-const CALL = 16;
-const SCALL = 17;
-const KEY = 18;
-const EMIT = 19;
-const EQUALS = 20;
-const NOT = 21;
-const AND = 22;
-const OR = 23;
-const LT = 24;
-const GT = 25;
-const RPOP = 26;
-const RPUT = 27;
-const RPEEK = 28;
-const BFETCH = 29;
-const BSTORE = 30;
-const DUMP = 31;
+const JMP = 3;
+const CJMP = 4;
+const CALL = 5;
+const EXCALL = 6;
+
+const CONST = 7;
+const DUP = 8;
+const DROP = 9;
+const SWAP = 10;
+const OVER = 11;
+const RPUSH = 12;
+const RPOP = 13;
+const RPEEK = 14;
+
+const STORE = 15;
+const FETCH = 16;
+const BSTORE = 17;
+const BFETCH = 18;
+
+const ADD = 19;
+const SUB = 20;
+const MULT = 21;
+const DIV = 22;
+const MOD = 23;
+
+const EQ = 24;
+const NOT = 25;
+const AND = 26;
+const OR = 27;
+const LT = 28;
+const GT = 29;
+
+const KEY = 30;
+const EMIT = 31;
+const DUMP = 32;
+const DEPTH = 33;
 
 const wordSize = 4;
 const wordMax = 2147483647;
 const wordMin = -2147483648;
 
-const stackSize = 30;
+const stackSize = 20;
 const ioBufferSize = 4096;
 const memorySize = 8192;
 
+// yieldInterval is the number of instructions after which execute
+// hands control back to the event loop so that endless loops neither
+// freeze the page nor prevent reset from stopping the program.
+const yieldInterval = 100000;
+
+// snapshotVersion has to match SnapshotVersion of the Go VM.
+const snapshotVersion = 1;
+
+const defaultVMOptions = {
+	memorySize: memorySize,
+	dataStackSize: stackSize,
+	returnStackSize: stackSize,
+};
+
 class Stack {
 	#cursor = 0;
 
@@ -73,6 +94,40 @@ class Stack {
 
 		return this.data[this.#cursor - 1];
 	}
+
+	get length() {
+		return this.#cursor;
+	}
+
+	// slice returns a copy of the stack's values from bottom to top.
+	slice() {
+		return Array.from(this.data.slice(0, this.#cursor));
+	}
+
+	// restore replaces the content of the stack with values from bottom
+	// to top.
+	restore(values) {
+		// push refuses to fill the last slot of a stack.
+		if (values.length >= this.data.length) {
+			throw new Error(`restore: stack holds ${values.length} values but its size is ${this.data.length}`);
+		}
+
+		this.data.fill(0);
+		this.data.set(values);
+		this.#cursor = values.length;
+	}
+}
+
+function bytesToBase64(bytes) {
+	let s = "";
+	for (const b of bytes) {
+		s += String.fromCharCode(b);
+	}
+	return btoa(s);
+}
+
+function base64ToBytes(s) {
+	return Uint8Array.from(atob(s ?? ""), c => c.charCodeAt(0));
 }
 
 function add(a, b) {
@@ -119,6 +174,7 @@ class Input {
 	#cursor = 0;
 	#buffer = new Uint8Array(new ArrayBuffer(0));
 	#resolve = null;
+	#closed = false;
 
 	pushData(data) {
 		const remaining = this.#buffer.slice(this.#cursor);
@@ -129,7 +185,7 @@ class Input {
 
 		this.#cursor = 0;
 
-		if (this.#resolve) {
+		if (this.#resolve && this.#cursor < this.#buffer.length) {
 			const c = this.#buffer[this.#cursor];
 			this.#cursor++;
 			this.#resolve(c);
@@ -137,6 +193,11 @@ class Input {
 		}
 	}
 
+	// remaining returns a copy of the data that has not been read yet.
+	remaining() {
+		return this.#buffer.slice(this.#cursor);
+	}
+
 	nextChar() {
 		if (this.#cursor < this.#buffer.length) {
 			const c = this.#buffer[this.#cursor];
@@ -145,12 +206,27 @@ class Input {
 			return Promise.resolve(c);
 		}
 
+		if (this.#closed) {
+			return Promise.resolve(null);
+		}
+
 		const promise = new Promise((res, _rej) => {
 			this.#resolve = res;
 		});
 
 		return promise;
 	}
+
+	// close marks the end of the input. Once the buffered data has been
+	// consumed nextChar resolves to null like the Go VM reaching EOF.
+	close() {
+		this.#closed = true;
+
+		if (this.#resolve) {
+			this.#resolve(null);
+			this.#resolve = null;
+		}
+	}
 }
 
 class DiatomVM {
@@ -161,13 +237,20 @@ class DiatomVM {
 	#inputElement = null;
 	#outputElement = null;
 	#memory = null;
-
-	constructor() {
+	#options = null;
+	#extensions = new Map();
+	#extensionIDs = [];
+	#execution = 0;
+
+	// options can override the memorySize, dataStackSize and
+	// returnStackSize of defaultVMOptions like the VMOptions of the Go VM.
+	constructor(options = {}) {
+		this.#options = { ...defaultVMOptions, ...options };
 		this.reset();
 	}
 
 	validateMemoryAccess(addr) {
-		if (addr >= this.#memory.length) {
+		if (addr >= this.#memory.length || addr < 0) {
 			throw new Error(`out of bound memory access: programCounter=${this.#programCounter} address=${addr}`);
 		}
 	}
@@ -235,6 +318,8 @@ class DiatomVM {
 		return this;
 	}
 
+	// withOutput accepts a selector, an element or a function that is
+	// called with every byte emitted by the program.
 	withOutput(selectorOrElement) {
 		this.#outputElement = selectorOrElement;
 		if (typeof selectorOrElement === "string") {
@@ -243,6 +328,99 @@ class DiatomVM {
 		return this;
 	}
 
+	// pushInput appends data (a string or Uint8Array) to the input read
+	// by key.
+	pushInput(data) {
+		if (typeof data === "string") {
+			data = new TextEncoder().encode(data);
+		}
+		this.#inputBuffer.pushData(data);
+		return this;
+	}
+
+	// closeInput makes the program exit once it tries to read past the
+	// end of the input, like the Go VM does at EOF.
+	closeInput() {
+		this.#inputBuffer.close();
+		return this;
+	}
+
+	// registerExtension makes the functions callable with excall at the
+	// addresses of the module addr like RegisterExtension of the Go VM.
+	// Each function gets the VM passed and works on its stacks. The name
+	// identifies the extension in snapshots.
+	registerExtension(addr, functions, name = "") {
+		const moduleAddr = addr << 16;
+		functions.forEach((f, i) => {
+			const funcAddr = moduleAddr | i;
+			if (this.#extensions.has(funcAddr)) {
+				throw new Error(`extension function already exists at address '${funcAddr}'`);
+			}
+			this.#extensions.set(funcAddr, f);
+		});
+		this.#extensionIDs.push({ addr: addr, name: name, functions: functions.length });
+		return this;
+	}
+
+	// snapshot returns the state of the VM in the JSON format of the
+	// Snapshot of the Go VM. Taken while the program waits for input it
+	// resumes with the pending key instruction.
+	snapshot() {
+		let end = this.#memory.length;
+		while (end > 0 && this.#memory[end - 1] === 0) {
+			end--;
+		}
+
+		return {
+			version: snapshotVersion,
+			programCounter: this.#programCounter,
+			dataStack: this.dataStack.slice(),
+			dataStackSize: this.dataStack.data.length,
+			returnStack: this.returnStack.slice(),
+			returnStackSize: this.returnStack.data.length,
+			input: bytesToBase64(this.#inputBuffer.remaining()),
+			memory: bytesToBase64(this.#memory.subarray(0, end)),
+			memorySize: this.#memory.length,
+			extensions: this.#extensionIDs.map(id => ({ ...id })),
+		};
+	}
+
+	// restore creates a VM from a snapshot of either the Go or the
+	// JavaScript VM. All extensions recorded in the snapshot have to be
+	// passed in again as objects with addr, name and functions.
+	static restore(snapshot, extensions = []) {
+		if (snapshot.version !== snapshotVersion) {
+			throw new Error(`unsupported snapshot version ${snapshot.version}, expected ${snapshotVersion}`);
+		}
+		if (snapshot.tasks?.length > 0) {
+			throw new Error("snapshots of programs with tasks can only be restored by the Go VM");
+		}
+
+		const vm = new DiatomVM({
+			memorySize: snapshot.memorySize,
+			dataStackSize: snapshot.dataStackSize,
+			returnStackSize: snapshot.returnStackSize,
+		});
+		vm.load(base64ToBytes(snapshot.memory));
+		vm.#programCounter = snapshot.programCounter;
+		vm.dataStack.restore(snapshot.dataStack ?? []);
+		vm.returnStack.restore(snapshot.returnStack ?? []);
+		vm.pushInput(base64ToBytes(snapshot.input));
+
+		for (const ext of extensions) {
+			vm.registerExtension(ext.addr, ext.functions, ext.name);
+		}
+		for (const want of snapshot.extensions ?? []) {
+			const found = vm.#extensionIDs.some(id =>
+				id.addr === want.addr && id.name === want.name && id.functions === want.functions);
+			if (!found) {
+				throw new Error(`extension "${want.name}" with ${want.functions} functions is missing at address '${want.addr}'`);
+			}
+		}
+
+		return vm;
+	}
+
 	load(program) {
 		if (program.length > this.#memory.length) {
 			throw new Error(`program length (${program.length} bytes) exceeds available memory (${this.#memory.length} bytes)`);
@@ -264,153 +442,206 @@ class DiatomVM {
 		});
 	}
 
+	// reset stops a running program and restores the initial state of
+	// the VM.
 	reset() {
+		this.#execution++;
 		if (this.#inputElement) {
 			this.#inputElement.removeEventListener("keyup", this.handleInput);
 		}
 
 		this.#programCounter = 0;
-		this.dataStack = new Stack(stackSize);
-		this.returnStack = new Stack(stackSize);
+		this.dataStack = new Stack(this.#options.dataStackSize);
+		this.returnStack = new Stack(this.#options.returnStackSize);
 		this.#inputBuffer = new Input();
 		this.#inputElement = null;
 		this.#outputElement = null;
-		this.#memory = new Uint8Array(new ArrayBuffer(memorySize));
+		this.#memory = new Uint8Array(new ArrayBuffer(this.#options.memorySize));
+	}
+
+	emit(b) {
+		if (typeof this.#outputElement === "function") {
+			this.#outputElement(b);
+		} else if (this.#outputElement) {
+			this.#outputElement.textContent += String.fromCharCode(b);
+		} else {
+			console.log(String.fromCharCode(b));
+		}
+	}
+
+	// isStopped reports whether reset was called since the execution
+	// started.
+	#isStopped(execution) {
+		return execution !== this.#execution;
 	}
 
 	async execute() {
-		while (true) {
-			const instruction = this.#memory[this.#programCounter];
+		const execution = this.#execution;
+		for (let executed = 1; ; executed++) {
+			if (executed % yieldInterval === 0) {
+				await new Promise(resolve => setTimeout(resolve));
+				if (this.#isStopped(execution)) {
+					return;
+				}
+			}
+
+			const instruction = this.fetchByte(this.#programCounter);
 
 			switch (instruction) {
+			case ABORT: {
+				throw new Error(`tried to execute uninitialized memory at address ${this.#programCounter} - aborting`);
+			}
 			case EXIT: {
-				console.log("VM exited normally");
 				return;
 			}
-			case NOP: {
-				break;
-			}
 			case RET: {
-				const addr = this.returnStack.pop();
-				this.#programCounter = addr;
+				this.#programCounter = this.returnStack.pop();
+				continue;
+			}
+			case JMP: {
+				this.#programCounter = this.fetchWord(this.#programCounter + 1);
 				continue;
 			}
+			case CJMP: {
+				this.#programCounter++;
+				const conditional = this.dataStack.pop();
+				if (conditional === 0) {
+					this.#programCounter += wordSize;
+				} else {
+					this.#programCounter = this.fetchWord(this.#programCounter);
+				}
+				continue;
+			}
+			case CALL: {
+				this.#programCounter++;
+				this.returnStack.push(this.#programCounter + wordSize);
+
+				this.#programCounter = this.fetchWord(this.#programCounter);
+				continue;
+			}
+			case EXCALL: {
+				const addr = this.dataStack.pop();
+				const f = this.#extensions.get(addr);
+				if (!f) {
+					throw new Error(`extension function at address '${addr}' not found - programCounter=${this.#programCounter}`);
+				}
+				await f(this);
+				if (this.#isStopped(execution)) {
+					return;
+				}
+				break;
+			}
 			case CONST: {
 				this.#programCounter++;
 				const w = this.fetchWord(this.#programCounter);
 				this.dataStack.push(w);
 
 				this.#programCounter += wordSize;
-				continue
+				continue;
 			}
-			case FETCH: {
-				const addr = this.dataStack.pop();
-				const w = this.fetchWord(addr);
-				this.dataStack.push(w);
+			case DUP: {
+				const a = this.dataStack.peek();
+				this.dataStack.push(a);
 				break;
 			}
-			case STORE: {
-				const addr = this.dataStack.pop();
-				const value = this.dataStack.pop();
-				this.storeWord(addr, value);
+			case DROP: {
+				this.dataStack.pop();
 				break;
 			}
-			case ADD: {
+			case SWAP: {
 				const a = this.dataStack.pop();
 				const b = this.dataStack.pop();
-				this.dataStack.push(add(b, a));
+				this.dataStack.push(a);
+				this.dataStack.push(b);
 				break;
 			}
-			case SUBTRACT: {
+			case OVER: {
 				const a = this.dataStack.pop();
 				const b = this.dataStack.pop();
-				this.dataStack.push(subtract(b, a));
+				this.dataStack.push(b);
+				this.dataStack.push(a);
+				this.dataStack.push(b);
 				break;
 			}
-			case MULTIPLY: {
+			case RPUSH: {
 				const a = this.dataStack.pop();
-				const b = this.dataStack.pop();
-				this.dataStack.push(multiply(b, a));
+				this.returnStack.push(a);
 				break;
 			}
-			case DIVIDE: {
-				const a = this.dataStack.pop();
-				const b = this.dataStack.pop();
-				this.dataStack.push(b / a);
+			case RPOP: {
+				const a = this.returnStack.pop();
+				this.dataStack.push(a);
 				break;
 			}
-			case MODULO: {
-				const a = this.dataStack.pop();
-				const b = this.dataStack.pop();
-				this.dataStack.push(b % a);
+			case RPEEK: {
+				const a = this.returnStack.peek();
+				this.dataStack.push(a);
 				break;
 			}
-			case DUP: {
-				const a = this.dataStack.peek();
-				this.dataStack.push(a);
+			case STORE: {
+				const addr = this.dataStack.pop();
+				const value = this.dataStack.pop();
+				this.storeWord(addr, value);
 				break;
 			}
-			case DROP: {
-				this.dataStack.pop();
+			case FETCH: {
+				const addr = this.dataStack.pop();
+				const w = this.fetchWord(addr);
+				this.dataStack.push(w);
 				break;
 			}
-			case SWAP: {
-				const a = this.dataStack.pop();
-				const b = this.dataStack.pop();
-				this.dataStack.push(a);
+			case BSTORE: {
+				const addr = this.dataStack.pop();
+				const value = this.dataStack.pop();
+				this.storeByte(addr, value);
+				break;
+			}
+			case BFETCH: {
+				const addr = this.dataStack.pop();
+				const b = this.fetchByte(addr);
 				this.dataStack.push(b);
 				break;
 			}
-			case OVER: {
+			case ADD: {
 				const a = this.dataStack.pop();
 				const b = this.dataStack.pop();
-				this.dataStack.push(b);
-				this.dataStack.push(a);
-				this.dataStack.push(b);
+				this.dataStack.push(add(b, a));
 				break;
 			}
-			case CJMP: {
-				this.#programCounter++;
-				const conditional = this.dataStack.pop();
-				if (conditional === -1) {
-					this.#programCounter = this.fetchWord(this.#programCounter);
-				} else {
-					this.#programCounter += wordSize;
-				}
-				continue
-			}
-			case CALL: {
-				this.#programCounter++;
-				this.returnStack.push(this.#programCounter + wordSize);
-
-				this.#programCounter = this.fetchWord(this.#programCounter);
-				continue
+			case SUB: {
+				const a = this.dataStack.pop();
+				const b = this.dataStack.pop();
+				this.dataStack.push(subtract(b, a));
+				break;
 			}
-			case SCALL: {
-				this.#programCounter++;
-				this.returnStack.push(this.#programCounter);
-
-				this.#programCounter = this.dataStack.pop();
-				continue
+			case MULT: {
+				const a = this.dataStack.pop();
+				const b = this.dataStack.pop();
+				this.dataStack.push(multiply(b, a));
+				break;
 			}
-			case KEY:{
-				const b = await this.#inputBuffer.nextChar();
-				this.dataStack.push(b);
+			case DIV: {
+				const a = this.dataStack.pop();
+				const b = this.dataStack.pop();
+				if (a === 0) {
+					throw new Error(`division by zero at address ${this.#programCounter}`);
+				}
+				this.dataStack.push(Math.trunc(b / a) | 0);
 				break;
 			}
-			case EMIT: {
-				const value = String.fromCharCode(this.dataStack.pop());
-				if (this.#outputElement) {
-					this.#outputElement.textContent += value;
-				} else {
-					console.log(value);
+			case MOD: {
+				const a = this.dataStack.pop();
+				const b = this.dataStack.pop();
+				if (a === 0) {
+					throw new Error(`division by zero at address ${this.#programCounter}`);
 				}
+				this.dataStack.push((b % a) | 0);
 				break;
 			}
-			case EQUALS: {
+			case EQ: {
 				const a = this.dataStack.pop();
 				const b = this.dataStack.pop();
-				this.dataStack.push(boolToWord(b == a));
+				this.dataStack.push(boolToWord(b === a));
 				break;
 			}
 			case NOT: {
@@ -442,31 +673,16 @@ class DiatomVM {
 				this.dataStack.push(boolToWord(b > a));
 				break;
 			}
-			case RPUT: {
-				const a = this.dataStack.pop();
-				this.returnStack.push(a);
-				break;
-			}
-			case RPOP: {
-				const a = this.returnStack.pop();
-				this.dataStack.push(a);
-				break;
-			}
-			case RPEEK: {
-				const a = this.returnStack.peek();
-				this.dataStack.push(a);
-				break;
-			}
-			case BFETCH: {
-				const addr = this.dataStack.pop();
-				const b= this.fetchByte(addr);
+			case KEY: {
+				const b = await this.#inputBuffer.nextChar();
+				if (b === null || this.#isStopped(execution)) {
+					return;
+				}
 				this.dataStack.push(b);
 				break;
 			}
-			case BSTORE: {
-				const addr = this.dataStack.pop();
-				const value = this.dataStack.pop();
-				this.storeByte(addr, value);
+			case EMIT: {
+				this.emit(this.dataStack.pop() & 0xFF);
 				break;
 			}
 			case DUMP: {
@@ -474,6 +690,10 @@ class DiatomVM {
 				this.dataStack.pop();
 				break;
 			}
+			case DEPTH: {
+				this.dataStack.push(this.dataStack.length);
+				break;
+			}
 			default:
 				throw new Error(`unknown instruction '${instruction}' at memory address '${this.#programCounter}' - terminating`);
 			}
@@ -483,21 +703,88 @@ class DiatomVM {
 	}
 }
 
+// vmOptionsFromAttributes reads the VM options of the memory-size,
+// stack-size and return-stack-size attributes of element.
+function vmOptionsFromAttributes(element) {
+	const options = {};
+	const attributes = {
+		"memory-size": "memorySize",
+		"stack-size": "dataStackSize",
+		"return-stack-size": "returnStackSize",
+	};
+
+	for (const [attribute, option] of Object.entries(attributes)) {
+		if (element.hasAttribute(attribute)) {
+			options[option] = parseInt(element.getAttribute(attribute), 10);
+		}
+	}
+
+	return options;
+}
+
 class DiatomRepl extends HTMLElement {
-	static observedAttributes = ["src"];
-	#vm = new DiatomVM();
+	static observedAttributes = ["src", "memory-size", "stack-size", "return-stack-size"];
+	#vm = null;
 
 	constructor() {
 		super();
 	}
 
-	init(input, output) {
+	vmOptions() {
+		return vmOptionsFromAttributes(this);
+	}
+
+	// sessionKey is the key of the local storage entry that holds the
+	// session of the REPL.
+	sessionKey() {
+		return "diatom-repl:" + this.getAttribute("src");
+	}
+
+	// saveSession stores a snapshot of the VM together with the output
+	// so that the session survives reloading the page.
+	saveSession(output) {
+		try {
+			const session = { snapshot: this.#vm.snapshot(), output: output.textContent };
+			localStorage.setItem(this.sessionKey(), JSON.stringify(session));
+		} catch (error) {
+			console.warn("failed to save diatom session:", error);
+		}
+	}
+
+	// restoreSession returns the VM of the stored session or null if
+	// there is none.
+	restoreSession(output) {
+		const data = localStorage.getItem(this.sessionKey());
+		if (data === null) {
+			return null;
+		}
+
+		try {
+			const session = JSON.parse(data);
+			const vm = DiatomVM.restore(session.snapshot);
+			output.textContent = session.output;
+			return vm;
+		} catch (error) {
+			console.warn("failed to restore diatom session:", error);
+			localStorage.removeItem(this.sessionKey());
+			return null;
+		}
+	}
+
+	init(input, output, restore = false) {
 		output.textContent = "";
 
-		this.#vm.reset();
+		if (this.#vm !== null) {
+			this.#vm.reset();
+		}
+
+		const restored = restore ? this.restoreSession(output) : null;
+		this.#vm = restored ?? new DiatomVM(this.vmOptions());
 		this.#vm.withInput(input);
 		this.#vm.withOutput(output);
-		this.#vm.loadRemote(this.getAttribute("src"))
+
+		const loaded = restored ? Promise.resolve() : this.#vm.loadRemote(this.getAttribute("src"));
+		loaded
 			.then(_ => this.#vm.execute())
 			.catch(error => {
 				output.textContent += "\r\n" + error + "\r\n";
@@ -518,7 +805,11 @@ class DiatomRepl extends HTMLElement {
 		input.addEventListener("keyup", e => {
 			if (e.key == "Enter") {
 				output.textContent += input.value + "\r\n";
-				setTimeout(() => { output.scrollTop = output.scrollHeight }, 10);
+				setTimeout(() => {
+					output.scrollTop = output.scrollHeight;
+					// By now the VM waits for the next input.
+					this.saveSession(output);
+				}, 10);
 			}
 		});
 
@@ -526,6 +817,7 @@ class DiatomRepl extends HTMLElement {
 		resetButton.setAttribute("class", "diatom-reset");
 		resetButton.textContent = "Reset";
 		resetButton.addEventListener("click", e => {
+			localStorage.removeItem(this.sessionKey());
 			this.init(input, output);
 		});
 
@@ -537,8 +829,142 @@ class DiatomRepl extends HTMLElement {
 		//       Pipe errors to the output as well
 
 		this.appendChild(wrapper);
-		this.init(input, output);
+		this.init(input, output, true);
 	}
 }
 
 customElements.define("diatom-repl", DiatomRepl);
+
+// DiatomPlayground is an editor for diatom assembly. Its initial
+// source is the text content of the element. Running the source
+// assembles it with the Go assembler behind the assemble attribute
+// (/diatom/assemble by default) and executes the machine code. With
+// the stdin attribute an input field is shown whose content is read by
+// key, otherwise the program sees an empty input.
+class DiatomPlayground extends HTMLElement {
+	static observedAttributes = ["assemble", "stdin", "memory-size", "stack-size", "return-stack-size"];
+	#vm = null;
+	#runs = 0;
+
+	constructor() {
+		super();
+	}
+
+	assembleURL() {
+		return this.getAttribute("assemble") ?? "/diatom/assemble";
+	}
+
+	// assemble returns the resolved instructions (dins) and the machine
+	// code (dopc) of source.
+	async assemble(source) {
+		const response = await fetch(this.assembleURL(), {
+			method: "POST",
+			headers: { "Content-Type": "text/plain; charset=UTF-8" },
+			body: source,
+		});
+
+		let result = {};
+		try {
+			result = await response.json();
+		} catch (_) {
+			// The error below covers responses without JSON body.
+		}
+		if (!response.ok) {
+			throw new Error(result.error ?? `HTTP error! Status: ${response.status}`);
+		}
+
+		return { dins: result.dins, dopc: base64ToBytes(result.dopc) };
+	}
+
+	// run stops the program that is still running from a previous call
+	// and executes source instead.
+	async run(source, stdin, output, dins) {
+		const run = ++this.#runs;
+		output.textContent = "";
+		dins.textContent = "";
+		if (this.#vm !== null) {
+			this.#vm.reset();
+			this.#vm = null;
+		}
+
+		try {
+			const program = await this.assemble(source);
+			if (run !== this.#runs) {
+				// Superseded by a later run while assembling.
+				return;
+			}
+			dins.textContent = program.dins;
+
+			this.#vm = new DiatomVM(vmOptionsFromAttributes(this));
+			this.#vm.load(program.dopc);
+			this.#vm.withOutput(output);
+			this.#vm.pushInput(stdin).closeInput();
+			await this.#vm.execute();
+		} catch (error) {
+			if (run === this.#runs) {
+				output.textContent += "\r\n" + error + "\r\n";
+			}
+		}
+	}
+
+	connectedCallback() {
+		// Code blocks start with a line break after '<pre>'.
+		const source = this.textContent.replace(/^\n/, "").trimEnd();
+
+		const wrapper = document.createElement("div");
+		wrapper.setAttribute("class", "diatom-playground");
+
+		const editor = document.createElement("textarea");
+		editor.setAttribute("class", "diatom-editor");
+		editor.setAttribute("spellcheck", "false");
+		editor.setAttribute("rows", Math.max(3, source.split("\n").length));
+		editor.value = source;
+
+		const stdin = document.createElement("textarea");
+		stdin.setAttribute("class", "diatom-stdin");
+		stdin.setAttribute("rows", "2");
+		stdin.setAttribute("placeholder", "Input ...");
+
+		const runButton = document.createElement("button");
+		runButton.setAttribute("class", "diatom-run");
+		runButton.textContent = "Run";
+
+		const output = document.createElement("output");
+		output.setAttribute("class", "diatom-output");
+
+		const details = document.createElement("details");
+		details.setAttribute("class", "diatom-dins");
+		const summary = document.createElement("summary");
+		summary.textContent = "Assembled instructions";
+		const dins = document.createElement("pre");
+		details.appendChild(summary);
+		details.appendChild(dins);
+
+		const run = () => {
+			const input = this.hasAttribute("stdin") ? stdin.value : "";
+			this.run(editor.value, input, output, dins);
+		};
+		runButton.addEventListener("click", run);
+		editor.addEventListener("keydown", e => {
+			if (e.key === "Enter" && (e.ctrlKey || e.metaKey)) {
+				e.preventDefault();
+				run();
+			} else if (e.key === "Tab") {
+				e.preventDefault();
+				editor.setRangeText("  ", editor.selectionStart, editor.selectionEnd, "end");
+			}
+		});
+
+		wrapper.appendChild(runButton);
+		wrapper.appendChild(editor);
+		if (this.hasAttribute("stdin")) {
+			wrapper.appendChild(stdin);
+		}
+		wrapper.appendChild(output);
+		wrapper.appendChild(details);
+
+		this.replaceChildren(wrapper);
+	}
+}
+
+customElements.define("diatom-playground", DiatomPlayground);
@@ -8,6 +8,7 @@ import (
 	"math"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
 type pos struct {
@@ -17,6 +18,7 @@ type pos struct {
 
 type tokenScanner struct {
 	pos   pos
+	start pos
 	token []byte
 	r     io.Reader
 }
@@ -50,6 +52,9 @@ func (s *tokenScanner) scan() error {
 			}
 		}
 
+		if len(s.token) == 0 {
+			s.start = s.pos
+		}
 		s.token = append(s.token, b)
 	}
 
@@ -77,7 +82,9 @@ type assembler struct {
 	scanner      *tokenScanner
 	writer       io.Writer
 	lastWordName string
+	codeword     string
 	labels       map[string]Word
+	stringLabels map[string]struct{}
 	stringCount  uint
 }
 
@@ -87,14 +94,28 @@ func newAssembler(r io.Reader, w io.Writer) *assembler {
 			token: []byte{},
 			r:     r,
 		},
-		writer: w,
-		labels: map[string]Word{},
+		writer:       w,
+		labels:       map[string]Word{},
+		stringLabels: map[string]struct{}{},
 	}
 }
 
 func (asm *assembler) NextStringLabel() string {
 	asm.stringCount++
-	return fmt.Sprintf("string-%d", asm.stringCount)
+	label := fmt.Sprintf("string-%d", asm.stringCount)
+	asm.stringLabels[label] = struct{}{}
+	return label
+}
+
+// generatedLabel reports if the label has been generated by a macro
+// instead of being declared in the source.
+func (asm *assembler) generatedLabel(label string) bool {
+	if strings.HasPrefix(label, "_") {
+		return true
+	}
+
+	_, ok := asm.stringLabels[strings.TrimSuffix(label, "-end")]
+	return ok
 }
 
 func scanError(asm *assembler, err error) error {
@@ -165,7 +186,9 @@ func match(want string) func(string) (string, error) {
 }
 
 func identifier(token string) (string, error) {
-	if token[0] == '.' && len(token) > 1 {
+	// Macros start with a dot followed by a letter, names like ." are
+	// fine.
+	if token[0] == '.' && len(token) > 1 && unicode.IsLetter(rune(token[1])) {
 		return "", fmt.Errorf("expected non-macro identifier but got %q", token)
 	}
 
@@ -315,6 +338,8 @@ func expandCodeWord(asm *assembler) error {
 	if err != nil {
 		return err
 	}
+	asm.codeword = name
+	defer func() { asm.codeword = "" }()
 
 	if err := writeDictionaryHeader(asm, name, immediate); err != nil {
 		return err
@@ -362,6 +387,8 @@ func expandVar(asm *assembler) error {
 	if _, err := expectToken(asm, match(".end")); err != nil {
 		return err
 	}
+	asm.codeword = name
+	defer func() { asm.codeword = "" }()
 
 	if err := writeDictionaryHeader(asm, name, false); err != nil {
 		return err
@@ -463,8 +490,10 @@ func expandMacros(asm *assembler) error {
 }
 
 func ExpandMacros(r io.Reader, w io.Writer) error {
-	asm := newAssembler(r, w)
+	return expandAllMacros(newAssembler(r, w))
+}
 
+func expandAllMacros(asm *assembler) error {
 	for {
 		if err := expandMacros(asm); err != nil {
 			if errors.Is(err, io.EOF) {
@@ -514,6 +543,17 @@ func readLabels(asm *assembler) error {
 	}
 }
 
+// Labels returns the addresses of all labels declared in the given
+// macro expanded assembly (.dexp).
+func Labels(r io.Reader) (map[string]Word, error) {
+	asm := newAssembler(r, io.Discard)
+	if err := readLabels(asm); err != nil && !errors.Is(err, io.EOF) {
+		return nil, scanError(asm, err)
+	}
+
+	return asm.labels, nil
+}
+
 func resolveLabel(asm *assembler) error {
 	token, err := asm.scanner.Token()
 	if err != nil {
@@ -640,24 +680,95 @@ func GenerateMachineCode(r io.Reader, w io.Writer) error {
 	}
 }
 
-func Assemble(r io.Reader) (dexp, dins string, dopc []byte, err error) {
+// Program is the result of assembling a single source file.
+type Program struct {
+	Dexp      string
+	Dins      string
+	Dopc      []byte
+	Labels    Symbols
+	SourceMap *SourceMap
+	// Optimization is only set if the program has been optimized.
+	Optimization *OptimizerReport
+}
+
+// AssemblerOptions enables optional steps of the assembler.
+type AssemblerOptions struct {
+	// Optimize runs the optimizer after expanding macros.
+	Optimize bool
+}
+
+// AssembleProgram assembles the source read from r like Assemble but
+// additionally returns the declared labels and a source map that
+// refers to the given file name.
+func AssembleProgram(file string, r io.Reader) (Program, error) {
+	return AssembleProgramWithOptions(file, r, AssemblerOptions{})
+}
+
+// AssembleProgramWithOptions is like AssembleProgram but runs the
+// optional steps enabled by opts. The labels and the source map
+// always refer to the final machine code.
+func AssembleProgramWithOptions(file string, r io.Reader, opts AssemblerOptions) (Program, error) {
 	out := bytes.Buffer{}
-	if err := ExpandMacros(r, &out); err != nil {
-		return "", "", nil, err
+	asm := newAssembler(r, nil)
+	sourceMap := &SourceMap{}
+	writer := &sourceMapWriter{w: &out, asm: asm, file: file, sourceMap: sourceMap}
+	asm.writer = writer
+
+	if err := expandAllMacros(asm); err != nil {
+		return Program{}, err
 	}
-	dexp = out.String()
+	writer.flush()
+	dexp := out.String()
 
-	out.Reset()
-	if err := ResolveLabels(bytes.NewBufferString(dexp), &out); err != nil {
-		return "", "", nil, err
+	code := dexp
+	var report *OptimizerReport
+	if opts.Optimize {
+		nodes, err := parseNodes(dexp)
+		if err != nil {
+			return Program{}, err
+		}
+
+		nodes, r := optimizeNodes(nodes)
+		out = bytes.Buffer{}
+		if err := writeNodes(&out, nodes); err != nil {
+			return Program{}, err
+		}
+		code = out.String()
+		sourceMap = remapSourceMap(sourceMap, nodes)
+		report = &r
+	}
+
+	labels, err := Labels(bytes.NewBufferString(code))
+	if err != nil {
+		return Program{}, err
+	}
+
+	out = bytes.Buffer{}
+	if err := ResolveLabels(bytes.NewBufferString(code), &out); err != nil {
+		return Program{}, err
 	}
-	dins = out.String()
+	dins := out.String()
 
-	out.Reset()
+	out = bytes.Buffer{}
 	if err := GenerateMachineCode(bytes.NewBufferString(dins), &out); err != nil {
+		return Program{}, err
+	}
+
+	return Program{
+		Dexp:         dexp,
+		Dins:         dins,
+		Dopc:         out.Bytes(),
+		Labels:       labels,
+		SourceMap:    sourceMap,
+		Optimization: report,
+	}, nil
+}
+
+func Assemble(r io.Reader) (dexp, dins string, dopc []byte, err error) {
+	program, err := AssembleProgram("", r)
+	if err != nil {
 		return "", "", nil, err
 	}
-	dopc = out.Bytes()
 
-	return
+	return program.Dexp, program.Dins, program.Dopc, nil
 }
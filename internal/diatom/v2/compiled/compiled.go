@@ -0,0 +1,7 @@
+// Package compiled holds diatom programs compiled to Go with
+// 'diatom compile --target go'. They are used to compare the compiled
+// code with the interpreter.
+package compiled
+
+//go:generate go run github.com/eldelto/core/cmd/diatom compile --target go --package compiled --name Fib -o fib.go fib.dasm
+//go:generate go run github.com/eldelto/core/cmd/diatom compile --target go --package compiled --name Interpreter -o interpreter.go --repl
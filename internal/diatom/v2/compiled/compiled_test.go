@@ -0,0 +1,124 @@
+package compiled
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/eldelto/core/internal/diatom/v2"
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+// stdlibProgram evaluates the standard library and a few words that
+// define, compile and run new code.
+var stdlibProgram = diatom.Stdlib + `
+: count-down begin . 1- dup 0 = until ;
+: test ." fact:" 7 factorial . drop 5 count-down ;
+test 1 2 <> ' 1+ 3 times`
+
+func run(t testing.TB, program diatom.CompiledProgram, input string,
+	compiled bool) (*diatom.VM, string, error) {
+	output := &bytes.Buffer{}
+	vm, err := diatom.NewVM(program.Image, strings.NewReader(input), output)
+	AssertNoError(t, err, "NewVM")
+	AssertNoError(t, vm.RegisterExtension(diatom.NewTaskExtension()), "vm.RegisterExtension")
+
+	if compiled {
+		err = vm.ExecuteCompiled(program)
+	} else {
+		err = vm.Execute()
+	}
+	return vm, output.String(), err
+}
+
+func TestCompiled(t *testing.T) {
+	tests := []struct {
+		name    string
+		program diatom.CompiledProgram
+		input   string
+	}{
+		{"fib", Fib, ""},
+		{"stdlib", Interpreter, stdlibProgram + "\n"},
+		{"tasks", Interpreter, diatom.Stdlib + "\n: a 65 emit task.yield 66 emit ; ' a task.spawn task.yield 67 emit\n"},
+		{"undefined word", Interpreter, "1 2 nope +\n"},
+		{"stack underflow", Interpreter, "drop\n"},
+		{"division by zero", Interpreter, "1 0 /\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, wantOutput, wantErr := run(t, tt.program, tt.input, false)
+			vm, output, err := run(t, tt.program, tt.input, true)
+
+			AssertEquals(t, wantOutput, output, "output")
+			AssertEquals(t, want.DataStack(), vm.DataStack(), "vm.DataStack")
+			AssertEquals(t, want.ReturnStack(), vm.ReturnStack(), "vm.ReturnStack")
+			if wantErr != nil {
+				// Only the interpreter appends the execution trace.
+				AssertError(t, err, "vm.ExecuteCompiled")
+				AssertStringContains(t, err.Error(), wantErr.Error(), "error")
+				return
+			}
+			AssertNoError(t, err, "vm.ExecuteCompiled")
+			AssertEquals(t, want.Snapshot(), vm.Snapshot(), "vm.Snapshot")
+		})
+	}
+}
+
+func TestGeneratedUpToDate(t *testing.T) {
+	fib, err := os.Open("fib.dasm")
+	AssertNoError(t, err, "os.Open")
+	defer fib.Close()
+	fibProgram, err := diatom.AssembleProgram("fib.dasm", fib)
+	AssertNoError(t, err, "AssembleProgram")
+
+	repl, err := diatom.AssembleRepl(diatom.VMOptions{})
+	AssertNoError(t, err, "AssembleRepl")
+
+	tests := []struct {
+		file    string
+		program diatom.Program
+		name    string
+	}{
+		{"fib.go", fibProgram, "Fib"},
+		{"interpreter.go", repl, "Interpreter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			want, err := os.ReadFile(tt.file)
+			AssertNoError(t, err, "os.ReadFile")
+
+			got := &bytes.Buffer{}
+			opts := diatom.CompilerOptions{Package: "compiled", Name: tt.name}
+			AssertNoError(t, diatom.CompileGo(tt.program.Dopc, tt.program.Labels, got, opts),
+				"CompileGo")
+			AssertEquals(t, string(want), got.String(), tt.file+" (run go generate)")
+		})
+	}
+}
+
+func benchmark(b *testing.B, program diatom.CompiledProgram, input string, compiled bool) {
+	for b.Loop() {
+		if _, _, err := run(b, program, input, compiled); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFibInterpreted(b *testing.B) {
+	benchmark(b, Fib, "", false)
+}
+
+func BenchmarkFibCompiled(b *testing.B) {
+	benchmark(b, Fib, "", true)
+}
+
+func BenchmarkStdlibInterpreted(b *testing.B) {
+	benchmark(b, Interpreter, stdlibProgram+"\n", false)
+}
+
+func BenchmarkStdlibCompiled(b *testing.B) {
+	benchmark(b, Interpreter, stdlibProgram+"\n", true)
+}
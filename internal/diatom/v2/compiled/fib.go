@@ -0,0 +1,127 @@
+// Code generated by diatom compile; DO NOT EDIT.
+
+package compiled
+
+import "github.com/eldelto/core/internal/diatom/v2"
+
+// Fib holds the compiled code of a 49 byte memory image.
+var Fib = diatom.CompiledProgram{
+	Image: []byte{
+		7, 0, 0, 0, 17, 5, 0, 0, 0, 11, 1, 8, 7, 0, 0, 0,
+		2, 28, 4, 0, 0, 0, 48, 8, 7, 0, 0, 0, 1, 20, 5, 0,
+		0, 0, 11, 10, 7, 0, 0, 0, 2, 20, 5, 0, 0, 0, 11, 19,
+		2,
+	},
+	Blocks: map[diatom.Word]diatom.CompiledFunc{
+		0:  fib_start,
+		10: fib_start,
+		11: fib_fib,
+		23: fib_fib,
+		35: fib_fib,
+		47: fib_fib,
+		48: fib_fib,
+	},
+}
+
+// fib_start is compiled from 'start'.
+func fib_start(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 0: // start
+			// 0 const
+			if err := m.Push(17); err != nil {
+				return 0, false, err
+			}
+			// 5 call
+			if err := m.PushReturn(10); err != nil {
+				return 5, false, err
+			}
+			return 11, false, nil
+		case 10:
+			// 10 exit
+			return 10, true, nil
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// fib_fib is compiled from 'fib'.
+func fib_fib(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 11: // fib
+			// 11 dup
+			if err := m.Dup(); err != nil {
+				return 11, false, err
+			}
+			// 12 const
+			if err := m.Push(2); err != nil {
+				return 12, false, err
+			}
+			// 17 <
+			if err := m.Lt(); err != nil {
+				return 17, false, err
+			}
+			// 18 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 18, false, err
+			} else if c != 0 {
+				pc = 48
+			} else {
+				pc = 23
+			}
+		case 23:
+			// 23 dup
+			if err := m.Dup(); err != nil {
+				return 23, false, err
+			}
+			// 24 const
+			if err := m.Push(1); err != nil {
+				return 24, false, err
+			}
+			// 29 -
+			if err := m.Sub(); err != nil {
+				return 29, false, err
+			}
+			// 30 call
+			if err := m.PushReturn(35); err != nil {
+				return 30, false, err
+			}
+			pc = 11
+		case 35:
+			// 35 swap
+			if err := m.Swap(); err != nil {
+				return 35, false, err
+			}
+			// 36 const
+			if err := m.Push(2); err != nil {
+				return 36, false, err
+			}
+			// 41 -
+			if err := m.Sub(); err != nil {
+				return 41, false, err
+			}
+			// 42 call
+			if err := m.PushReturn(47); err != nil {
+				return 42, false, err
+			}
+			pc = 11
+		case 47:
+			// 47 +
+			if err := m.Add(); err != nil {
+				return 47, false, err
+			}
+			pc = 48
+		case 48: // fib-end
+			// 48 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 48, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
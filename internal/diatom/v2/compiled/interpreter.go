@@ -0,0 +1,6513 @@
+// Code generated by diatom compile; DO NOT EDIT.
+
+package compiled
+
+import "github.com/eldelto/core/internal/diatom/v2"
+
+// Interpreter holds the compiled code of a 3817 byte memory image.
+var Interpreter = diatom.CompiledProgram{
+	Image: []byte{
+		3, 0, 0, 14, 206, 0, 0, 0, 0, 0, 1, 1, 64, 16, 2, 0,
+		0, 0, 5, 0, 1, 1, 33, 15, 2, 0, 0, 0, 15, 0, 1, 1,
+		43, 19, 2, 0, 0, 0, 25, 0, 1, 1, 45, 20, 2, 0, 0, 0,
+		35, 0, 1, 1, 42, 21, 2, 0, 0, 0, 45, 0, 1, 1, 47, 22,
+		2, 0, 0, 0, 55, 0, 1, 1, 37, 23, 2, 0, 0, 0, 65, 0,
+		3, 3, 100, 117, 112, 8, 2, 0, 0, 0, 75, 0, 4, 4, 100, 114,
+		111, 112, 9, 2, 0, 0, 0, 87, 0, 4, 4, 115, 119, 97, 112, 10,
+		2, 0, 0, 0, 100, 0, 4, 4, 111, 118, 101, 114, 11, 2, 0, 0,
+		0, 113, 0, 3, 3, 107, 101, 121, 30, 2, 0, 0, 0, 126, 0, 4,
+		4, 101, 109, 105, 116, 31, 2, 0, 0, 0, 138, 0, 1, 1, 61, 24,
+		2, 0, 0, 0, 151, 0, 1, 1, 126, 25, 2, 0, 0, 0, 161, 0,
+		1, 1, 38, 26, 2, 0, 0, 0, 171, 0, 1, 1, 124, 27, 2, 0,
+		0, 0, 181, 0, 1, 1, 60, 28, 2, 0, 0, 0, 191, 0, 1, 1,
+		62, 29, 2, 0, 0, 0, 201, 0, 4, 4, 114, 112, 111, 112, 13, 13,
+		10, 12, 2, 0, 0, 0, 211, 0, 5, 5, 114, 112, 117, 115, 104, 13,
+		10, 12, 12, 2, 0, 0, 0, 227, 0, 2, 2, 98, 64, 18, 2, 0,
+		0, 0, 244, 0, 2, 2, 98, 33, 17, 2, 0, 0, 0, 255, 0, 5,
+		5, 100, 101, 112, 116, 104, 33, 2, 0, 0, 1, 10, 0, 9, 9, 119,
+		111, 114, 100, 45, 115, 105, 122, 101, 7, 0, 0, 0, 4, 2, 0, 0,
+		1, 24, 0, 11, 11, 109, 101, 109, 111, 114, 121, 46, 115, 105, 122, 101,
+		7, 0, 0, 32, 0, 2, 0, 0, 1, 46, 0, 10, 10, 115, 116, 97,
+		99, 107, 46, 115, 105, 122, 101, 7, 0, 0, 0, 20, 2, 0, 0, 1,
+		70, 0, 11, 11, 114, 115, 116, 97, 99, 107, 46, 115, 105, 122, 101, 7,
+		0, 0, 0, 20, 2, 0, 0, 1, 93, 0, 2, 2, 119, 43, 7, 0,
+		0, 0, 4, 19, 2, 0, 0, 1, 117, 0, 4, 4, 116, 114, 117, 101,
+		7, 255, 255, 255, 255, 2, 0, 0, 1, 133, 0, 5, 5, 102, 97, 108,
+		115, 101, 7, 0, 0, 0, 0, 2, 0, 0, 1, 150, 0, 5, 5, 39,
+		114, 101, 116, 39, 7, 0, 0, 0, 2, 2, 0, 0, 1, 168, 0, 5,
+		5, 39, 106, 109, 112, 39, 7, 0, 0, 0, 3, 2, 0, 0, 1, 186,
+		0, 6, 6, 39, 99, 106, 109, 112, 39, 7, 0, 0, 0, 4, 2, 0,
+		0, 1, 204, 0, 6, 6, 39, 99, 97, 108, 108, 39, 7, 0, 0, 0,
+		5, 2, 0, 0, 1, 223, 0, 7, 7, 39, 99, 111, 110, 115, 116, 39,
+		7, 0, 0, 0, 7, 2, 0, 0, 1, 242, 0, 3, 3, 39, 61, 39,
+		7, 0, 0, 0, 24, 2, 0, 0, 2, 6, 0, 17, 17, 117, 110, 115,
+		97, 102, 101, 46, 115, 116, 97, 99, 107, 45, 99, 97, 108, 108, 12, 2,
+		8, 7, 255, 255, 255, 255, 29, 7, 0, 0, 0, 1, 19, 10, 10, 7,
+		0, 0, 0, 1, 19, 10, 7, 0, 0, 0, 10, 22, 8, 7, 0, 0,
+		0, 0, 24, 25, 4, 0, 0, 2, 62, 9, 2, 11, 11, 28, 4, 0,
+		0, 2, 101, 9, 2, 10, 9, 2, 11, 11, 29, 4, 0, 0, 2, 101,
+		9, 2, 10, 9, 2, 8, 7, 0, 0, 0, 0, 28, 4, 0, 0, 2,
+		130, 2, 7, 255, 255, 255, 255, 21, 2, 7, 127, 255, 255, 255, 2, 7,
+		128, 0, 0, 0, 2, 8, 5, 0, 0, 2, 137, 24, 10, 5, 0, 0,
+		2, 143, 24, 27, 2, 8, 7, 0, 0, 0, 0, 28, 4, 0, 0, 2,
+		178, 2, 7, 255, 255, 255, 255, 21, 2, 5, 0, 0, 2, 117, 11, 7,
+		0, 0, 0, 1, 19, 17, 8, 7, 0, 0, 0, 0, 10, 17, 2, 18,
+		2, 7, 0, 0, 0, 1, 19, 18, 2, 8, 18, 7, 0, 0, 0, 1,
+		19, 11, 7, 0, 0, 0, 1, 19, 18, 23, 11, 11, 10, 17, 19, 7,
+		0, 0, 0, 1, 19, 17, 2, 10, 11, 18, 23, 5, 0, 0, 2, 117,
+		19, 7, 0, 0, 0, 2, 19, 2, 5, 0, 0, 2, 247, 18, 2, 5,
+		0, 0, 2, 247, 17, 2, 7, 0, 0, 0, 0, 10, 17, 2, 11, 5,
+		0, 0, 2, 207, 11, 5, 0, 0, 2, 207, 8, 7, 0, 0, 0, 1,
+		20, 12, 24, 25, 4, 0, 0, 3, 110, 11, 14, 10, 5, 0, 0, 3,
+		8, 11, 14, 10, 5, 0, 0, 3, 8, 24, 25, 4, 0, 0, 3, 110,
+		13, 7, 0, 0, 0, 1, 20, 12, 14, 7, 255, 255, 255, 255, 29, 4,
+		0, 0, 3, 57, 13, 9, 9, 9, 5, 0, 0, 1, 144, 2, 13, 9,
+		9, 9, 5, 0, 0, 1, 162, 2, 11, 5, 0, 0, 2, 209, 11, 5,
+		0, 0, 2, 209, 5, 0, 0, 2, 104, 12, 11, 5, 0, 0, 2, 207,
+		11, 5, 0, 0, 2, 207, 5, 0, 0, 2, 91, 14, 5, 0, 0, 2,
+		104, 11, 17, 14, 7, 0, 0, 0, 1, 28, 4, 0, 0, 3, 204, 11,
+		14, 10, 5, 0, 0, 3, 8, 11, 14, 10, 5, 0, 0, 3, 15, 13,
+		7, 0, 0, 0, 1, 20, 12, 3, 0, 0, 3, 163, 13, 9, 9, 9,
+		2, 7, 0, 0, 0, 33, 28, 2, 8, 7, 0, 0, 0, 47, 29, 10,
+		7, 0, 0, 0, 58, 28, 26, 2, 7, 0, 0, 0, 0, 10, 5, 0,
+		0, 3, 8, 7, 0, 0, 0, 45, 24, 4, 0, 0, 4, 4, 7, 0,
+		0, 0, 1, 2, 7, 255, 255, 255, 255, 2, 8, 5, 0, 0, 3, 232,
+		10, 7, 0, 0, 0, 0, 12, 7, 0, 0, 0, 0, 11, 5, 0, 0,
+		2, 207, 14, 29, 25, 4, 0, 0, 4, 121, 7, 0, 0, 0, 10, 21,
+		11, 14, 10, 5, 0, 0, 3, 8, 8, 7, 0, 0, 0, 45, 24, 4,
+		0, 0, 4, 100, 8, 5, 0, 0, 3, 216, 25, 4, 0, 0, 4, 114,
+		7, 0, 0, 0, 48, 20, 19, 13, 7, 0, 0, 0, 1, 19, 12, 3,
+		0, 0, 4, 28, 9, 13, 7, 0, 0, 0, 1, 19, 12, 3, 0, 0,
+		4, 28, 9, 9, 5, 0, 0, 2, 143, 13, 9, 10, 9, 21, 2, 7,
+		0, 0, 0, 0, 12, 8, 5, 0, 0, 2, 207, 14, 29, 25, 4, 0,
+		0, 4, 168, 14, 11, 5, 0, 0, 3, 8, 31, 13, 7, 0, 0, 0,
+		1, 19, 12, 3, 0, 0, 4, 133, 13, 9, 9, 2, 5, 0, 0, 2,
+		165, 7, 0, 0, 0, 10, 23, 7, 0, 0, 0, 48, 19, 2, 12, 12,
+		5, 0, 0, 4, 172, 13, 13, 5, 0, 0, 3, 15, 2, 12, 8, 5,
+		0, 0, 2, 48, 8, 14, 17, 7, 0, 0, 0, 1, 20, 11, 11, 14,
+		5, 0, 0, 4, 190, 8, 7, 0, 0, 0, 0, 24, 4, 0, 0, 4,
+		254, 10, 7, 0, 0, 0, 10, 22, 10, 3, 0, 0, 4, 215, 9, 9,
+		7, 0, 0, 0, 0, 14, 5, 0, 0, 3, 8, 7, 0, 0, 0, 48,
+		24, 14, 5, 0, 0, 2, 207, 7, 0, 0, 0, 1, 29, 26, 4, 0,
+		0, 5, 38, 13, 9, 2, 7, 0, 0, 0, 45, 7, 0, 0, 0, 0,
+		13, 5, 0, 0, 3, 15, 2, 0, 0, 2, 22, 0, 11, 11, 119, 111,
+		114, 100, 46, 98, 117, 102, 102, 101, 114, 7, 0, 0, 5, 79, 2, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 30, 8, 5, 0, 0, 3, 209, 25, 4, 0, 0, 5, 132, 9, 3,
+		0, 0, 5, 113, 2, 0, 0, 5, 55, 0, 9, 9, 119, 111, 114, 100,
+		46, 114, 101, 97, 100, 7, 0, 0, 5, 79, 7, 0, 0, 0, 32, 5,
+		0, 0, 2, 185, 12, 5, 0, 0, 5, 113, 14, 5, 0, 0, 2, 217,
+		30, 8, 5, 0, 0, 3, 209, 25, 4, 0, 0, 5, 170, 9, 13, 9,
+		2, 0, 0, 5, 133, 0, 10, 10, 119, 111, 114, 100, 46, 112, 114, 105,
+		110, 116, 7, 0, 0, 5, 79, 5, 0, 0, 4, 127, 2, 5, 0, 0,
+		5, 73, 5, 0, 0, 4, 205, 5, 0, 0, 5, 210, 2, 0, 0, 5,
+		193, 0, 1, 1, 46, 8, 5, 0, 0, 5, 221, 7, 0, 0, 0, 10,
+		31, 2, 0, 0, 5, 237, 0, 11, 11, 115, 116, 97, 99, 107, 46, 112,
+		114, 105, 110, 116, 7, 0, 0, 0, 60, 31, 33, 5, 0, 0, 5, 221,
+		7, 0, 0, 0, 62, 31, 7, 0, 0, 0, 0, 33, 7, 0, 0, 0,
+		1, 24, 4, 0, 0, 6, 74, 10, 11, 5, 0, 0, 6, 125, 15, 7,
+		0, 0, 0, 1, 19, 3, 0, 0, 6, 43, 7, 0, 0, 0, 1, 20,
+		8, 7, 0, 0, 0, 0, 28, 4, 0, 0, 6, 117, 8, 5, 0, 0,
+		6, 125, 16, 7, 0, 0, 0, 32, 31, 8, 5, 0, 0, 5, 221, 10,
+		3, 0, 0, 6, 74, 9, 7, 0, 0, 0, 10, 31, 2, 5, 0, 0,
+		1, 40, 21, 5, 0, 0, 8, 34, 16, 19, 2, 0, 0, 6, 2, 0,
+		10, 10, 119, 111, 114, 100, 46, 102, 108, 97, 103, 115, 5, 0, 0, 1,
+		126, 2, 0, 0, 6, 139, 0, 9, 9, 119, 111, 114, 100, 46, 110, 97,
+		109, 101, 5, 0, 0, 1, 126, 7, 0, 0, 0, 1, 19, 2, 0, 0,
+		6, 162, 0, 9, 9, 119, 111, 114, 100, 46, 99, 111, 100, 101, 5, 0,
+		0, 6, 178, 8, 18, 7, 0, 0, 0, 2, 19, 19, 2, 0, 0, 6,
+		190, 0, 14, 14, 119, 111, 114, 100, 46, 105, 109, 109, 101, 100, 105, 97,
+		116, 101, 5, 0, 0, 14, 196, 16, 5, 0, 0, 6, 156, 8, 18, 7,
+		0, 0, 0, 2, 27, 10, 17, 2, 0, 0, 6, 221, 0, 15, 15, 119,
+		111, 114, 100, 46, 105, 109, 109, 101, 100, 105, 97, 116, 101, 63, 5, 0,
+		0, 6, 156, 18, 7, 0, 0, 0, 2, 26, 7, 0, 0, 0, 2, 24,
+		2, 0, 0, 7, 8, 0, 9, 9, 119, 111, 114, 100, 46, 104, 105, 100,
+		101, 5, 0, 0, 14, 196, 16, 5, 0, 0, 6, 156, 8, 18, 7, 0,
+		0, 0, 1, 27, 10, 17, 2, 0, 0, 7, 49, 0, 11, 11, 119, 111,
+		114, 100, 46, 117, 110, 104, 105, 100, 101, 5, 0, 0, 14, 196, 16, 5,
+		0, 0, 6, 156, 8, 18, 7, 0, 0, 0, 254, 26, 10, 17, 2, 0,
+		0, 7, 87, 0, 12, 12, 119, 111, 114, 100, 46, 104, 105, 100, 100, 101,
+		110, 63, 5, 0, 0, 6, 156, 18, 7, 0, 0, 0, 1, 26, 7, 0,
+		0, 0, 1, 24, 2, 0, 0, 7, 127, 0, 9, 9, 119, 111, 114, 100,
+		46, 102, 105, 110, 100, 5, 0, 0, 14, 196, 16, 12, 14, 7, 0, 0,
+		0, 0, 24, 4, 0, 0, 7, 237, 14, 5, 0, 0, 6, 178, 5, 0,
+		0, 5, 73, 5, 0, 0, 3, 30, 14, 5, 0, 0, 7, 146, 25, 26,
+		4, 0, 0, 7, 237, 13, 16, 12, 3, 0, 0, 7, 188, 13, 2, 0,
+		0, 7, 165, 0, 18, 18, 119, 111, 114, 100, 46, 99, 111, 109, 112, 105,
+		108, 101, 45, 115, 116, 97, 116, 101, 7, 0, 0, 8, 14, 2, 0, 0,
+		0, 0, 0, 0, 7, 239, 0, 9, 9, 119, 111, 114, 100, 46, 104, 101,
+		114, 101, 7, 0, 0, 8, 40, 2, 0, 0, 0, 0, 5, 0, 0, 8,
+		34, 16, 15, 5, 0, 0, 8, 34, 8, 16, 5, 0, 0, 1, 126, 10,
+		15, 2, 5, 0, 0, 8, 34, 16, 17, 5, 0, 0, 8, 34, 8, 16,
+		7, 0, 0, 0, 1, 19, 10, 15, 2, 5, 0, 0, 8, 34, 16, 5,
+		0, 0, 14, 196, 16, 5, 0, 0, 8, 44, 5, 0, 0, 14, 196, 15,
+		7, 0, 0, 0, 0, 5, 0, 0, 8, 66, 5, 0, 0, 5, 73, 5,
+		0, 0, 8, 34, 16, 11, 5, 0, 0, 2, 207, 5, 0, 0, 2, 185,
+		5, 0, 0, 3, 120, 5, 0, 0, 14, 196, 16, 5, 0, 0, 6, 206,
+		5, 0, 0, 8, 34, 15, 2, 0, 0, 8, 18, 2, 1, 1, 91, 5,
+		0, 0, 1, 162, 5, 0, 0, 8, 8, 15, 2, 0, 0, 8, 167, 2,
+		1, 1, 93, 5, 0, 0, 1, 144, 5, 0, 0, 8, 8, 15, 2, 0,
+		0, 8, 187, 0, 1, 1, 58, 5, 0, 0, 5, 149, 5, 0, 0, 8,
+		89, 5, 0, 0, 7, 65, 5, 0, 0, 8, 195, 2, 0, 0, 8, 207,
+		2, 1, 1, 59, 5, 0, 0, 1, 180, 5, 0, 0, 8, 66, 5, 0,
+		0, 7, 105, 5, 0, 0, 8, 175, 2, 0, 0, 8, 236, 2, 9, 9,
+		105, 109, 109, 101, 100, 105, 97, 116, 101, 5, 0, 0, 6, 242, 2, 0,
+		0, 9, 9, 0, 1, 1, 44, 5, 0, 0, 8, 44, 2, 0, 0, 9,
+		31, 0, 2, 2, 98, 44, 5, 0, 0, 8, 66, 2, 0, 0, 9, 45,
+		0, 4, 4, 119, 111, 114, 100, 5, 0, 0, 5, 149, 5, 0, 0, 5,
+		73, 2, 0, 0, 9, 60, 0, 4, 4, 102, 105, 110, 100, 5, 0, 0,
+		5, 73, 5, 0, 0, 3, 120, 5, 0, 0, 7, 181, 2, 0, 0, 9,
+		82, 0, 6, 6, 110, 117, 109, 98, 101, 114, 5, 0, 0, 4, 10, 2,
+		0, 0, 9, 109, 2, 1, 1, 40, 30, 7, 0, 0, 0, 41, 24, 25,
+		4, 0, 0, 9, 136, 2, 5, 0, 0, 2, 0, 5, 0, 0, 8, 66,
+		7, 0, 0, 0, 0, 5, 0, 0, 8, 44, 5, 0, 0, 2, 16, 5,
+		0, 0, 8, 66, 2, 0, 0, 9, 128, 2, 2, 2, 105, 102, 5, 0,
+		0, 9, 150, 5, 0, 0, 1, 217, 5, 0, 0, 8, 66, 5, 0, 0,
+		8, 34, 16, 7, 0, 0, 0, 0, 5, 0, 0, 8, 44, 2, 0, 0,
+		9, 181, 2, 4, 4, 101, 108, 115, 101, 5, 0, 0, 1, 198, 5, 0,
+		0, 8, 66, 5, 0, 0, 8, 34, 16, 7, 0, 0, 0, 0, 5, 0,
+		0, 8, 44, 10, 5, 0, 0, 10, 21, 2, 0, 0, 9, 222, 2, 4,
+		4, 116, 104, 101, 110, 5, 0, 0, 8, 34, 16, 10, 15, 2, 0, 0,
+		10, 10, 2, 5, 5, 98, 101, 103, 105, 110, 5, 0, 0, 8, 34, 16,
+		2, 0, 0, 10, 30, 2, 5, 5, 117, 110, 116, 105, 108, 5, 0, 0,
+		9, 150, 5, 0, 0, 1, 217, 5, 0, 0, 8, 66, 5, 0, 0, 8,
+		44, 2, 0, 0, 10, 49, 2, 5, 5, 97, 103, 97, 105, 110, 5, 0,
+		0, 1, 198, 5, 0, 0, 8, 66, 5, 0, 0, 8, 44, 2, 0, 0,
+		10, 82, 0, 13, 13, 115, 116, 114, 105, 110, 103, 46, 98, 117, 102, 102,
+		101, 114, 7, 0, 0, 10, 136, 2, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 5, 0, 0, 2, 185, 30,
+		8, 7, 0, 0, 0, 34, 24, 4, 0, 0, 11, 39, 11, 5, 0, 0,
+		2, 217, 3, 0, 0, 11, 15, 9, 2, 5, 0, 0, 1, 198, 5, 0,
+		0, 8, 66, 5, 0, 0, 8, 34, 16, 7, 0, 0, 0, 0, 5, 0,
+		0, 8, 44, 5, 0, 0, 8, 34, 16, 7, 0, 0, 0, 255, 5, 0,
+		0, 11, 10, 8, 5, 0, 0, 2, 207, 11, 7, 0, 0, 0, 1, 19,
+		17, 8, 5, 0, 0, 2, 207, 7, 0, 0, 0, 2, 19, 5, 0, 0,
+		8, 34, 16, 19, 5, 0, 0, 8, 34, 15, 10, 5, 0, 0, 8, 34,
+		16, 10, 15, 5, 0, 0, 2, 0, 5, 0, 0, 8, 66, 5, 0, 0,
+		8, 44, 2, 0, 0, 10, 110, 2, 2, 2, 115, 34, 5, 0, 0, 8,
+		8, 16, 4, 0, 0, 11, 41, 5, 0, 0, 10, 130, 7, 0, 0, 0,
+		128, 5, 0, 0, 11, 10, 2, 0, 0, 11, 147, 2, 2, 2, 46, 34,
+		5, 0, 0, 8, 8, 16, 4, 0, 0, 11, 214, 5, 0, 0, 11, 156,
+		5, 0, 0, 4, 127, 2, 5, 0, 0, 11, 41, 5, 0, 0, 1, 236,
+		5, 0, 0, 8, 66, 7, 0, 0, 4, 127, 5, 0, 0, 8, 44, 2,
+		0, 0, 11, 183, 0, 14, 14, 119, 111, 114, 100, 46, 105, 110, 116, 101,
+		114, 112, 114, 101, 116, 5, 0, 0, 5, 149, 5, 0, 0, 7, 181, 8,
+		7, 0, 0, 0, 0, 24, 4, 0, 0, 12, 82, 8, 5, 0, 0, 6,
+		206, 10, 5, 0, 0, 7, 30, 25, 5, 0, 0, 8, 8, 16, 26, 4,
+		0, 0, 12, 62, 5, 0, 0, 2, 46, 3, 0, 0, 12, 5, 5, 0,
+		0, 1, 236, 5, 0, 0, 8, 66, 5, 0, 0, 8, 44, 3, 0, 0,
+		12, 5, 9, 5, 0, 0, 5, 73, 5, 0, 0, 4, 10, 8, 5, 0,
+		0, 2, 143, 24, 4, 0, 0, 12, 141, 5, 0, 0, 8, 8, 16, 4,
+		0, 0, 12, 121, 3, 0, 0, 12, 5, 5, 0, 0, 2, 0, 5, 0,
+		0, 8, 66, 5, 0, 0, 8, 44, 3, 0, 0, 12, 5, 7, 0, 0,
+		12, 151, 3, 0, 0, 12, 168, 15, 15, 87, 111, 114, 100, 32, 110, 111,
+		116, 32, 102, 111, 117, 110, 100, 46, 5, 0, 0, 4, 127, 2, 0, 0,
+		11, 240, 0, 14, 14, 102, 105, 108, 101, 46, 114, 101, 97, 100, 45, 111,
+		110, 108, 121, 7, 0, 0, 0, 0, 2, 0, 0, 12, 174, 0, 15, 15,
+		102, 105, 108, 101, 46, 119, 114, 105, 116, 101, 45, 111, 110, 108, 121, 7,
+		0, 0, 0, 1, 2, 0, 0, 12, 201, 0, 11, 11, 102, 105, 108, 101,
+		46, 97, 112, 112, 101, 110, 100, 7, 0, 0, 0, 2, 2, 0, 0, 12,
+		229, 0, 9, 9, 102, 105, 108, 101, 46, 111, 112, 101, 110, 7, 0, 1,
+		0, 0, 6, 2, 0, 0, 12, 253, 0, 10, 10, 102, 105, 108, 101, 46,
+		99, 108, 111, 115, 101, 7, 0, 1, 0, 1, 6, 2, 0, 0, 13, 20,
+		0, 9, 9, 102, 105, 108, 101, 46, 114, 101, 97, 100, 7, 0, 1, 0,
+		2, 6, 2, 0, 0, 13, 44, 0, 10, 10, 102, 105, 108, 101, 46, 119,
+		114, 105, 116, 101, 7, 0, 1, 0, 3, 6, 2, 0, 0, 13, 67, 0,
+		9, 9, 99, 108, 111, 99, 107, 46, 110, 111, 119, 7, 0, 2, 0, 0,
+		6, 2, 0, 0, 13, 91, 0, 12, 12, 99, 108, 111, 99, 107, 46, 109,
+		105, 108, 108, 105, 115, 7, 0, 2, 0, 1, 6, 2, 0, 0, 13, 114,
+		0, 11, 11, 99, 108, 111, 99, 107, 46, 115, 108, 101, 101, 112, 7, 0,
+		2, 0, 2, 6, 2, 0, 0, 13, 140, 0, 10, 10, 114, 97, 110, 100,
+		111, 109, 46, 105, 110, 116, 7, 0, 3, 0, 0, 6, 2, 0, 0, 13,
+		165, 0, 12, 12, 114, 97, 110, 100, 111, 109, 46, 98, 101, 108, 111, 119,
+		7, 0, 3, 0, 1, 6, 2, 0, 0, 13, 189, 0, 11, 11, 114, 97,
+		110, 100, 111, 109, 46, 115, 101, 101, 100, 7, 0, 3, 0, 2, 6, 2,
+		0, 0, 13, 215, 0, 13, 13, 102, 111, 114, 109, 97, 116, 46, 115, 116,
+		114, 105, 110, 103, 7, 0, 4, 0, 0, 6, 2, 0, 0, 13, 240, 0,
+		12, 12, 102, 111, 114, 109, 97, 116, 46, 112, 114, 105, 110, 116, 7, 0,
+		4, 0, 1, 6, 2, 1, 0, 0, 14, 11, 0, 10, 10, 116, 97, 115,
+		107, 46, 115, 112, 97, 119, 110, 7, 0, 0, 14, 37, 10, 7, 0, 5,
+		0, 0, 6, 2, 0, 0, 14, 38, 0, 10, 10, 116, 97, 115, 107, 46,
+		121, 105, 101, 108, 100, 7, 0, 5, 0, 1, 6, 2, 0, 0, 14, 68,
+		0, 9, 9, 116, 97, 115, 107, 46, 115, 101, 110, 100, 7, 0, 5, 0,
+		2, 6, 2, 0, 0, 14, 92, 0, 12, 12, 116, 97, 115, 107, 46, 114,
+		101, 99, 101, 105, 118, 101, 7, 0, 5, 0, 3, 6, 2, 0, 0, 14,
+		115, 0, 7, 7, 116, 97, 115, 107, 46, 105, 100, 7, 0, 5, 0, 4,
+		6, 2, 5, 0, 0, 8, 175, 5, 0, 0, 12, 5, 9, 3, 0, 0,
+		14, 162, 0, 0, 14, 141, 0, 11, 11, 119, 111, 114, 100, 46, 108, 97,
+		116, 101, 115, 116, 7, 0, 0, 14, 202, 2, 0, 0, 0, 0, 7, 0,
+		0, 14, 178, 7, 0, 0, 14, 202, 15, 7, 0, 0, 14, 233, 7, 0,
+		0, 8, 40, 15, 3, 0, 0, 14, 162,
+	},
+	Blocks: map[diatom.Word]diatom.CompiledFunc{
+		0:    interpreter_start,
+		13:   interpreter_x40,
+		23:   interpreter_x21,
+		33:   interpreter_x2b,
+		43:   interpreter__,
+		53:   interpreter_x2a,
+		63:   interpreter_x2f,
+		73:   interpreter_x25,
+		85:   interpreter_dup,
+		98:   interpreter_drop,
+		111:  interpreter_swap,
+		124:  interpreter_over,
+		136:  interpreter_key,
+		149:  interpreter_emit,
+		159:  interpreter_x3d,
+		169:  interpreter_x7e,
+		179:  interpreter_x26,
+		189:  interpreter_x7c,
+		199:  interpreter_x3c,
+		209:  interpreter_x3e,
+		222:  interpreter_rpop,
+		239:  interpreter_rpush,
+		253:  interpreter_bx40,
+		264:  interpreter_bx21,
+		278:  interpreter_depth,
+		296:  interpreter_word_size,
+		320:  interpreter_memory_size,
+		343:  interpreter_stack_size,
+		367:  interpreter_rstack_size,
+		382:  interpreter_wx2b,
+		400:  interpreter_true,
+		418:  interpreter_false,
+		436:  interpreter_x27retx27,
+		454:  interpreter_x27jmpx27,
+		473:  interpreter_x27cjmpx27,
+		492:  interpreter_x27callx27,
+		512:  interpreter_x27constx27,
+		528:  interpreter_x27x3dx27,
+		558:  interpreter_unsafe_stack_call,
+		560:  interpreter_int_digit_count,
+		574:  interpreter_int_digit_count,
+		601:  interpreter_int_digit_count,
+		603:  interpreter_math_max,
+		611:  interpreter_math_max,
+		613:  interpreter_math_max,
+		616:  interpreter_math_min,
+		624:  interpreter_math_min,
+		626:  interpreter_math_min,
+		629:  interpreter_math_abs,
+		641:  interpreter_math_abs,
+		642:  interpreter_math_abs,
+		649:  interpreter_math_int_max,
+		655:  interpreter_math_int_min,
+		661:  interpreter_math_saturatedx3f,
+		667:  interpreter_math_saturatedx3f,
+		674:  interpreter_math_saturatedx3f,
+		677:  interpreter_math_absolute,
+		689:  interpreter_math_absolute,
+		690:  interpreter_math_absolute,
+		697:  interpreter_array_init,
+		702:  interpreter_array_init,
+		719:  interpreter_array_length,
+		721:  interpreter_array_capacity,
+		729:  interpreter_array_append,
+		759:  interpreter_array_indexed,
+		768:  interpreter_array_indexed,
+		776:  interpreter_array_get,
+		781:  interpreter_array_get,
+		783:  interpreter_array_set,
+		788:  interpreter_array_set,
+		790:  interpreter_array_clear,
+		798:  interpreter_array_equalx3f,
+		804:  interpreter_array_equalx3f,
+		810:  interpreter_array_equalx3f,
+		825:  interpreter_array_equalx3f,
+		833:  interpreter_array_equalx3f,
+		841:  interpreter_array_equalx3f,
+		848:  interpreter_array_equalx3f,
+		868:  interpreter_array_equalx3f,
+		877:  interpreter_array_equalx3f,
+		878:  interpreter_array_equalx3f,
+		887:  interpreter_array_equalx3f,
+		888:  interpreter_array_copy,
+		894:  interpreter_array_copy,
+		900:  interpreter_array_copy,
+		905:  interpreter_array_copy,
+		912:  interpreter_array_copy,
+		918:  interpreter_array_copy,
+		923:  interpreter_array_copy,
+		929:  interpreter_array_copy,
+		931:  interpreter_array_copy,
+		943:  interpreter_array_copy,
+		951:  interpreter_array_copy,
+		959:  interpreter_array_copy,
+		972:  interpreter_array_copy,
+		977:  interpreter_char_blankx3f,
+		984:  interpreter_char_numberx3f,
+		1000: interpreter_string_sign,
+		1011: interpreter_string_sign,
+		1022: interpreter_string_sign,
+		1028: interpreter_string_sign,
+		1034: interpreter_string_parse_number,
+		1040: interpreter_string_parse_number,
+		1052: interpreter_string_parse_number,
+		1058: interpreter_string_parse_number,
+		1066: interpreter_string_parse_number,
+		1080: interpreter_string_parse_number,
+		1092: interpreter_string_parse_number,
+		1098: interpreter_string_parse_number,
+		1104: interpreter_string_parse_number,
+		1124: interpreter_string_parse_number,
+		1138: interpreter_string_parse_number,
+		1145: interpreter_string_parse_number,
+		1151: interpreter_string_print,
+		1157: interpreter_string_print,
+		1163: interpreter_string_print,
+		1171: interpreter_string_print,
+		1178: interpreter_string_print,
+		1192: interpreter_string_print,
+		1196: interpreter_last_digit_to_char,
+		1201: interpreter_last_digit_to_char,
+		1214: interpreter_store_digit,
+		1221: interpreter_store_digit,
+		1228: interpreter_store_digit,
+		1229: interpreter_string_from_number,
+		1236: interpreter_string_from_number,
+		1239: interpreter_string_from_number,
+		1253: interpreter_string_from_number,
+		1265: interpreter_string_from_number,
+		1278: interpreter_string_from_number,
+		1291: interpreter_string_from_number,
+		1303: interpreter_string_from_number,
+		1315: interpreter_string_from_number,
+		1318: interpreter_string_from_number,
+		1334: interpreter_string_from_number,
+		1353: interpreter_word_buffer,
+		1393: interpreter_non_blank_key,
+		1400: interpreter_non_blank_key,
+		1406: interpreter_non_blank_key,
+		1412: interpreter_non_blank_key,
+		1429: interpreter_word_read,
+		1444: interpreter_word_read,
+		1450: interpreter_word_read,
+		1456: interpreter_word_read,
+		1463: interpreter_word_read,
+		1469: interpreter_word_read,
+		1490: interpreter_word_print,
+		1500: interpreter_word_print,
+		1501: interpreter_number_print,
+		1506: interpreter_number_print,
+		1511: interpreter_number_print,
+		1516: interpreter_number_print,
+		1525: interpreter___1525,
+		1531: interpreter___1525,
+		1556: interpreter_stack_print,
+		1568: interpreter_stack_print,
+		1579: interpreter_stack_print,
+		1591: interpreter_stack_print,
+		1598: interpreter_stack_print,
+		1610: interpreter_stack_print,
+		1628: interpreter_stack_print,
+		1634: interpreter_stack_print,
+		1647: interpreter_stack_print,
+		1653: interpreter_stack_print,
+		1661: interpreter_stack_print,
+		1666: interpreter_stack_print,
+		1672: interpreter_stack_print,
+		1692: interpreter_word_flags,
+		1697: interpreter_word_flags,
+		1714: interpreter_word_name,
+		1719: interpreter_word_name,
+		1742: interpreter_word_code,
+		1747: interpreter_word_code,
+		1778: interpreter_word_immediate,
+		1783: interpreter_word_immediate,
+		1789: interpreter_word_immediate,
+		1822: interpreter_word_immediatex3f,
+		1827: interpreter_word_immediatex3f,
+		1857: interpreter_word_hide,
+		1862: interpreter_word_hide,
+		1868: interpreter_word_hide,
+		1897: interpreter_word_unhide,
+		1902: interpreter_word_unhide,
+		1908: interpreter_word_unhide,
+		1938: interpreter_word_hiddenx3f,
+		1943: interpreter_word_hiddenx3f,
+		1973: interpreter_word_find,
+		1978: interpreter_word_find,
+		1980: interpreter_word_find,
+		1992: interpreter_word_find,
+		1998: interpreter_word_find,
+		2003: interpreter_word_find,
+		2008: interpreter_word_find,
+		2014: interpreter_word_find,
+		2021: interpreter_word_find,
+		2029: interpreter_word_find,
+		2056: interpreter_word_compile_state,
+		2082: interpreter_word_here,
+		2092: interpreter_word_append,
+		2097: interpreter_word_append,
+		2104: interpreter_word_append,
+		2111: interpreter_word_append,
+		2114: interpreter_word_append,
+		2119: interpreter_word_append,
+		2126: interpreter_word_append,
+		2137: interpreter_word_create_header,
+		2142: interpreter_word_create_header,
+		2148: interpreter_word_create_header,
+		2154: interpreter_word_create_header,
+		2159: interpreter_word_create_header,
+		2170: interpreter_word_create_header,
+		2175: interpreter_word_create_header,
+		2180: interpreter_word_create_header,
+		2187: interpreter_word_create_header,
+		2192: interpreter_word_create_header,
+		2197: interpreter_word_create_header,
+		2202: interpreter_word_create_header,
+		2208: interpreter_word_create_header,
+		2213: interpreter_word_create_header,
+		2223: interpreter_x5b,
+		2228: interpreter_x5b,
+		2233: interpreter_x5b,
+		2243: interpreter_x5d,
+		2248: interpreter_x5d,
+		2253: interpreter_x5d,
+		2263: interpreter_x3a,
+		2268: interpreter_x3a,
+		2273: interpreter_x3a,
+		2278: interpreter_x3a,
+		2283: interpreter_x3a,
+		2292: interpreter_x3b,
+		2297: interpreter_x3b,
+		2302: interpreter_x3b,
+		2307: interpreter_x3b,
+		2312: interpreter_x3b,
+		2329: interpreter_immediate,
+		2334: interpreter_immediate,
+		2343: interpreter_x2c,
+		2348: interpreter_x2c,
+		2358: interpreter_bx2c,
+		2363: interpreter_bx2c,
+		2375: interpreter_word,
+		2380: interpreter_word,
+		2385: interpreter_word,
+		2397: interpreter_find,
+		2402: interpreter_find,
+		2407: interpreter_find,
+		2412: interpreter_find,
+		2426: interpreter_number,
+		2431: interpreter_number,
+		2440: interpreter_x28,
+		2453: interpreter_x28,
+		2454: interpreter_compile_falsex3f,
+		2459: interpreter_compile_falsex3f,
+		2464: interpreter_compile_falsex3f,
+		2474: interpreter_compile_falsex3f,
+		2479: interpreter_compile_falsex3f,
+		2484: interpreter_compile_falsex3f,
+		2494: interpreter_if,
+		2499: interpreter_if,
+		2504: interpreter_if,
+		2509: interpreter_if,
+		2514: interpreter_if,
+		2525: interpreter_if,
+		2537: interpreter_else,
+		2542: interpreter_else,
+		2547: interpreter_else,
+		2552: interpreter_else,
+		2563: interpreter_else,
+		2569: interpreter_else,
+		2581: interpreter_then,
+		2586: interpreter_then,
+		2602: interpreter_begin,
+		2607: interpreter_begin,
+		2621: interpreter_until,
+		2626: interpreter_until,
+		2631: interpreter_until,
+		2636: interpreter_until,
+		2641: interpreter_until,
+		2654: interpreter_again,
+		2659: interpreter_again,
+		2664: interpreter_again,
+		2669: interpreter_again,
+		2690: interpreter_string_buffer,
+		2826: interpreter_string_read,
+		2831: interpreter_string_read,
+		2844: interpreter_string_read,
+		2850: interpreter_string_read,
+		2855: interpreter_string_read,
+		2857: interpreter_string_compile,
+		2862: interpreter_string_compile,
+		2867: interpreter_string_compile,
+		2872: interpreter_string_compile,
+		2883: interpreter_string_compile,
+		2888: interpreter_string_compile,
+		2899: interpreter_string_compile,
+		2905: interpreter_string_compile,
+		2919: interpreter_string_compile,
+		2930: interpreter_string_compile,
+		2937: interpreter_string_compile,
+		2944: interpreter_string_compile,
+		2952: interpreter_string_compile,
+		2957: interpreter_string_compile,
+		2962: interpreter_string_compile,
+		2972: interpreter_sx22,
+		2977: interpreter_sx22,
+		2983: interpreter_sx22,
+		2988: interpreter_sx22,
+		2998: interpreter_sx22,
+		3008: interpreter__x22,
+		3013: interpreter__x22,
+		3019: interpreter__x22,
+		3024: interpreter__x22,
+		3029: interpreter__x22,
+		3030: interpreter_string_print_compile,
+		3035: interpreter_string_print_compile,
+		3040: interpreter_string_print_compile,
+		3045: interpreter_string_print_compile,
+		3055: interpreter_string_print_compile,
+		3077: interpreter_word_interpret,
+		3082: interpreter_word_interpret,
+		3087: interpreter_word_interpret,
+		3099: interpreter_word_interpret,
+		3105: interpreter_word_interpret,
+		3111: interpreter_word_interpret,
+		3117: interpreter_word_interpret,
+		3124: interpreter_word_interpret,
+		3129: interpreter_word_interpret,
+		3134: interpreter_word_interpret,
+		3139: interpreter_word_interpret,
+		3144: interpreter_word_interpret,
+		3149: interpreter_word_interpret,
+		3154: interpreter_word_interpret,
+		3160: interpreter_word_interpret,
+		3165: interpreter_word_interpret,
+		3171: interpreter_word_interpret,
+		3177: interpreter_word_interpret,
+		3182: interpreter_word_interpret,
+		3188: interpreter_word_interpret,
+		3193: interpreter_word_interpret,
+		3198: interpreter_word_interpret,
+		3203: interpreter_word_interpret,
+		3208: interpreter_word_interpret,
+		3213: interpreter_word_interpret,
+		3240: interpreter_word_interpret,
+		3245: interpreter_word_interpret,
+		3267: interpreter_file_read_only,
+		3295: interpreter_file_write_only,
+		3319: interpreter_file_append,
+		3341: interpreter_file_open,
+		3347: interpreter_file_open,
+		3365: interpreter_file_close,
+		3371: interpreter_file_close,
+		3388: interpreter_file_read,
+		3394: interpreter_file_read,
+		3412: interpreter_file_write,
+		3418: interpreter_file_write,
+		3435: interpreter_clock_now,
+		3441: interpreter_clock_now,
+		3461: interpreter_clock_millis,
+		3467: interpreter_clock_millis,
+		3486: interpreter_clock_sleep,
+		3492: interpreter_clock_sleep,
+		3510: interpreter_random_int,
+		3516: interpreter_random_int,
+		3536: interpreter_random_below,
+		3542: interpreter_random_below,
+		3561: interpreter_random_seed,
+		3567: interpreter_random_seed,
+		3588: interpreter_format_string,
+		3594: interpreter_format_string,
+		3614: interpreter_format_print,
+		3620: interpreter_format_print,
+		3621: interpreter_task_finish,
+		3639: interpreter_task_spawn,
+		3651: interpreter_task_spawn,
+		3669: interpreter_task_yield,
+		3675: interpreter_task_yield,
+		3692: interpreter_task_send,
+		3698: interpreter_task_send,
+		3718: interpreter_task_receive,
+		3724: interpreter_task_receive,
+		3739: interpreter_task_id,
+		3745: interpreter_task_id,
+		3746: interpreter_main,
+		3751: interpreter_main,
+		3756: interpreter_main,
+		3780: interpreter_word_latest,
+		3790: interpreter_init,
+	},
+}
+
+// interpreter_start is compiled from 'start'.
+func interpreter_start(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 0: // start
+			// 0 jmp
+			return 3790, false, nil
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x40 is compiled from '@'.
+func interpreter_x40(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 13: // @
+			// 13 @
+			if err := m.Fetch(); err != nil {
+				return 13, false, err
+			}
+			// 14 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 14, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x21 is compiled from '!'.
+func interpreter_x21(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 23: // !
+			// 23 !
+			if err := m.Store(); err != nil {
+				return 23, false, err
+			}
+			// 24 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 24, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x2b is compiled from '+'.
+func interpreter_x2b(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 33: // +
+			// 33 +
+			if err := m.Add(); err != nil {
+				return 33, false, err
+			}
+			// 34 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 34, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter__ is compiled from '-'.
+func interpreter__(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 43: // -
+			// 43 -
+			if err := m.Sub(); err != nil {
+				return 43, false, err
+			}
+			// 44 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 44, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x2a is compiled from '*'.
+func interpreter_x2a(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 53: // *
+			// 53 *
+			if err := m.Mult(); err != nil {
+				return 53, false, err
+			}
+			// 54 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 54, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x2f is compiled from '/'.
+func interpreter_x2f(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 63: // /
+			// 63 /
+			if err := m.Div(); err != nil {
+				return 63, false, err
+			}
+			// 64 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 64, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x25 is compiled from '%'.
+func interpreter_x25(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 73: // %
+			// 73 %
+			if err := m.Mod(); err != nil {
+				return 73, false, err
+			}
+			// 74 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 74, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_dup is compiled from 'dup'.
+func interpreter_dup(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 85: // dup
+			// 85 dup
+			if err := m.Dup(); err != nil {
+				return 85, false, err
+			}
+			// 86 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 86, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_drop is compiled from 'drop'.
+func interpreter_drop(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 98: // drop
+			// 98 drop
+			if err := m.Drop(); err != nil {
+				return 98, false, err
+			}
+			// 99 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 99, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_swap is compiled from 'swap'.
+func interpreter_swap(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 111: // swap
+			// 111 swap
+			if err := m.Swap(); err != nil {
+				return 111, false, err
+			}
+			// 112 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 112, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_over is compiled from 'over'.
+func interpreter_over(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 124: // over
+			// 124 over
+			if err := m.Over(); err != nil {
+				return 124, false, err
+			}
+			// 125 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 125, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_key is compiled from 'key'.
+func interpreter_key(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 136: // key
+			// 136 key
+			if eof, err := m.Key(); err != nil {
+				return 136, false, err
+			} else if eof {
+				return 136, true, nil
+			}
+			// 137 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 137, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_emit is compiled from 'emit'.
+func interpreter_emit(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 149: // emit
+			// 149 emit
+			if err := m.Emit(); err != nil {
+				return 149, false, err
+			}
+			// 150 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 150, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x3d is compiled from '='.
+func interpreter_x3d(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 159: // =
+			// 159 =
+			if err := m.Eq(); err != nil {
+				return 159, false, err
+			}
+			// 160 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 160, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x7e is compiled from '~'.
+func interpreter_x7e(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 169: // ~
+			// 169 ~
+			if err := m.Not(); err != nil {
+				return 169, false, err
+			}
+			// 170 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 170, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x26 is compiled from '&'.
+func interpreter_x26(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 179: // &
+			// 179 &
+			if err := m.And(); err != nil {
+				return 179, false, err
+			}
+			// 180 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 180, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x7c is compiled from '|'.
+func interpreter_x7c(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 189: // |
+			// 189 |
+			if err := m.Or(); err != nil {
+				return 189, false, err
+			}
+			// 190 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 190, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x3c is compiled from '<'.
+func interpreter_x3c(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 199: // <
+			// 199 <
+			if err := m.Lt(); err != nil {
+				return 199, false, err
+			}
+			// 200 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 200, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x3e is compiled from '>'.
+func interpreter_x3e(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 209: // >
+			// 209 >
+			if err := m.Gt(); err != nil {
+				return 209, false, err
+			}
+			// 210 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 210, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_rpop is compiled from 'rpop'.
+func interpreter_rpop(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 222: // rpop
+			// 222 rpop
+			if err := m.RPop(); err != nil {
+				return 222, false, err
+			}
+			// 223 rpop
+			if err := m.RPop(); err != nil {
+				return 223, false, err
+			}
+			// 224 swap
+			if err := m.Swap(); err != nil {
+				return 224, false, err
+			}
+			// 225 rpush
+			if err := m.RPush(); err != nil {
+				return 225, false, err
+			}
+			// 226 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 226, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_rpush is compiled from 'rpush'.
+func interpreter_rpush(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 239: // rpush
+			// 239 rpop
+			if err := m.RPop(); err != nil {
+				return 239, false, err
+			}
+			// 240 swap
+			if err := m.Swap(); err != nil {
+				return 240, false, err
+			}
+			// 241 rpush
+			if err := m.RPush(); err != nil {
+				return 241, false, err
+			}
+			// 242 rpush
+			if err := m.RPush(); err != nil {
+				return 242, false, err
+			}
+			// 243 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 243, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_bx40 is compiled from 'b@'.
+func interpreter_bx40(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 253: // b@
+			// 253 b@
+			if err := m.BFetch(); err != nil {
+				return 253, false, err
+			}
+			// 254 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 254, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_bx21 is compiled from 'b!'.
+func interpreter_bx21(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 264: // b!
+			// 264 b!
+			if err := m.BStore(); err != nil {
+				return 264, false, err
+			}
+			// 265 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 265, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_depth is compiled from 'depth'.
+func interpreter_depth(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 278: // depth
+			// 278 depth
+			if err := m.Depth(); err != nil {
+				return 278, false, err
+			}
+			// 279 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 279, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_size is compiled from 'word-size'.
+func interpreter_word_size(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 296: // word-size
+			// 296 const
+			if err := m.Push(4); err != nil {
+				return 296, false, err
+			}
+			// 301 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 301, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_memory_size is compiled from 'memory.size'.
+func interpreter_memory_size(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 320: // memory.size
+			// 320 const
+			if err := m.Push(8192); err != nil {
+				return 320, false, err
+			}
+			// 325 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 325, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_stack_size is compiled from 'stack.size'.
+func interpreter_stack_size(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 343: // stack.size
+			// 343 const
+			if err := m.Push(20); err != nil {
+				return 343, false, err
+			}
+			// 348 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 348, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_rstack_size is compiled from 'rstack.size'.
+func interpreter_rstack_size(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 367: // rstack.size
+			// 367 const
+			if err := m.Push(20); err != nil {
+				return 367, false, err
+			}
+			// 372 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 372, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_wx2b is compiled from 'w+'.
+func interpreter_wx2b(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 382: // w+
+			// 382 const
+			if err := m.Push(4); err != nil {
+				return 382, false, err
+			}
+			// 387 +
+			if err := m.Add(); err != nil {
+				return 387, false, err
+			}
+			// 388 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 388, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_true is compiled from 'true'.
+func interpreter_true(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 400: // true
+			// 400 const
+			if err := m.Push(-1); err != nil {
+				return 400, false, err
+			}
+			// 405 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 405, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_false is compiled from 'false'.
+func interpreter_false(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 418: // false
+			// 418 const
+			if err := m.Push(0); err != nil {
+				return 418, false, err
+			}
+			// 423 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 423, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x27retx27 is compiled from ”ret”.
+func interpreter_x27retx27(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 436: // 'ret'
+			// 436 const
+			if err := m.Push(2); err != nil {
+				return 436, false, err
+			}
+			// 441 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 441, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x27jmpx27 is compiled from ”jmp”.
+func interpreter_x27jmpx27(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 454: // 'jmp'
+			// 454 const
+			if err := m.Push(3); err != nil {
+				return 454, false, err
+			}
+			// 459 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 459, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x27cjmpx27 is compiled from ”cjmp”.
+func interpreter_x27cjmpx27(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 473: // 'cjmp'
+			// 473 const
+			if err := m.Push(4); err != nil {
+				return 473, false, err
+			}
+			// 478 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 478, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x27callx27 is compiled from ”call”.
+func interpreter_x27callx27(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 492: // 'call'
+			// 492 const
+			if err := m.Push(5); err != nil {
+				return 492, false, err
+			}
+			// 497 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 497, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x27constx27 is compiled from ”const”.
+func interpreter_x27constx27(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 512: // 'const'
+			// 512 const
+			if err := m.Push(7); err != nil {
+				return 512, false, err
+			}
+			// 517 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 517, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x27x3dx27 is compiled from ”=”.
+func interpreter_x27x3dx27(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 528: // '='
+			// 528 const
+			if err := m.Push(24); err != nil {
+				return 528, false, err
+			}
+			// 533 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 533, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_unsafe_stack_call is compiled from 'unsafe.stack-call'.
+func interpreter_unsafe_stack_call(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 558: // unsafe.stack-call
+			// 558 rpush
+			if err := m.RPush(); err != nil {
+				return 558, false, err
+			}
+			// 559 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 559, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_int_digit_count is compiled from 'int.digit-count'.
+func interpreter_int_digit_count(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 560: // int.digit-count
+			// 560 dup
+			if err := m.Dup(); err != nil {
+				return 560, false, err
+			}
+			// 561 const
+			if err := m.Push(-1); err != nil {
+				return 561, false, err
+			}
+			// 566 >
+			if err := m.Gt(); err != nil {
+				return 566, false, err
+			}
+			// 567 const
+			if err := m.Push(1); err != nil {
+				return 567, false, err
+			}
+			// 572 +
+			if err := m.Add(); err != nil {
+				return 572, false, err
+			}
+			// 573 swap
+			if err := m.Swap(); err != nil {
+				return 573, false, err
+			}
+			pc = 574
+		case 574: // int.digit-count-loop
+			// 574 swap
+			if err := m.Swap(); err != nil {
+				return 574, false, err
+			}
+			// 575 const
+			if err := m.Push(1); err != nil {
+				return 575, false, err
+			}
+			// 580 +
+			if err := m.Add(); err != nil {
+				return 580, false, err
+			}
+			// 581 swap
+			if err := m.Swap(); err != nil {
+				return 581, false, err
+			}
+			// 582 const
+			if err := m.Push(10); err != nil {
+				return 582, false, err
+			}
+			// 587 /
+			if err := m.Div(); err != nil {
+				return 587, false, err
+			}
+			// 588 dup
+			if err := m.Dup(); err != nil {
+				return 588, false, err
+			}
+			// 589 const
+			if err := m.Push(0); err != nil {
+				return 589, false, err
+			}
+			// 594 =
+			if err := m.Eq(); err != nil {
+				return 594, false, err
+			}
+			// 595 ~
+			if err := m.Not(); err != nil {
+				return 595, false, err
+			}
+			// 596 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 596, false, err
+			} else if c != 0 {
+				pc = 574
+			} else {
+				pc = 601
+			}
+		case 601:
+			// 601 drop
+			if err := m.Drop(); err != nil {
+				return 601, false, err
+			}
+			// 602 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 602, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_math_max is compiled from 'math.max'.
+func interpreter_math_max(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 603: // math.max
+			// 603 over
+			if err := m.Over(); err != nil {
+				return 603, false, err
+			}
+			// 604 over
+			if err := m.Over(); err != nil {
+				return 604, false, err
+			}
+			// 605 <
+			if err := m.Lt(); err != nil {
+				return 605, false, err
+			}
+			// 606 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 606, false, err
+			} else if c != 0 {
+				pc = 613
+			} else {
+				pc = 611
+			}
+		case 611:
+			// 611 drop
+			if err := m.Drop(); err != nil {
+				return 611, false, err
+			}
+			// 612 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 612, false, err
+			}
+			pc = target
+		case 613: // math.max-else
+			// 613 swap
+			if err := m.Swap(); err != nil {
+				return 613, false, err
+			}
+			// 614 drop
+			if err := m.Drop(); err != nil {
+				return 614, false, err
+			}
+			// 615 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 615, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_math_min is compiled from 'math.min'.
+func interpreter_math_min(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 616: // math.min
+			// 616 over
+			if err := m.Over(); err != nil {
+				return 616, false, err
+			}
+			// 617 over
+			if err := m.Over(); err != nil {
+				return 617, false, err
+			}
+			// 618 >
+			if err := m.Gt(); err != nil {
+				return 618, false, err
+			}
+			// 619 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 619, false, err
+			} else if c != 0 {
+				return 613, false, nil
+			} else {
+				pc = 624
+			}
+		case 624:
+			// 624 drop
+			if err := m.Drop(); err != nil {
+				return 624, false, err
+			}
+			// 625 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 625, false, err
+			}
+			pc = target
+		case 626: // math.min-else
+			// 626 swap
+			if err := m.Swap(); err != nil {
+				return 626, false, err
+			}
+			// 627 drop
+			if err := m.Drop(); err != nil {
+				return 627, false, err
+			}
+			// 628 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 628, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_math_abs is compiled from 'math.abs'.
+func interpreter_math_abs(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 629: // math.abs
+			// 629 dup
+			if err := m.Dup(); err != nil {
+				return 629, false, err
+			}
+			// 630 const
+			if err := m.Push(0); err != nil {
+				return 630, false, err
+			}
+			// 635 <
+			if err := m.Lt(); err != nil {
+				return 635, false, err
+			}
+			// 636 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 636, false, err
+			} else if c != 0 {
+				pc = 642
+			} else {
+				pc = 641
+			}
+		case 641:
+			// 641 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 641, false, err
+			}
+			pc = target
+		case 642: // math.abs-invert
+			// 642 const
+			if err := m.Push(-1); err != nil {
+				return 642, false, err
+			}
+			// 647 *
+			if err := m.Mult(); err != nil {
+				return 647, false, err
+			}
+			// 648 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 648, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_math_int_max is compiled from 'math.int-max'.
+func interpreter_math_int_max(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 649: // math.int-max
+			// 649 const
+			if err := m.Push(2147483647); err != nil {
+				return 649, false, err
+			}
+			// 654 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 654, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_math_int_min is compiled from 'math.int-min'.
+func interpreter_math_int_min(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 655: // math.int-min
+			// 655 const
+			if err := m.Push(-2147483648); err != nil {
+				return 655, false, err
+			}
+			// 660 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 660, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_math_saturatedx3f is compiled from 'math.saturated?'.
+func interpreter_math_saturatedx3f(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 661: // math.saturated?
+			// 661 dup
+			if err := m.Dup(); err != nil {
+				return 661, false, err
+			}
+			// 662 call
+			if err := m.PushReturn(667); err != nil {
+				return 662, false, err
+			}
+			return 649, false, nil
+		case 667:
+			// 667 =
+			if err := m.Eq(); err != nil {
+				return 667, false, err
+			}
+			// 668 swap
+			if err := m.Swap(); err != nil {
+				return 668, false, err
+			}
+			// 669 call
+			if err := m.PushReturn(674); err != nil {
+				return 669, false, err
+			}
+			return 655, false, nil
+		case 674:
+			// 674 =
+			if err := m.Eq(); err != nil {
+				return 674, false, err
+			}
+			// 675 |
+			if err := m.Or(); err != nil {
+				return 675, false, err
+			}
+			// 676 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 676, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_math_absolute is compiled from 'math.absolute'.
+func interpreter_math_absolute(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 677: // math.absolute
+			// 677 dup
+			if err := m.Dup(); err != nil {
+				return 677, false, err
+			}
+			// 678 const
+			if err := m.Push(0); err != nil {
+				return 678, false, err
+			}
+			// 683 <
+			if err := m.Lt(); err != nil {
+				return 683, false, err
+			}
+			// 684 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 684, false, err
+			} else if c != 0 {
+				pc = 690
+			} else {
+				pc = 689
+			}
+		case 689:
+			// 689 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 689, false, err
+			}
+			pc = target
+		case 690: // math.absolute-negative
+			// 690 const
+			if err := m.Push(-1); err != nil {
+				return 690, false, err
+			}
+			// 695 *
+			if err := m.Mult(); err != nil {
+				return 695, false, err
+			}
+			// 696 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 696, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_init is compiled from 'array.init'.
+func interpreter_array_init(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 697: // array.init
+			// 697 call
+			if err := m.PushReturn(702); err != nil {
+				return 697, false, err
+			}
+			return 629, false, nil
+		case 702:
+			// 702 over
+			if err := m.Over(); err != nil {
+				return 702, false, err
+			}
+			// 703 const
+			if err := m.Push(1); err != nil {
+				return 703, false, err
+			}
+			// 708 +
+			if err := m.Add(); err != nil {
+				return 708, false, err
+			}
+			// 709 b!
+			if err := m.BStore(); err != nil {
+				return 709, false, err
+			}
+			// 710 dup
+			if err := m.Dup(); err != nil {
+				return 710, false, err
+			}
+			// 711 const
+			if err := m.Push(0); err != nil {
+				return 711, false, err
+			}
+			// 716 swap
+			if err := m.Swap(); err != nil {
+				return 716, false, err
+			}
+			// 717 b!
+			if err := m.BStore(); err != nil {
+				return 717, false, err
+			}
+			// 718 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 718, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_length is compiled from 'array.length'.
+func interpreter_array_length(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 719: // array.length
+			// 719 b@
+			if err := m.BFetch(); err != nil {
+				return 719, false, err
+			}
+			// 720 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 720, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_capacity is compiled from 'array.capacity'.
+func interpreter_array_capacity(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 721: // array.capacity
+			// 721 const
+			if err := m.Push(1); err != nil {
+				return 721, false, err
+			}
+			// 726 +
+			if err := m.Add(); err != nil {
+				return 726, false, err
+			}
+			// 727 b@
+			if err := m.BFetch(); err != nil {
+				return 727, false, err
+			}
+			// 728 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 728, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_append is compiled from 'array.append'.
+func interpreter_array_append(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 729: // array.append
+			// 729 dup
+			if err := m.Dup(); err != nil {
+				return 729, false, err
+			}
+			// 730 b@
+			if err := m.BFetch(); err != nil {
+				return 730, false, err
+			}
+			// 731 const
+			if err := m.Push(1); err != nil {
+				return 731, false, err
+			}
+			// 736 +
+			if err := m.Add(); err != nil {
+				return 736, false, err
+			}
+			// 737 over
+			if err := m.Over(); err != nil {
+				return 737, false, err
+			}
+			// 738 const
+			if err := m.Push(1); err != nil {
+				return 738, false, err
+			}
+			// 743 +
+			if err := m.Add(); err != nil {
+				return 743, false, err
+			}
+			// 744 b@
+			if err := m.BFetch(); err != nil {
+				return 744, false, err
+			}
+			// 745 %
+			if err := m.Mod(); err != nil {
+				return 745, false, err
+			}
+			// 746 over
+			if err := m.Over(); err != nil {
+				return 746, false, err
+			}
+			// 747 over
+			if err := m.Over(); err != nil {
+				return 747, false, err
+			}
+			// 748 swap
+			if err := m.Swap(); err != nil {
+				return 748, false, err
+			}
+			// 749 b!
+			if err := m.BStore(); err != nil {
+				return 749, false, err
+			}
+			// 750 +
+			if err := m.Add(); err != nil {
+				return 750, false, err
+			}
+			// 751 const
+			if err := m.Push(1); err != nil {
+				return 751, false, err
+			}
+			// 756 +
+			if err := m.Add(); err != nil {
+				return 756, false, err
+			}
+			// 757 b!
+			if err := m.BStore(); err != nil {
+				return 757, false, err
+			}
+			// 758 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 758, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_indexed is compiled from 'array.indexed'.
+func interpreter_array_indexed(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 759: // array.indexed
+			// 759 swap
+			if err := m.Swap(); err != nil {
+				return 759, false, err
+			}
+			// 760 over
+			if err := m.Over(); err != nil {
+				return 760, false, err
+			}
+			// 761 b@
+			if err := m.BFetch(); err != nil {
+				return 761, false, err
+			}
+			// 762 %
+			if err := m.Mod(); err != nil {
+				return 762, false, err
+			}
+			// 763 call
+			if err := m.PushReturn(768); err != nil {
+				return 763, false, err
+			}
+			return 629, false, nil
+		case 768:
+			// 768 +
+			if err := m.Add(); err != nil {
+				return 768, false, err
+			}
+			// 769 const
+			if err := m.Push(2); err != nil {
+				return 769, false, err
+			}
+			// 774 +
+			if err := m.Add(); err != nil {
+				return 774, false, err
+			}
+			// 775 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 775, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_get is compiled from 'array.get'.
+func interpreter_array_get(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 776: // array.get
+			// 776 call
+			if err := m.PushReturn(781); err != nil {
+				return 776, false, err
+			}
+			return 759, false, nil
+		case 781:
+			// 781 b@
+			if err := m.BFetch(); err != nil {
+				return 781, false, err
+			}
+			// 782 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 782, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_set is compiled from 'array.set'.
+func interpreter_array_set(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 783: // array.set
+			// 783 call
+			if err := m.PushReturn(788); err != nil {
+				return 783, false, err
+			}
+			return 759, false, nil
+		case 788:
+			// 788 b!
+			if err := m.BStore(); err != nil {
+				return 788, false, err
+			}
+			// 789 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 789, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_clear is compiled from 'array.clear'.
+func interpreter_array_clear(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 790: // array.clear
+			// 790 const
+			if err := m.Push(0); err != nil {
+				return 790, false, err
+			}
+			// 795 swap
+			if err := m.Swap(); err != nil {
+				return 795, false, err
+			}
+			// 796 b!
+			if err := m.BStore(); err != nil {
+				return 796, false, err
+			}
+			// 797 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 797, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_equalx3f is compiled from 'array.equal?'.
+func interpreter_array_equalx3f(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 798: // array.equal?
+			// 798 over
+			if err := m.Over(); err != nil {
+				return 798, false, err
+			}
+			// 799 call
+			if err := m.PushReturn(804); err != nil {
+				return 799, false, err
+			}
+			return 719, false, nil
+		case 804:
+			// 804 over
+			if err := m.Over(); err != nil {
+				return 804, false, err
+			}
+			// 805 call
+			if err := m.PushReturn(810); err != nil {
+				return 805, false, err
+			}
+			return 719, false, nil
+		case 810:
+			// 810 dup
+			if err := m.Dup(); err != nil {
+				return 810, false, err
+			}
+			// 811 const
+			if err := m.Push(1); err != nil {
+				return 811, false, err
+			}
+			// 816 -
+			if err := m.Sub(); err != nil {
+				return 816, false, err
+			}
+			// 817 rpush
+			if err := m.RPush(); err != nil {
+				return 817, false, err
+			}
+			// 818 =
+			if err := m.Eq(); err != nil {
+				return 818, false, err
+			}
+			// 819 ~
+			if err := m.Not(); err != nil {
+				return 819, false, err
+			}
+			// 820 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 820, false, err
+			} else if c != 0 {
+				pc = 878
+			} else {
+				pc = 825
+			}
+		case 825: // array.equal?-loop
+			// 825 over
+			if err := m.Over(); err != nil {
+				return 825, false, err
+			}
+			// 826 rpeek
+			if err := m.RPeek(); err != nil {
+				return 826, false, err
+			}
+			// 827 swap
+			if err := m.Swap(); err != nil {
+				return 827, false, err
+			}
+			// 828 call
+			if err := m.PushReturn(833); err != nil {
+				return 828, false, err
+			}
+			return 776, false, nil
+		case 833:
+			// 833 over
+			if err := m.Over(); err != nil {
+				return 833, false, err
+			}
+			// 834 rpeek
+			if err := m.RPeek(); err != nil {
+				return 834, false, err
+			}
+			// 835 swap
+			if err := m.Swap(); err != nil {
+				return 835, false, err
+			}
+			// 836 call
+			if err := m.PushReturn(841); err != nil {
+				return 836, false, err
+			}
+			return 776, false, nil
+		case 841:
+			// 841 =
+			if err := m.Eq(); err != nil {
+				return 841, false, err
+			}
+			// 842 ~
+			if err := m.Not(); err != nil {
+				return 842, false, err
+			}
+			// 843 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 843, false, err
+			} else if c != 0 {
+				pc = 878
+			} else {
+				pc = 848
+			}
+		case 848:
+			// 848 rpop
+			if err := m.RPop(); err != nil {
+				return 848, false, err
+			}
+			// 849 const
+			if err := m.Push(1); err != nil {
+				return 849, false, err
+			}
+			// 854 -
+			if err := m.Sub(); err != nil {
+				return 854, false, err
+			}
+			// 855 rpush
+			if err := m.RPush(); err != nil {
+				return 855, false, err
+			}
+			// 856 rpeek
+			if err := m.RPeek(); err != nil {
+				return 856, false, err
+			}
+			// 857 const
+			if err := m.Push(-1); err != nil {
+				return 857, false, err
+			}
+			// 862 >
+			if err := m.Gt(); err != nil {
+				return 862, false, err
+			}
+			// 863 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 863, false, err
+			} else if c != 0 {
+				pc = 825
+			} else {
+				pc = 868
+			}
+		case 868:
+			// 868 rpop
+			if err := m.RPop(); err != nil {
+				return 868, false, err
+			}
+			// 869 drop
+			if err := m.Drop(); err != nil {
+				return 869, false, err
+			}
+			// 870 drop
+			if err := m.Drop(); err != nil {
+				return 870, false, err
+			}
+			// 871 drop
+			if err := m.Drop(); err != nil {
+				return 871, false, err
+			}
+			// 872 call
+			if err := m.PushReturn(877); err != nil {
+				return 872, false, err
+			}
+			return 400, false, nil
+		case 877:
+			// 877 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 877, false, err
+			}
+			pc = target
+		case 878: // array.equal?-false
+			// 878 rpop
+			if err := m.RPop(); err != nil {
+				return 878, false, err
+			}
+			// 879 drop
+			if err := m.Drop(); err != nil {
+				return 879, false, err
+			}
+			// 880 drop
+			if err := m.Drop(); err != nil {
+				return 880, false, err
+			}
+			// 881 drop
+			if err := m.Drop(); err != nil {
+				return 881, false, err
+			}
+			// 882 call
+			if err := m.PushReturn(887); err != nil {
+				return 882, false, err
+			}
+			return 418, false, nil
+		case 887:
+			// 887 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 887, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_array_copy is compiled from 'array.copy'.
+func interpreter_array_copy(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 888: // array.copy
+			// 888 over
+			if err := m.Over(); err != nil {
+				return 888, false, err
+			}
+			// 889 call
+			if err := m.PushReturn(894); err != nil {
+				return 889, false, err
+			}
+			return 721, false, nil
+		case 894:
+			// 894 over
+			if err := m.Over(); err != nil {
+				return 894, false, err
+			}
+			// 895 call
+			if err := m.PushReturn(900); err != nil {
+				return 895, false, err
+			}
+			return 721, false, nil
+		case 900:
+			// 900 call
+			if err := m.PushReturn(905); err != nil {
+				return 900, false, err
+			}
+			return 616, false, nil
+		case 905:
+			// 905 rpush
+			if err := m.RPush(); err != nil {
+				return 905, false, err
+			}
+			// 906 over
+			if err := m.Over(); err != nil {
+				return 906, false, err
+			}
+			// 907 call
+			if err := m.PushReturn(912); err != nil {
+				return 907, false, err
+			}
+			return 719, false, nil
+		case 912:
+			// 912 over
+			if err := m.Over(); err != nil {
+				return 912, false, err
+			}
+			// 913 call
+			if err := m.PushReturn(918); err != nil {
+				return 913, false, err
+			}
+			return 719, false, nil
+		case 918:
+			// 918 call
+			if err := m.PushReturn(923); err != nil {
+				return 918, false, err
+			}
+			return 603, false, nil
+		case 923:
+			// 923 rpeek
+			if err := m.RPeek(); err != nil {
+				return 923, false, err
+			}
+			// 924 call
+			if err := m.PushReturn(929); err != nil {
+				return 924, false, err
+			}
+			return 616, false, nil
+		case 929:
+			// 929 over
+			if err := m.Over(); err != nil {
+				return 929, false, err
+			}
+			// 930 b!
+			if err := m.BStore(); err != nil {
+				return 930, false, err
+			}
+			pc = 931
+		case 931: // array.copy-loop
+			// 931 rpeek
+			if err := m.RPeek(); err != nil {
+				return 931, false, err
+			}
+			// 932 const
+			if err := m.Push(1); err != nil {
+				return 932, false, err
+			}
+			// 937 <
+			if err := m.Lt(); err != nil {
+				return 937, false, err
+			}
+			// 938 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 938, false, err
+			} else if c != 0 {
+				pc = 972
+			} else {
+				pc = 943
+			}
+		case 943:
+			// 943 over
+			if err := m.Over(); err != nil {
+				return 943, false, err
+			}
+			// 944 rpeek
+			if err := m.RPeek(); err != nil {
+				return 944, false, err
+			}
+			// 945 swap
+			if err := m.Swap(); err != nil {
+				return 945, false, err
+			}
+			// 946 call
+			if err := m.PushReturn(951); err != nil {
+				return 946, false, err
+			}
+			return 776, false, nil
+		case 951:
+			// 951 over
+			if err := m.Over(); err != nil {
+				return 951, false, err
+			}
+			// 952 rpeek
+			if err := m.RPeek(); err != nil {
+				return 952, false, err
+			}
+			// 953 swap
+			if err := m.Swap(); err != nil {
+				return 953, false, err
+			}
+			// 954 call
+			if err := m.PushReturn(959); err != nil {
+				return 954, false, err
+			}
+			return 783, false, nil
+		case 959:
+			// 959 rpop
+			if err := m.RPop(); err != nil {
+				return 959, false, err
+			}
+			// 960 const
+			if err := m.Push(1); err != nil {
+				return 960, false, err
+			}
+			// 965 -
+			if err := m.Sub(); err != nil {
+				return 965, false, err
+			}
+			// 966 rpush
+			if err := m.RPush(); err != nil {
+				return 966, false, err
+			}
+			// 967 jmp
+			pc = 931
+		case 972: // array.copy-end
+			// 972 rpop
+			if err := m.RPop(); err != nil {
+				return 972, false, err
+			}
+			// 973 drop
+			if err := m.Drop(); err != nil {
+				return 973, false, err
+			}
+			// 974 drop
+			if err := m.Drop(); err != nil {
+				return 974, false, err
+			}
+			// 975 drop
+			if err := m.Drop(); err != nil {
+				return 975, false, err
+			}
+			// 976 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 976, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_char_blankx3f is compiled from 'char.blank?'.
+func interpreter_char_blankx3f(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 977: // char.blank?
+			// 977 const
+			if err := m.Push(33); err != nil {
+				return 977, false, err
+			}
+			// 982 <
+			if err := m.Lt(); err != nil {
+				return 982, false, err
+			}
+			// 983 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 983, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_char_numberx3f is compiled from 'char.number?'.
+func interpreter_char_numberx3f(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 984: // char.number?
+			// 984 dup
+			if err := m.Dup(); err != nil {
+				return 984, false, err
+			}
+			// 985 const
+			if err := m.Push(47); err != nil {
+				return 985, false, err
+			}
+			// 990 >
+			if err := m.Gt(); err != nil {
+				return 990, false, err
+			}
+			// 991 swap
+			if err := m.Swap(); err != nil {
+				return 991, false, err
+			}
+			// 992 const
+			if err := m.Push(58); err != nil {
+				return 992, false, err
+			}
+			// 997 <
+			if err := m.Lt(); err != nil {
+				return 997, false, err
+			}
+			// 998 &
+			if err := m.And(); err != nil {
+				return 998, false, err
+			}
+			// 999 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 999, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_string_sign is compiled from 'string.sign'.
+func interpreter_string_sign(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1000: // string.sign
+			// 1000 const
+			if err := m.Push(0); err != nil {
+				return 1000, false, err
+			}
+			// 1005 swap
+			if err := m.Swap(); err != nil {
+				return 1005, false, err
+			}
+			// 1006 call
+			if err := m.PushReturn(1011); err != nil {
+				return 1006, false, err
+			}
+			return 776, false, nil
+		case 1011:
+			// 1011 const
+			if err := m.Push(45); err != nil {
+				return 1011, false, err
+			}
+			// 1016 =
+			if err := m.Eq(); err != nil {
+				return 1016, false, err
+			}
+			// 1017 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1017, false, err
+			} else if c != 0 {
+				pc = 1028
+			} else {
+				pc = 1022
+			}
+		case 1022:
+			// 1022 const
+			if err := m.Push(1); err != nil {
+				return 1022, false, err
+			}
+			// 1027 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1027, false, err
+			}
+			pc = target
+		case 1028: // string.sign-negative
+			// 1028 const
+			if err := m.Push(-1); err != nil {
+				return 1028, false, err
+			}
+			// 1033 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1033, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_string_parse_number is compiled from 'string.parse-number'.
+func interpreter_string_parse_number(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1034: // string.parse-number
+			// 1034 dup
+			if err := m.Dup(); err != nil {
+				return 1034, false, err
+			}
+			// 1035 call
+			if err := m.PushReturn(1040); err != nil {
+				return 1035, false, err
+			}
+			return 1000, false, nil
+		case 1040:
+			// 1040 swap
+			if err := m.Swap(); err != nil {
+				return 1040, false, err
+			}
+			// 1041 const
+			if err := m.Push(0); err != nil {
+				return 1041, false, err
+			}
+			// 1046 rpush
+			if err := m.RPush(); err != nil {
+				return 1046, false, err
+			}
+			// 1047 const
+			if err := m.Push(0); err != nil {
+				return 1047, false, err
+			}
+			pc = 1052
+		case 1052: // string.parse-number-loop
+			// 1052 over
+			if err := m.Over(); err != nil {
+				return 1052, false, err
+			}
+			// 1053 call
+			if err := m.PushReturn(1058); err != nil {
+				return 1053, false, err
+			}
+			return 719, false, nil
+		case 1058:
+			// 1058 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1058, false, err
+			}
+			// 1059 >
+			if err := m.Gt(); err != nil {
+				return 1059, false, err
+			}
+			// 1060 ~
+			if err := m.Not(); err != nil {
+				return 1060, false, err
+			}
+			// 1061 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1061, false, err
+			} else if c != 0 {
+				pc = 1145
+			} else {
+				pc = 1066
+			}
+		case 1066:
+			// 1066 const
+			if err := m.Push(10); err != nil {
+				return 1066, false, err
+			}
+			// 1071 *
+			if err := m.Mult(); err != nil {
+				return 1071, false, err
+			}
+			// 1072 over
+			if err := m.Over(); err != nil {
+				return 1072, false, err
+			}
+			// 1073 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1073, false, err
+			}
+			// 1074 swap
+			if err := m.Swap(); err != nil {
+				return 1074, false, err
+			}
+			// 1075 call
+			if err := m.PushReturn(1080); err != nil {
+				return 1075, false, err
+			}
+			return 776, false, nil
+		case 1080:
+			// 1080 dup
+			if err := m.Dup(); err != nil {
+				return 1080, false, err
+			}
+			// 1081 const
+			if err := m.Push(45); err != nil {
+				return 1081, false, err
+			}
+			// 1086 =
+			if err := m.Eq(); err != nil {
+				return 1086, false, err
+			}
+			// 1087 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1087, false, err
+			} else if c != 0 {
+				pc = 1124
+			} else {
+				pc = 1092
+			}
+		case 1092:
+			// 1092 dup
+			if err := m.Dup(); err != nil {
+				return 1092, false, err
+			}
+			// 1093 call
+			if err := m.PushReturn(1098); err != nil {
+				return 1093, false, err
+			}
+			return 984, false, nil
+		case 1098:
+			// 1098 ~
+			if err := m.Not(); err != nil {
+				return 1098, false, err
+			}
+			// 1099 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1099, false, err
+			} else if c != 0 {
+				pc = 1138
+			} else {
+				pc = 1104
+			}
+		case 1104:
+			// 1104 const
+			if err := m.Push(48); err != nil {
+				return 1104, false, err
+			}
+			// 1109 -
+			if err := m.Sub(); err != nil {
+				return 1109, false, err
+			}
+			// 1110 +
+			if err := m.Add(); err != nil {
+				return 1110, false, err
+			}
+			// 1111 rpop
+			if err := m.RPop(); err != nil {
+				return 1111, false, err
+			}
+			// 1112 const
+			if err := m.Push(1); err != nil {
+				return 1112, false, err
+			}
+			// 1117 +
+			if err := m.Add(); err != nil {
+				return 1117, false, err
+			}
+			// 1118 rpush
+			if err := m.RPush(); err != nil {
+				return 1118, false, err
+			}
+			// 1119 jmp
+			pc = 1052
+		case 1124: // string.parse-number-skip
+			// 1124 drop
+			if err := m.Drop(); err != nil {
+				return 1124, false, err
+			}
+			// 1125 rpop
+			if err := m.RPop(); err != nil {
+				return 1125, false, err
+			}
+			// 1126 const
+			if err := m.Push(1); err != nil {
+				return 1126, false, err
+			}
+			// 1131 +
+			if err := m.Add(); err != nil {
+				return 1131, false, err
+			}
+			// 1132 rpush
+			if err := m.RPush(); err != nil {
+				return 1132, false, err
+			}
+			// 1133 jmp
+			pc = 1052
+		case 1138: // string.parse-number-error
+			// 1138 drop
+			if err := m.Drop(); err != nil {
+				return 1138, false, err
+			}
+			// 1139 drop
+			if err := m.Drop(); err != nil {
+				return 1139, false, err
+			}
+			// 1140 call
+			if err := m.PushReturn(1145); err != nil {
+				return 1140, false, err
+			}
+			return 655, false, nil
+		case 1145: // string.parse-number-end
+			// 1145 rpop
+			if err := m.RPop(); err != nil {
+				return 1145, false, err
+			}
+			// 1146 drop
+			if err := m.Drop(); err != nil {
+				return 1146, false, err
+			}
+			// 1147 swap
+			if err := m.Swap(); err != nil {
+				return 1147, false, err
+			}
+			// 1148 drop
+			if err := m.Drop(); err != nil {
+				return 1148, false, err
+			}
+			// 1149 *
+			if err := m.Mult(); err != nil {
+				return 1149, false, err
+			}
+			// 1150 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1150, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_string_print is compiled from 'string.print'.
+func interpreter_string_print(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1151: // string.print
+			// 1151 const
+			if err := m.Push(0); err != nil {
+				return 1151, false, err
+			}
+			// 1156 rpush
+			if err := m.RPush(); err != nil {
+				return 1156, false, err
+			}
+			pc = 1157
+		case 1157: // string.print-loop
+			// 1157 dup
+			if err := m.Dup(); err != nil {
+				return 1157, false, err
+			}
+			// 1158 call
+			if err := m.PushReturn(1163); err != nil {
+				return 1158, false, err
+			}
+			return 719, false, nil
+		case 1163:
+			// 1163 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1163, false, err
+			}
+			// 1164 >
+			if err := m.Gt(); err != nil {
+				return 1164, false, err
+			}
+			// 1165 ~
+			if err := m.Not(); err != nil {
+				return 1165, false, err
+			}
+			// 1166 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1166, false, err
+			} else if c != 0 {
+				pc = 1192
+			} else {
+				pc = 1171
+			}
+		case 1171:
+			// 1171 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1171, false, err
+			}
+			// 1172 over
+			if err := m.Over(); err != nil {
+				return 1172, false, err
+			}
+			// 1173 call
+			if err := m.PushReturn(1178); err != nil {
+				return 1173, false, err
+			}
+			return 776, false, nil
+		case 1178:
+			// 1178 emit
+			if err := m.Emit(); err != nil {
+				return 1178, false, err
+			}
+			// 1179 rpop
+			if err := m.RPop(); err != nil {
+				return 1179, false, err
+			}
+			// 1180 const
+			if err := m.Push(1); err != nil {
+				return 1180, false, err
+			}
+			// 1185 +
+			if err := m.Add(); err != nil {
+				return 1185, false, err
+			}
+			// 1186 rpush
+			if err := m.RPush(); err != nil {
+				return 1186, false, err
+			}
+			// 1187 jmp
+			pc = 1157
+		case 1192: // string.print-end
+			// 1192 rpop
+			if err := m.RPop(); err != nil {
+				return 1192, false, err
+			}
+			// 1193 drop
+			if err := m.Drop(); err != nil {
+				return 1193, false, err
+			}
+			// 1194 drop
+			if err := m.Drop(); err != nil {
+				return 1194, false, err
+			}
+			// 1195 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1195, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_last_digit_to_char is compiled from 'last-digit-to-char'.
+func interpreter_last_digit_to_char(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1196: // last-digit-to-char
+			// 1196 call
+			if err := m.PushReturn(1201); err != nil {
+				return 1196, false, err
+			}
+			return 677, false, nil
+		case 1201:
+			// 1201 const
+			if err := m.Push(10); err != nil {
+				return 1201, false, err
+			}
+			// 1206 %
+			if err := m.Mod(); err != nil {
+				return 1206, false, err
+			}
+			// 1207 const
+			if err := m.Push(48); err != nil {
+				return 1207, false, err
+			}
+			// 1212 +
+			if err := m.Add(); err != nil {
+				return 1212, false, err
+			}
+			// 1213 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1213, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_store_digit is compiled from 'store-digit'.
+func interpreter_store_digit(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1214: // store-digit
+			// 1214 rpush
+			if err := m.RPush(); err != nil {
+				return 1214, false, err
+			}
+			// 1215 rpush
+			if err := m.RPush(); err != nil {
+				return 1215, false, err
+			}
+			// 1216 call
+			if err := m.PushReturn(1221); err != nil {
+				return 1216, false, err
+			}
+			return 1196, false, nil
+		case 1221:
+			// 1221 rpop
+			if err := m.RPop(); err != nil {
+				return 1221, false, err
+			}
+			// 1222 rpop
+			if err := m.RPop(); err != nil {
+				return 1222, false, err
+			}
+			// 1223 call
+			if err := m.PushReturn(1228); err != nil {
+				return 1223, false, err
+			}
+			return 783, false, nil
+		case 1228:
+			// 1228 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1228, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_string_from_number is compiled from 'string.from-number'.
+func interpreter_string_from_number(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1229: // string.from-number
+			// 1229 rpush
+			if err := m.RPush(); err != nil {
+				return 1229, false, err
+			}
+			// 1230 dup
+			if err := m.Dup(); err != nil {
+				return 1230, false, err
+			}
+			// 1231 call
+			if err := m.PushReturn(1236); err != nil {
+				return 1231, false, err
+			}
+			return 560, false, nil
+		case 1236:
+			// 1236 dup
+			if err := m.Dup(); err != nil {
+				return 1236, false, err
+			}
+			// 1237 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1237, false, err
+			}
+			// 1238 b!
+			if err := m.BStore(); err != nil {
+				return 1238, false, err
+			}
+			pc = 1239
+		case 1239: // string.from-number-loop
+			// 1239 const
+			if err := m.Push(1); err != nil {
+				return 1239, false, err
+			}
+			// 1244 -
+			if err := m.Sub(); err != nil {
+				return 1244, false, err
+			}
+			// 1245 over
+			if err := m.Over(); err != nil {
+				return 1245, false, err
+			}
+			// 1246 over
+			if err := m.Over(); err != nil {
+				return 1246, false, err
+			}
+			// 1247 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1247, false, err
+			}
+			// 1248 call
+			if err := m.PushReturn(1253); err != nil {
+				return 1248, false, err
+			}
+			return 1214, false, nil
+		case 1253:
+			// 1253 dup
+			if err := m.Dup(); err != nil {
+				return 1253, false, err
+			}
+			// 1254 const
+			if err := m.Push(0); err != nil {
+				return 1254, false, err
+			}
+			// 1259 =
+			if err := m.Eq(); err != nil {
+				return 1259, false, err
+			}
+			// 1260 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1260, false, err
+			} else if c != 0 {
+				pc = 1278
+			} else {
+				pc = 1265
+			}
+		case 1265:
+			// 1265 swap
+			if err := m.Swap(); err != nil {
+				return 1265, false, err
+			}
+			// 1266 const
+			if err := m.Push(10); err != nil {
+				return 1266, false, err
+			}
+			// 1271 /
+			if err := m.Div(); err != nil {
+				return 1271, false, err
+			}
+			// 1272 swap
+			if err := m.Swap(); err != nil {
+				return 1272, false, err
+			}
+			// 1273 jmp
+			pc = 1239
+		case 1278: // string.from-number-end
+			// 1278 drop
+			if err := m.Drop(); err != nil {
+				return 1278, false, err
+			}
+			// 1279 drop
+			if err := m.Drop(); err != nil {
+				return 1279, false, err
+			}
+			// 1280 const
+			if err := m.Push(0); err != nil {
+				return 1280, false, err
+			}
+			// 1285 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1285, false, err
+			}
+			// 1286 call
+			if err := m.PushReturn(1291); err != nil {
+				return 1286, false, err
+			}
+			return 776, false, nil
+		case 1291:
+			// 1291 const
+			if err := m.Push(48); err != nil {
+				return 1291, false, err
+			}
+			// 1296 =
+			if err := m.Eq(); err != nil {
+				return 1296, false, err
+			}
+			// 1297 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1297, false, err
+			}
+			// 1298 call
+			if err := m.PushReturn(1303); err != nil {
+				return 1298, false, err
+			}
+			return 719, false, nil
+		case 1303:
+			// 1303 const
+			if err := m.Push(1); err != nil {
+				return 1303, false, err
+			}
+			// 1308 >
+			if err := m.Gt(); err != nil {
+				return 1308, false, err
+			}
+			// 1309 &
+			if err := m.And(); err != nil {
+				return 1309, false, err
+			}
+			// 1310 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1310, false, err
+			} else if c != 0 {
+				pc = 1318
+			} else {
+				pc = 1315
+			}
+		case 1315:
+			// 1315 rpop
+			if err := m.RPop(); err != nil {
+				return 1315, false, err
+			}
+			// 1316 drop
+			if err := m.Drop(); err != nil {
+				return 1316, false, err
+			}
+			// 1317 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1317, false, err
+			}
+			pc = target
+		case 1318: // string.from-number-minus
+			// 1318 const
+			if err := m.Push(45); err != nil {
+				return 1318, false, err
+			}
+			// 1323 const
+			if err := m.Push(0); err != nil {
+				return 1323, false, err
+			}
+			// 1328 rpop
+			if err := m.RPop(); err != nil {
+				return 1328, false, err
+			}
+			// 1329 call
+			if err := m.PushReturn(1334); err != nil {
+				return 1329, false, err
+			}
+			return 783, false, nil
+		case 1334:
+			// 1334 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1334, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_buffer is compiled from 'word.buffer'.
+func interpreter_word_buffer(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1353: // word.buffer
+			// 1353 const
+			if err := m.Push(1359); err != nil {
+				return 1353, false, err
+			}
+			// 1358 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1358, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_non_blank_key is compiled from 'non-blank-key'.
+func interpreter_non_blank_key(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1393: // non-blank-key
+			// 1393 key
+			if eof, err := m.Key(); err != nil {
+				return 1393, false, err
+			} else if eof {
+				return 1393, true, nil
+			}
+			// 1394 dup
+			if err := m.Dup(); err != nil {
+				return 1394, false, err
+			}
+			// 1395 call
+			if err := m.PushReturn(1400); err != nil {
+				return 1395, false, err
+			}
+			return 977, false, nil
+		case 1400:
+			// 1400 ~
+			if err := m.Not(); err != nil {
+				return 1400, false, err
+			}
+			// 1401 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1401, false, err
+			} else if c != 0 {
+				pc = 1412
+			} else {
+				pc = 1406
+			}
+		case 1406:
+			// 1406 drop
+			if err := m.Drop(); err != nil {
+				return 1406, false, err
+			}
+			// 1407 jmp
+			pc = 1393
+		case 1412: // non-blank-key-end
+			// 1412 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1412, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_read is compiled from 'word.read'.
+func interpreter_word_read(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1429: // word.read
+			// 1429 const
+			if err := m.Push(1359); err != nil {
+				return 1429, false, err
+			}
+			// 1434 const
+			if err := m.Push(32); err != nil {
+				return 1434, false, err
+			}
+			// 1439 call
+			if err := m.PushReturn(1444); err != nil {
+				return 1439, false, err
+			}
+			return 697, false, nil
+		case 1444:
+			// 1444 rpush
+			if err := m.RPush(); err != nil {
+				return 1444, false, err
+			}
+			// 1445 call
+			if err := m.PushReturn(1450); err != nil {
+				return 1445, false, err
+			}
+			return 1393, false, nil
+		case 1450: // word.read-loop
+			// 1450 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1450, false, err
+			}
+			// 1451 call
+			if err := m.PushReturn(1456); err != nil {
+				return 1451, false, err
+			}
+			return 729, false, nil
+		case 1456:
+			// 1456 key
+			if eof, err := m.Key(); err != nil {
+				return 1456, false, err
+			} else if eof {
+				return 1456, true, nil
+			}
+			// 1457 dup
+			if err := m.Dup(); err != nil {
+				return 1457, false, err
+			}
+			// 1458 call
+			if err := m.PushReturn(1463); err != nil {
+				return 1458, false, err
+			}
+			return 977, false, nil
+		case 1463:
+			// 1463 ~
+			if err := m.Not(); err != nil {
+				return 1463, false, err
+			}
+			// 1464 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1464, false, err
+			} else if c != 0 {
+				pc = 1450
+			} else {
+				pc = 1469
+			}
+		case 1469:
+			// 1469 drop
+			if err := m.Drop(); err != nil {
+				return 1469, false, err
+			}
+			// 1470 rpop
+			if err := m.RPop(); err != nil {
+				return 1470, false, err
+			}
+			// 1471 drop
+			if err := m.Drop(); err != nil {
+				return 1471, false, err
+			}
+			// 1472 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1472, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_print is compiled from 'word.print'.
+func interpreter_word_print(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1490: // word.print
+			// 1490 const
+			if err := m.Push(1359); err != nil {
+				return 1490, false, err
+			}
+			// 1495 call
+			if err := m.PushReturn(1500); err != nil {
+				return 1495, false, err
+			}
+			return 1151, false, nil
+		case 1500:
+			// 1500 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1500, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_number_print is compiled from 'number.print'.
+func interpreter_number_print(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1501: // number.print
+			// 1501 call
+			if err := m.PushReturn(1506); err != nil {
+				return 1501, false, err
+			}
+			return 1353, false, nil
+		case 1506:
+			// 1506 call
+			if err := m.PushReturn(1511); err != nil {
+				return 1506, false, err
+			}
+			return 1229, false, nil
+		case 1511:
+			// 1511 call
+			if err := m.PushReturn(1516); err != nil {
+				return 1511, false, err
+			}
+			return 1490, false, nil
+		case 1516:
+			// 1516 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1516, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter___1525 is compiled from '.'.
+func interpreter___1525(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1525: // .
+			// 1525 dup
+			if err := m.Dup(); err != nil {
+				return 1525, false, err
+			}
+			// 1526 call
+			if err := m.PushReturn(1531); err != nil {
+				return 1526, false, err
+			}
+			return 1501, false, nil
+		case 1531:
+			// 1531 const
+			if err := m.Push(10); err != nil {
+				return 1531, false, err
+			}
+			// 1536 emit
+			if err := m.Emit(); err != nil {
+				return 1536, false, err
+			}
+			// 1537 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1537, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_stack_print is compiled from 'stack.print'.
+func interpreter_stack_print(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1556: // stack.print
+			// 1556 const
+			if err := m.Push(60); err != nil {
+				return 1556, false, err
+			}
+			// 1561 emit
+			if err := m.Emit(); err != nil {
+				return 1561, false, err
+			}
+			// 1562 depth
+			if err := m.Depth(); err != nil {
+				return 1562, false, err
+			}
+			// 1563 call
+			if err := m.PushReturn(1568); err != nil {
+				return 1563, false, err
+			}
+			return 1501, false, nil
+		case 1568:
+			// 1568 const
+			if err := m.Push(62); err != nil {
+				return 1568, false, err
+			}
+			// 1573 emit
+			if err := m.Emit(); err != nil {
+				return 1573, false, err
+			}
+			// 1574 const
+			if err := m.Push(0); err != nil {
+				return 1574, false, err
+			}
+			pc = 1579
+		case 1579: // stack.print-save
+			// 1579 depth
+			if err := m.Depth(); err != nil {
+				return 1579, false, err
+			}
+			// 1580 const
+			if err := m.Push(1); err != nil {
+				return 1580, false, err
+			}
+			// 1585 =
+			if err := m.Eq(); err != nil {
+				return 1585, false, err
+			}
+			// 1586 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1586, false, err
+			} else if c != 0 {
+				pc = 1610
+			} else {
+				pc = 1591
+			}
+		case 1591:
+			// 1591 swap
+			if err := m.Swap(); err != nil {
+				return 1591, false, err
+			}
+			// 1592 over
+			if err := m.Over(); err != nil {
+				return 1592, false, err
+			}
+			// 1593 call
+			if err := m.PushReturn(1598); err != nil {
+				return 1593, false, err
+			}
+			pc = 1661
+		case 1598:
+			// 1598 !
+			if err := m.Store(); err != nil {
+				return 1598, false, err
+			}
+			// 1599 const
+			if err := m.Push(1); err != nil {
+				return 1599, false, err
+			}
+			// 1604 +
+			if err := m.Add(); err != nil {
+				return 1604, false, err
+			}
+			// 1605 jmp
+			pc = 1579
+		case 1610: // stack.print-restore
+			// 1610 const
+			if err := m.Push(1); err != nil {
+				return 1610, false, err
+			}
+			// 1615 -
+			if err := m.Sub(); err != nil {
+				return 1615, false, err
+			}
+			// 1616 dup
+			if err := m.Dup(); err != nil {
+				return 1616, false, err
+			}
+			// 1617 const
+			if err := m.Push(0); err != nil {
+				return 1617, false, err
+			}
+			// 1622 <
+			if err := m.Lt(); err != nil {
+				return 1622, false, err
+			}
+			// 1623 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1623, false, err
+			} else if c != 0 {
+				pc = 1653
+			} else {
+				pc = 1628
+			}
+		case 1628:
+			// 1628 dup
+			if err := m.Dup(); err != nil {
+				return 1628, false, err
+			}
+			// 1629 call
+			if err := m.PushReturn(1634); err != nil {
+				return 1629, false, err
+			}
+			pc = 1661
+		case 1634:
+			// 1634 @
+			if err := m.Fetch(); err != nil {
+				return 1634, false, err
+			}
+			// 1635 const
+			if err := m.Push(32); err != nil {
+				return 1635, false, err
+			}
+			// 1640 emit
+			if err := m.Emit(); err != nil {
+				return 1640, false, err
+			}
+			// 1641 dup
+			if err := m.Dup(); err != nil {
+				return 1641, false, err
+			}
+			// 1642 call
+			if err := m.PushReturn(1647); err != nil {
+				return 1642, false, err
+			}
+			return 1501, false, nil
+		case 1647:
+			// 1647 swap
+			if err := m.Swap(); err != nil {
+				return 1647, false, err
+			}
+			// 1648 jmp
+			pc = 1610
+		case 1653: // stack.print-end
+			// 1653 drop
+			if err := m.Drop(); err != nil {
+				return 1653, false, err
+			}
+			// 1654 const
+			if err := m.Push(10); err != nil {
+				return 1654, false, err
+			}
+			// 1659 emit
+			if err := m.Emit(); err != nil {
+				return 1659, false, err
+			}
+			// 1660 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1660, false, err
+			}
+			pc = target
+		case 1661: // stack.print-cell
+			// 1661 call
+			if err := m.PushReturn(1666); err != nil {
+				return 1661, false, err
+			}
+			return 296, false, nil
+		case 1666:
+			// 1666 *
+			if err := m.Mult(); err != nil {
+				return 1666, false, err
+			}
+			// 1667 call
+			if err := m.PushReturn(1672); err != nil {
+				return 1667, false, err
+			}
+			return 2082, false, nil
+		case 1672:
+			// 1672 @
+			if err := m.Fetch(); err != nil {
+				return 1672, false, err
+			}
+			// 1673 +
+			if err := m.Add(); err != nil {
+				return 1673, false, err
+			}
+			// 1674 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1674, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_flags is compiled from 'word.flags'.
+func interpreter_word_flags(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1692: // word.flags
+			// 1692 call
+			if err := m.PushReturn(1697); err != nil {
+				return 1692, false, err
+			}
+			return 382, false, nil
+		case 1697:
+			// 1697 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1697, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_name is compiled from 'word.name'.
+func interpreter_word_name(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1714: // word.name
+			// 1714 call
+			if err := m.PushReturn(1719); err != nil {
+				return 1714, false, err
+			}
+			return 382, false, nil
+		case 1719:
+			// 1719 const
+			if err := m.Push(1); err != nil {
+				return 1719, false, err
+			}
+			// 1724 +
+			if err := m.Add(); err != nil {
+				return 1724, false, err
+			}
+			// 1725 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1725, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_code is compiled from 'word.code'.
+func interpreter_word_code(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1742: // word.code
+			// 1742 call
+			if err := m.PushReturn(1747); err != nil {
+				return 1742, false, err
+			}
+			return 1714, false, nil
+		case 1747:
+			// 1747 dup
+			if err := m.Dup(); err != nil {
+				return 1747, false, err
+			}
+			// 1748 b@
+			if err := m.BFetch(); err != nil {
+				return 1748, false, err
+			}
+			// 1749 const
+			if err := m.Push(2); err != nil {
+				return 1749, false, err
+			}
+			// 1754 +
+			if err := m.Add(); err != nil {
+				return 1754, false, err
+			}
+			// 1755 +
+			if err := m.Add(); err != nil {
+				return 1755, false, err
+			}
+			// 1756 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1756, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_immediate is compiled from 'word.immediate'.
+func interpreter_word_immediate(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1778: // word.immediate
+			// 1778 call
+			if err := m.PushReturn(1783); err != nil {
+				return 1778, false, err
+			}
+			return 3780, false, nil
+		case 1783:
+			// 1783 @
+			if err := m.Fetch(); err != nil {
+				return 1783, false, err
+			}
+			// 1784 call
+			if err := m.PushReturn(1789); err != nil {
+				return 1784, false, err
+			}
+			return 1692, false, nil
+		case 1789:
+			// 1789 dup
+			if err := m.Dup(); err != nil {
+				return 1789, false, err
+			}
+			// 1790 b@
+			if err := m.BFetch(); err != nil {
+				return 1790, false, err
+			}
+			// 1791 const
+			if err := m.Push(2); err != nil {
+				return 1791, false, err
+			}
+			// 1796 |
+			if err := m.Or(); err != nil {
+				return 1796, false, err
+			}
+			// 1797 swap
+			if err := m.Swap(); err != nil {
+				return 1797, false, err
+			}
+			// 1798 b!
+			if err := m.BStore(); err != nil {
+				return 1798, false, err
+			}
+			// 1799 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1799, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_immediatex3f is compiled from 'word.immediate?'.
+func interpreter_word_immediatex3f(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1822: // word.immediate?
+			// 1822 call
+			if err := m.PushReturn(1827); err != nil {
+				return 1822, false, err
+			}
+			return 1692, false, nil
+		case 1827:
+			// 1827 b@
+			if err := m.BFetch(); err != nil {
+				return 1827, false, err
+			}
+			// 1828 const
+			if err := m.Push(2); err != nil {
+				return 1828, false, err
+			}
+			// 1833 &
+			if err := m.And(); err != nil {
+				return 1833, false, err
+			}
+			// 1834 const
+			if err := m.Push(2); err != nil {
+				return 1834, false, err
+			}
+			// 1839 =
+			if err := m.Eq(); err != nil {
+				return 1839, false, err
+			}
+			// 1840 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1840, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_hide is compiled from 'word.hide'.
+func interpreter_word_hide(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1857: // word.hide
+			// 1857 call
+			if err := m.PushReturn(1862); err != nil {
+				return 1857, false, err
+			}
+			return 3780, false, nil
+		case 1862:
+			// 1862 @
+			if err := m.Fetch(); err != nil {
+				return 1862, false, err
+			}
+			// 1863 call
+			if err := m.PushReturn(1868); err != nil {
+				return 1863, false, err
+			}
+			return 1692, false, nil
+		case 1868:
+			// 1868 dup
+			if err := m.Dup(); err != nil {
+				return 1868, false, err
+			}
+			// 1869 b@
+			if err := m.BFetch(); err != nil {
+				return 1869, false, err
+			}
+			// 1870 const
+			if err := m.Push(1); err != nil {
+				return 1870, false, err
+			}
+			// 1875 |
+			if err := m.Or(); err != nil {
+				return 1875, false, err
+			}
+			// 1876 swap
+			if err := m.Swap(); err != nil {
+				return 1876, false, err
+			}
+			// 1877 b!
+			if err := m.BStore(); err != nil {
+				return 1877, false, err
+			}
+			// 1878 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1878, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_unhide is compiled from 'word.unhide'.
+func interpreter_word_unhide(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1897: // word.unhide
+			// 1897 call
+			if err := m.PushReturn(1902); err != nil {
+				return 1897, false, err
+			}
+			return 3780, false, nil
+		case 1902:
+			// 1902 @
+			if err := m.Fetch(); err != nil {
+				return 1902, false, err
+			}
+			// 1903 call
+			if err := m.PushReturn(1908); err != nil {
+				return 1903, false, err
+			}
+			return 1692, false, nil
+		case 1908:
+			// 1908 dup
+			if err := m.Dup(); err != nil {
+				return 1908, false, err
+			}
+			// 1909 b@
+			if err := m.BFetch(); err != nil {
+				return 1909, false, err
+			}
+			// 1910 const
+			if err := m.Push(254); err != nil {
+				return 1910, false, err
+			}
+			// 1915 &
+			if err := m.And(); err != nil {
+				return 1915, false, err
+			}
+			// 1916 swap
+			if err := m.Swap(); err != nil {
+				return 1916, false, err
+			}
+			// 1917 b!
+			if err := m.BStore(); err != nil {
+				return 1917, false, err
+			}
+			// 1918 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1918, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_hiddenx3f is compiled from 'word.hidden?'.
+func interpreter_word_hiddenx3f(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1938: // word.hidden?
+			// 1938 call
+			if err := m.PushReturn(1943); err != nil {
+				return 1938, false, err
+			}
+			return 1692, false, nil
+		case 1943:
+			// 1943 b@
+			if err := m.BFetch(); err != nil {
+				return 1943, false, err
+			}
+			// 1944 const
+			if err := m.Push(1); err != nil {
+				return 1944, false, err
+			}
+			// 1949 &
+			if err := m.And(); err != nil {
+				return 1949, false, err
+			}
+			// 1950 const
+			if err := m.Push(1); err != nil {
+				return 1950, false, err
+			}
+			// 1955 =
+			if err := m.Eq(); err != nil {
+				return 1955, false, err
+			}
+			// 1956 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 1956, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_find is compiled from 'word.find'.
+func interpreter_word_find(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 1973: // word.find
+			// 1973 call
+			if err := m.PushReturn(1978); err != nil {
+				return 1973, false, err
+			}
+			return 3780, false, nil
+		case 1978:
+			// 1978 @
+			if err := m.Fetch(); err != nil {
+				return 1978, false, err
+			}
+			// 1979 rpush
+			if err := m.RPush(); err != nil {
+				return 1979, false, err
+			}
+			pc = 1980
+		case 1980: // word.find-loop
+			// 1980 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1980, false, err
+			}
+			// 1981 const
+			if err := m.Push(0); err != nil {
+				return 1981, false, err
+			}
+			// 1986 =
+			if err := m.Eq(); err != nil {
+				return 1986, false, err
+			}
+			// 1987 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 1987, false, err
+			} else if c != 0 {
+				pc = 2029
+			} else {
+				pc = 1992
+			}
+		case 1992:
+			// 1992 rpeek
+			if err := m.RPeek(); err != nil {
+				return 1992, false, err
+			}
+			// 1993 call
+			if err := m.PushReturn(1998); err != nil {
+				return 1993, false, err
+			}
+			return 1714, false, nil
+		case 1998:
+			// 1998 call
+			if err := m.PushReturn(2003); err != nil {
+				return 1998, false, err
+			}
+			return 1353, false, nil
+		case 2003:
+			// 2003 call
+			if err := m.PushReturn(2008); err != nil {
+				return 2003, false, err
+			}
+			return 798, false, nil
+		case 2008:
+			// 2008 rpeek
+			if err := m.RPeek(); err != nil {
+				return 2008, false, err
+			}
+			// 2009 call
+			if err := m.PushReturn(2014); err != nil {
+				return 2009, false, err
+			}
+			return 1938, false, nil
+		case 2014:
+			// 2014 ~
+			if err := m.Not(); err != nil {
+				return 2014, false, err
+			}
+			// 2015 &
+			if err := m.And(); err != nil {
+				return 2015, false, err
+			}
+			// 2016 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 2016, false, err
+			} else if c != 0 {
+				pc = 2029
+			} else {
+				pc = 2021
+			}
+		case 2021:
+			// 2021 rpop
+			if err := m.RPop(); err != nil {
+				return 2021, false, err
+			}
+			// 2022 @
+			if err := m.Fetch(); err != nil {
+				return 2022, false, err
+			}
+			// 2023 rpush
+			if err := m.RPush(); err != nil {
+				return 2023, false, err
+			}
+			// 2024 jmp
+			pc = 1980
+		case 2029: // word.find-exit
+			// 2029 rpop
+			if err := m.RPop(); err != nil {
+				return 2029, false, err
+			}
+			// 2030 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2030, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_compile_state is compiled from 'word.compile-state'.
+func interpreter_word_compile_state(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2056: // word.compile-state
+			// 2056 const
+			if err := m.Push(2062); err != nil {
+				return 2056, false, err
+			}
+			// 2061 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2061, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_here is compiled from 'word.here'.
+func interpreter_word_here(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2082: // word.here
+			// 2082 const
+			if err := m.Push(2088); err != nil {
+				return 2082, false, err
+			}
+			// 2087 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2087, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_append is compiled from 'word.append'.
+func interpreter_word_append(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2092: // word.append
+			// 2092 call
+			if err := m.PushReturn(2097); err != nil {
+				return 2092, false, err
+			}
+			return 2082, false, nil
+		case 2097:
+			// 2097 @
+			if err := m.Fetch(); err != nil {
+				return 2097, false, err
+			}
+			// 2098 !
+			if err := m.Store(); err != nil {
+				return 2098, false, err
+			}
+			// 2099 call
+			if err := m.PushReturn(2104); err != nil {
+				return 2099, false, err
+			}
+			return 2082, false, nil
+		case 2104:
+			// 2104 dup
+			if err := m.Dup(); err != nil {
+				return 2104, false, err
+			}
+			// 2105 @
+			if err := m.Fetch(); err != nil {
+				return 2105, false, err
+			}
+			// 2106 call
+			if err := m.PushReturn(2111); err != nil {
+				return 2106, false, err
+			}
+			return 382, false, nil
+		case 2111:
+			// 2111 swap
+			if err := m.Swap(); err != nil {
+				return 2111, false, err
+			}
+			// 2112 !
+			if err := m.Store(); err != nil {
+				return 2112, false, err
+			}
+			// 2113 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2113, false, err
+			}
+			pc = target
+		case 2114: // word.append-byte
+			// 2114 call
+			if err := m.PushReturn(2119); err != nil {
+				return 2114, false, err
+			}
+			return 2082, false, nil
+		case 2119:
+			// 2119 @
+			if err := m.Fetch(); err != nil {
+				return 2119, false, err
+			}
+			// 2120 b!
+			if err := m.BStore(); err != nil {
+				return 2120, false, err
+			}
+			// 2121 call
+			if err := m.PushReturn(2126); err != nil {
+				return 2121, false, err
+			}
+			return 2082, false, nil
+		case 2126:
+			// 2126 dup
+			if err := m.Dup(); err != nil {
+				return 2126, false, err
+			}
+			// 2127 @
+			if err := m.Fetch(); err != nil {
+				return 2127, false, err
+			}
+			// 2128 const
+			if err := m.Push(1); err != nil {
+				return 2128, false, err
+			}
+			// 2133 +
+			if err := m.Add(); err != nil {
+				return 2133, false, err
+			}
+			// 2134 swap
+			if err := m.Swap(); err != nil {
+				return 2134, false, err
+			}
+			// 2135 !
+			if err := m.Store(); err != nil {
+				return 2135, false, err
+			}
+			// 2136 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2136, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_create_header is compiled from 'word.create-header'.
+func interpreter_word_create_header(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2137: // word.create-header
+			// 2137 call
+			if err := m.PushReturn(2142); err != nil {
+				return 2137, false, err
+			}
+			return 2082, false, nil
+		case 2142:
+			// 2142 @
+			if err := m.Fetch(); err != nil {
+				return 2142, false, err
+			}
+			// 2143 call
+			if err := m.PushReturn(2148); err != nil {
+				return 2143, false, err
+			}
+			return 3780, false, nil
+		case 2148:
+			// 2148 @
+			if err := m.Fetch(); err != nil {
+				return 2148, false, err
+			}
+			// 2149 call
+			if err := m.PushReturn(2154); err != nil {
+				return 2149, false, err
+			}
+			return 2092, false, nil
+		case 2154:
+			// 2154 call
+			if err := m.PushReturn(2159); err != nil {
+				return 2154, false, err
+			}
+			return 3780, false, nil
+		case 2159:
+			// 2159 !
+			if err := m.Store(); err != nil {
+				return 2159, false, err
+			}
+			// 2160 const
+			if err := m.Push(0); err != nil {
+				return 2160, false, err
+			}
+			// 2165 call
+			if err := m.PushReturn(2170); err != nil {
+				return 2165, false, err
+			}
+			return 2114, false, nil
+		case 2170:
+			// 2170 call
+			if err := m.PushReturn(2175); err != nil {
+				return 2170, false, err
+			}
+			return 1353, false, nil
+		case 2175:
+			// 2175 call
+			if err := m.PushReturn(2180); err != nil {
+				return 2175, false, err
+			}
+			return 2082, false, nil
+		case 2180:
+			// 2180 @
+			if err := m.Fetch(); err != nil {
+				return 2180, false, err
+			}
+			// 2181 over
+			if err := m.Over(); err != nil {
+				return 2181, false, err
+			}
+			// 2182 call
+			if err := m.PushReturn(2187); err != nil {
+				return 2182, false, err
+			}
+			return 719, false, nil
+		case 2187:
+			// 2187 call
+			if err := m.PushReturn(2192); err != nil {
+				return 2187, false, err
+			}
+			return 697, false, nil
+		case 2192:
+			// 2192 call
+			if err := m.PushReturn(2197); err != nil {
+				return 2192, false, err
+			}
+			return 888, false, nil
+		case 2197:
+			// 2197 call
+			if err := m.PushReturn(2202); err != nil {
+				return 2197, false, err
+			}
+			return 3780, false, nil
+		case 2202:
+			// 2202 @
+			if err := m.Fetch(); err != nil {
+				return 2202, false, err
+			}
+			// 2203 call
+			if err := m.PushReturn(2208); err != nil {
+				return 2203, false, err
+			}
+			return 1742, false, nil
+		case 2208:
+			// 2208 call
+			if err := m.PushReturn(2213); err != nil {
+				return 2208, false, err
+			}
+			return 2082, false, nil
+		case 2213:
+			// 2213 !
+			if err := m.Store(); err != nil {
+				return 2213, false, err
+			}
+			// 2214 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2214, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x5b is compiled from '['.
+func interpreter_x5b(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2223: // [
+			// 2223 call
+			if err := m.PushReturn(2228); err != nil {
+				return 2223, false, err
+			}
+			return 418, false, nil
+		case 2228:
+			// 2228 call
+			if err := m.PushReturn(2233); err != nil {
+				return 2228, false, err
+			}
+			return 2056, false, nil
+		case 2233:
+			// 2233 !
+			if err := m.Store(); err != nil {
+				return 2233, false, err
+			}
+			// 2234 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2234, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x5d is compiled from ']'.
+func interpreter_x5d(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2243: // ]
+			// 2243 call
+			if err := m.PushReturn(2248); err != nil {
+				return 2243, false, err
+			}
+			return 400, false, nil
+		case 2248:
+			// 2248 call
+			if err := m.PushReturn(2253); err != nil {
+				return 2248, false, err
+			}
+			return 2056, false, nil
+		case 2253:
+			// 2253 !
+			if err := m.Store(); err != nil {
+				return 2253, false, err
+			}
+			// 2254 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2254, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x3a is compiled from ':'.
+func interpreter_x3a(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2263: // :
+			// 2263 call
+			if err := m.PushReturn(2268); err != nil {
+				return 2263, false, err
+			}
+			return 1429, false, nil
+		case 2268:
+			// 2268 call
+			if err := m.PushReturn(2273); err != nil {
+				return 2268, false, err
+			}
+			return 2137, false, nil
+		case 2273:
+			// 2273 call
+			if err := m.PushReturn(2278); err != nil {
+				return 2273, false, err
+			}
+			return 1857, false, nil
+		case 2278:
+			// 2278 call
+			if err := m.PushReturn(2283); err != nil {
+				return 2278, false, err
+			}
+			return 2243, false, nil
+		case 2283:
+			// 2283 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2283, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x3b is compiled from ';'.
+func interpreter_x3b(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2292: // ;
+			// 2292 call
+			if err := m.PushReturn(2297); err != nil {
+				return 2292, false, err
+			}
+			return 436, false, nil
+		case 2297:
+			// 2297 call
+			if err := m.PushReturn(2302); err != nil {
+				return 2297, false, err
+			}
+			return 2114, false, nil
+		case 2302:
+			// 2302 call
+			if err := m.PushReturn(2307); err != nil {
+				return 2302, false, err
+			}
+			return 1897, false, nil
+		case 2307:
+			// 2307 call
+			if err := m.PushReturn(2312); err != nil {
+				return 2307, false, err
+			}
+			return 2223, false, nil
+		case 2312:
+			// 2312 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2312, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_immediate is compiled from 'immediate'.
+func interpreter_immediate(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2329: // immediate
+			// 2329 call
+			if err := m.PushReturn(2334); err != nil {
+				return 2329, false, err
+			}
+			return 1778, false, nil
+		case 2334:
+			// 2334 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2334, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x2c is compiled from ','.
+func interpreter_x2c(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2343: // ,
+			// 2343 call
+			if err := m.PushReturn(2348); err != nil {
+				return 2343, false, err
+			}
+			return 2092, false, nil
+		case 2348:
+			// 2348 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2348, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_bx2c is compiled from 'b,'.
+func interpreter_bx2c(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2358: // b,
+			// 2358 call
+			if err := m.PushReturn(2363); err != nil {
+				return 2358, false, err
+			}
+			return 2114, false, nil
+		case 2363:
+			// 2363 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2363, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word is compiled from 'word'.
+func interpreter_word(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2375: // word
+			// 2375 call
+			if err := m.PushReturn(2380); err != nil {
+				return 2375, false, err
+			}
+			return 1429, false, nil
+		case 2380:
+			// 2380 call
+			if err := m.PushReturn(2385); err != nil {
+				return 2380, false, err
+			}
+			return 1353, false, nil
+		case 2385:
+			// 2385 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2385, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_find is compiled from 'find'.
+func interpreter_find(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2397: // find
+			// 2397 call
+			if err := m.PushReturn(2402); err != nil {
+				return 2397, false, err
+			}
+			return 1353, false, nil
+		case 2402:
+			// 2402 call
+			if err := m.PushReturn(2407); err != nil {
+				return 2402, false, err
+			}
+			return 888, false, nil
+		case 2407:
+			// 2407 call
+			if err := m.PushReturn(2412); err != nil {
+				return 2407, false, err
+			}
+			return 1973, false, nil
+		case 2412:
+			// 2412 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2412, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_number is compiled from 'number'.
+func interpreter_number(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2426: // number
+			// 2426 call
+			if err := m.PushReturn(2431); err != nil {
+				return 2426, false, err
+			}
+			return 1034, false, nil
+		case 2431:
+			// 2431 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2431, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_x28 is compiled from '('.
+func interpreter_x28(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2440: // (
+			// 2440 key
+			if eof, err := m.Key(); err != nil {
+				return 2440, false, err
+			} else if eof {
+				return 2440, true, nil
+			}
+			// 2441 const
+			if err := m.Push(41); err != nil {
+				return 2441, false, err
+			}
+			// 2446 =
+			if err := m.Eq(); err != nil {
+				return 2446, false, err
+			}
+			// 2447 ~
+			if err := m.Not(); err != nil {
+				return 2447, false, err
+			}
+			// 2448 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 2448, false, err
+			} else if c != 0 {
+				pc = 2440
+			} else {
+				pc = 2453
+			}
+		case 2453:
+			// 2453 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2453, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_compile_falsex3f is compiled from 'compile-false?'.
+func interpreter_compile_falsex3f(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2454: // compile-false?
+			// 2454 call
+			if err := m.PushReturn(2459); err != nil {
+				return 2454, false, err
+			}
+			return 512, false, nil
+		case 2459:
+			// 2459 call
+			if err := m.PushReturn(2464); err != nil {
+				return 2459, false, err
+			}
+			return 2114, false, nil
+		case 2464:
+			// 2464 const
+			if err := m.Push(0); err != nil {
+				return 2464, false, err
+			}
+			// 2469 call
+			if err := m.PushReturn(2474); err != nil {
+				return 2469, false, err
+			}
+			return 2092, false, nil
+		case 2474:
+			// 2474 call
+			if err := m.PushReturn(2479); err != nil {
+				return 2474, false, err
+			}
+			return 528, false, nil
+		case 2479:
+			// 2479 call
+			if err := m.PushReturn(2484); err != nil {
+				return 2479, false, err
+			}
+			return 2114, false, nil
+		case 2484:
+			// 2484 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2484, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_if is compiled from 'if'.
+func interpreter_if(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2494: // if
+			// 2494 call
+			if err := m.PushReturn(2499); err != nil {
+				return 2494, false, err
+			}
+			return 2454, false, nil
+		case 2499:
+			// 2499 call
+			if err := m.PushReturn(2504); err != nil {
+				return 2499, false, err
+			}
+			return 473, false, nil
+		case 2504:
+			// 2504 call
+			if err := m.PushReturn(2509); err != nil {
+				return 2504, false, err
+			}
+			return 2114, false, nil
+		case 2509:
+			// 2509 call
+			if err := m.PushReturn(2514); err != nil {
+				return 2509, false, err
+			}
+			return 2082, false, nil
+		case 2514:
+			// 2514 @
+			if err := m.Fetch(); err != nil {
+				return 2514, false, err
+			}
+			// 2515 const
+			if err := m.Push(0); err != nil {
+				return 2515, false, err
+			}
+			// 2520 call
+			if err := m.PushReturn(2525); err != nil {
+				return 2520, false, err
+			}
+			return 2092, false, nil
+		case 2525:
+			// 2525 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2525, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_else is compiled from 'else'.
+func interpreter_else(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2537: // else
+			// 2537 call
+			if err := m.PushReturn(2542); err != nil {
+				return 2537, false, err
+			}
+			return 454, false, nil
+		case 2542:
+			// 2542 call
+			if err := m.PushReturn(2547); err != nil {
+				return 2542, false, err
+			}
+			return 2114, false, nil
+		case 2547:
+			// 2547 call
+			if err := m.PushReturn(2552); err != nil {
+				return 2547, false, err
+			}
+			return 2082, false, nil
+		case 2552:
+			// 2552 @
+			if err := m.Fetch(); err != nil {
+				return 2552, false, err
+			}
+			// 2553 const
+			if err := m.Push(0); err != nil {
+				return 2553, false, err
+			}
+			// 2558 call
+			if err := m.PushReturn(2563); err != nil {
+				return 2558, false, err
+			}
+			return 2092, false, nil
+		case 2563:
+			// 2563 swap
+			if err := m.Swap(); err != nil {
+				return 2563, false, err
+			}
+			// 2564 call
+			if err := m.PushReturn(2569); err != nil {
+				return 2564, false, err
+			}
+			return 2581, false, nil
+		case 2569:
+			// 2569 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2569, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_then is compiled from 'then'.
+func interpreter_then(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2581: // then
+			// 2581 call
+			if err := m.PushReturn(2586); err != nil {
+				return 2581, false, err
+			}
+			return 2082, false, nil
+		case 2586:
+			// 2586 @
+			if err := m.Fetch(); err != nil {
+				return 2586, false, err
+			}
+			// 2587 swap
+			if err := m.Swap(); err != nil {
+				return 2587, false, err
+			}
+			// 2588 !
+			if err := m.Store(); err != nil {
+				return 2588, false, err
+			}
+			// 2589 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2589, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_begin is compiled from 'begin'.
+func interpreter_begin(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2602: // begin
+			// 2602 call
+			if err := m.PushReturn(2607); err != nil {
+				return 2602, false, err
+			}
+			return 2082, false, nil
+		case 2607:
+			// 2607 @
+			if err := m.Fetch(); err != nil {
+				return 2607, false, err
+			}
+			// 2608 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2608, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_until is compiled from 'until'.
+func interpreter_until(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2621: // until
+			// 2621 call
+			if err := m.PushReturn(2626); err != nil {
+				return 2621, false, err
+			}
+			return 2454, false, nil
+		case 2626:
+			// 2626 call
+			if err := m.PushReturn(2631); err != nil {
+				return 2626, false, err
+			}
+			return 473, false, nil
+		case 2631:
+			// 2631 call
+			if err := m.PushReturn(2636); err != nil {
+				return 2631, false, err
+			}
+			return 2114, false, nil
+		case 2636:
+			// 2636 call
+			if err := m.PushReturn(2641); err != nil {
+				return 2636, false, err
+			}
+			return 2092, false, nil
+		case 2641:
+			// 2641 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2641, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_again is compiled from 'again'.
+func interpreter_again(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2654: // again
+			// 2654 call
+			if err := m.PushReturn(2659); err != nil {
+				return 2654, false, err
+			}
+			return 454, false, nil
+		case 2659:
+			// 2659 call
+			if err := m.PushReturn(2664); err != nil {
+				return 2659, false, err
+			}
+			return 2114, false, nil
+		case 2664:
+			// 2664 call
+			if err := m.PushReturn(2669); err != nil {
+				return 2664, false, err
+			}
+			return 2092, false, nil
+		case 2669:
+			// 2669 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2669, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_string_buffer is compiled from 'string.buffer'.
+func interpreter_string_buffer(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2690: // string.buffer
+			// 2690 const
+			if err := m.Push(2696); err != nil {
+				return 2690, false, err
+			}
+			// 2695 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2695, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_string_read is compiled from 'string.read'.
+func interpreter_string_read(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2826: // string.read
+			// 2826 call
+			if err := m.PushReturn(2831); err != nil {
+				return 2826, false, err
+			}
+			return 697, false, nil
+		case 2831: // string.read-loop
+			// 2831 key
+			if eof, err := m.Key(); err != nil {
+				return 2831, false, err
+			} else if eof {
+				return 2831, true, nil
+			}
+			// 2832 dup
+			if err := m.Dup(); err != nil {
+				return 2832, false, err
+			}
+			// 2833 const
+			if err := m.Push(34); err != nil {
+				return 2833, false, err
+			}
+			// 2838 =
+			if err := m.Eq(); err != nil {
+				return 2838, false, err
+			}
+			// 2839 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 2839, false, err
+			} else if c != 0 {
+				pc = 2855
+			} else {
+				pc = 2844
+			}
+		case 2844:
+			// 2844 over
+			if err := m.Over(); err != nil {
+				return 2844, false, err
+			}
+			// 2845 call
+			if err := m.PushReturn(2850); err != nil {
+				return 2845, false, err
+			}
+			return 729, false, nil
+		case 2850:
+			// 2850 jmp
+			pc = 2831
+		case 2855: // string.read-end
+			// 2855 drop
+			if err := m.Drop(); err != nil {
+				return 2855, false, err
+			}
+			// 2856 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2856, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_string_compile is compiled from 'string.compile'.
+func interpreter_string_compile(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2857: // string.compile
+			// 2857 call
+			if err := m.PushReturn(2862); err != nil {
+				return 2857, false, err
+			}
+			return 454, false, nil
+		case 2862:
+			// 2862 call
+			if err := m.PushReturn(2867); err != nil {
+				return 2862, false, err
+			}
+			return 2114, false, nil
+		case 2867:
+			// 2867 call
+			if err := m.PushReturn(2872); err != nil {
+				return 2867, false, err
+			}
+			return 2082, false, nil
+		case 2872:
+			// 2872 @
+			if err := m.Fetch(); err != nil {
+				return 2872, false, err
+			}
+			// 2873 const
+			if err := m.Push(0); err != nil {
+				return 2873, false, err
+			}
+			// 2878 call
+			if err := m.PushReturn(2883); err != nil {
+				return 2878, false, err
+			}
+			return 2092, false, nil
+		case 2883:
+			// 2883 call
+			if err := m.PushReturn(2888); err != nil {
+				return 2883, false, err
+			}
+			return 2082, false, nil
+		case 2888:
+			// 2888 @
+			if err := m.Fetch(); err != nil {
+				return 2888, false, err
+			}
+			// 2889 const
+			if err := m.Push(255); err != nil {
+				return 2889, false, err
+			}
+			// 2894 call
+			if err := m.PushReturn(2899); err != nil {
+				return 2894, false, err
+			}
+			return 2826, false, nil
+		case 2899:
+			// 2899 dup
+			if err := m.Dup(); err != nil {
+				return 2899, false, err
+			}
+			// 2900 call
+			if err := m.PushReturn(2905); err != nil {
+				return 2900, false, err
+			}
+			return 719, false, nil
+		case 2905:
+			// 2905 over
+			if err := m.Over(); err != nil {
+				return 2905, false, err
+			}
+			// 2906 const
+			if err := m.Push(1); err != nil {
+				return 2906, false, err
+			}
+			// 2911 +
+			if err := m.Add(); err != nil {
+				return 2911, false, err
+			}
+			// 2912 b!
+			if err := m.BStore(); err != nil {
+				return 2912, false, err
+			}
+			// 2913 dup
+			if err := m.Dup(); err != nil {
+				return 2913, false, err
+			}
+			// 2914 call
+			if err := m.PushReturn(2919); err != nil {
+				return 2914, false, err
+			}
+			return 719, false, nil
+		case 2919:
+			// 2919 const
+			if err := m.Push(2); err != nil {
+				return 2919, false, err
+			}
+			// 2924 +
+			if err := m.Add(); err != nil {
+				return 2924, false, err
+			}
+			// 2925 call
+			if err := m.PushReturn(2930); err != nil {
+				return 2925, false, err
+			}
+			return 2082, false, nil
+		case 2930:
+			// 2930 @
+			if err := m.Fetch(); err != nil {
+				return 2930, false, err
+			}
+			// 2931 +
+			if err := m.Add(); err != nil {
+				return 2931, false, err
+			}
+			// 2932 call
+			if err := m.PushReturn(2937); err != nil {
+				return 2932, false, err
+			}
+			return 2082, false, nil
+		case 2937:
+			// 2937 !
+			if err := m.Store(); err != nil {
+				return 2937, false, err
+			}
+			// 2938 swap
+			if err := m.Swap(); err != nil {
+				return 2938, false, err
+			}
+			// 2939 call
+			if err := m.PushReturn(2944); err != nil {
+				return 2939, false, err
+			}
+			return 2082, false, nil
+		case 2944:
+			// 2944 @
+			if err := m.Fetch(); err != nil {
+				return 2944, false, err
+			}
+			// 2945 swap
+			if err := m.Swap(); err != nil {
+				return 2945, false, err
+			}
+			// 2946 !
+			if err := m.Store(); err != nil {
+				return 2946, false, err
+			}
+			// 2947 call
+			if err := m.PushReturn(2952); err != nil {
+				return 2947, false, err
+			}
+			return 512, false, nil
+		case 2952:
+			// 2952 call
+			if err := m.PushReturn(2957); err != nil {
+				return 2952, false, err
+			}
+			return 2114, false, nil
+		case 2957:
+			// 2957 call
+			if err := m.PushReturn(2962); err != nil {
+				return 2957, false, err
+			}
+			return 2092, false, nil
+		case 2962:
+			// 2962 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2962, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_sx22 is compiled from 's"'.
+func interpreter_sx22(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 2972: // s"
+			// 2972 call
+			if err := m.PushReturn(2977); err != nil {
+				return 2972, false, err
+			}
+			return 2056, false, nil
+		case 2977:
+			// 2977 @
+			if err := m.Fetch(); err != nil {
+				return 2977, false, err
+			}
+			// 2978 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 2978, false, err
+			} else if c != 0 {
+				return 2857, false, nil
+			} else {
+				pc = 2983
+			}
+		case 2983:
+			// 2983 call
+			if err := m.PushReturn(2988); err != nil {
+				return 2983, false, err
+			}
+			return 2690, false, nil
+		case 2988:
+			// 2988 const
+			if err := m.Push(128); err != nil {
+				return 2988, false, err
+			}
+			// 2993 call
+			if err := m.PushReturn(2998); err != nil {
+				return 2993, false, err
+			}
+			return 2826, false, nil
+		case 2998:
+			// 2998 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 2998, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter__x22 is compiled from '."'.
+func interpreter__x22(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3008: // ."
+			// 3008 call
+			if err := m.PushReturn(3013); err != nil {
+				return 3008, false, err
+			}
+			return 2056, false, nil
+		case 3013:
+			// 3013 @
+			if err := m.Fetch(); err != nil {
+				return 3013, false, err
+			}
+			// 3014 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 3014, false, err
+			} else if c != 0 {
+				return 3030, false, nil
+			} else {
+				pc = 3019
+			}
+		case 3019:
+			// 3019 call
+			if err := m.PushReturn(3024); err != nil {
+				return 3019, false, err
+			}
+			return 2972, false, nil
+		case 3024:
+			// 3024 call
+			if err := m.PushReturn(3029); err != nil {
+				return 3024, false, err
+			}
+			return 1151, false, nil
+		case 3029:
+			// 3029 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3029, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_string_print_compile is compiled from 'string.print-compile'.
+func interpreter_string_print_compile(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3030: // string.print-compile
+			// 3030 call
+			if err := m.PushReturn(3035); err != nil {
+				return 3030, false, err
+			}
+			return 2857, false, nil
+		case 3035:
+			// 3035 call
+			if err := m.PushReturn(3040); err != nil {
+				return 3035, false, err
+			}
+			return 492, false, nil
+		case 3040:
+			// 3040 call
+			if err := m.PushReturn(3045); err != nil {
+				return 3040, false, err
+			}
+			return 2114, false, nil
+		case 3045:
+			// 3045 const
+			if err := m.Push(1151); err != nil {
+				return 3045, false, err
+			}
+			// 3050 call
+			if err := m.PushReturn(3055); err != nil {
+				return 3050, false, err
+			}
+			return 2092, false, nil
+		case 3055:
+			// 3055 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3055, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_interpret is compiled from 'word.interpret'.
+func interpreter_word_interpret(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3077: // word.interpret
+			// 3077 call
+			if err := m.PushReturn(3082); err != nil {
+				return 3077, false, err
+			}
+			return 1429, false, nil
+		case 3082:
+			// 3082 call
+			if err := m.PushReturn(3087); err != nil {
+				return 3082, false, err
+			}
+			return 1973, false, nil
+		case 3087:
+			// 3087 dup
+			if err := m.Dup(); err != nil {
+				return 3087, false, err
+			}
+			// 3088 const
+			if err := m.Push(0); err != nil {
+				return 3088, false, err
+			}
+			// 3093 =
+			if err := m.Eq(); err != nil {
+				return 3093, false, err
+			}
+			// 3094 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 3094, false, err
+			} else if c != 0 {
+				pc = 3154
+			} else {
+				pc = 3099
+			}
+		case 3099:
+			// 3099 dup
+			if err := m.Dup(); err != nil {
+				return 3099, false, err
+			}
+			// 3100 call
+			if err := m.PushReturn(3105); err != nil {
+				return 3100, false, err
+			}
+			return 1742, false, nil
+		case 3105:
+			// 3105 swap
+			if err := m.Swap(); err != nil {
+				return 3105, false, err
+			}
+			// 3106 call
+			if err := m.PushReturn(3111); err != nil {
+				return 3106, false, err
+			}
+			return 1822, false, nil
+		case 3111:
+			// 3111 ~
+			if err := m.Not(); err != nil {
+				return 3111, false, err
+			}
+			// 3112 call
+			if err := m.PushReturn(3117); err != nil {
+				return 3112, false, err
+			}
+			return 2056, false, nil
+		case 3117:
+			// 3117 @
+			if err := m.Fetch(); err != nil {
+				return 3117, false, err
+			}
+			// 3118 &
+			if err := m.And(); err != nil {
+				return 3118, false, err
+			}
+			// 3119 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 3119, false, err
+			} else if c != 0 {
+				pc = 3134
+			} else {
+				pc = 3124
+			}
+		case 3124:
+			// 3124 call
+			if err := m.PushReturn(3129); err != nil {
+				return 3124, false, err
+			}
+			return 558, false, nil
+		case 3129:
+			// 3129 jmp
+			pc = 3077
+		case 3134: // word.interpret-compile
+			// 3134 call
+			if err := m.PushReturn(3139); err != nil {
+				return 3134, false, err
+			}
+			return 492, false, nil
+		case 3139:
+			// 3139 call
+			if err := m.PushReturn(3144); err != nil {
+				return 3139, false, err
+			}
+			return 2114, false, nil
+		case 3144:
+			// 3144 call
+			if err := m.PushReturn(3149); err != nil {
+				return 3144, false, err
+			}
+			return 2092, false, nil
+		case 3149:
+			// 3149 jmp
+			pc = 3077
+		case 3154: // word.interpret-number
+			// 3154 drop
+			if err := m.Drop(); err != nil {
+				return 3154, false, err
+			}
+			// 3155 call
+			if err := m.PushReturn(3160); err != nil {
+				return 3155, false, err
+			}
+			return 1353, false, nil
+		case 3160:
+			// 3160 call
+			if err := m.PushReturn(3165); err != nil {
+				return 3160, false, err
+			}
+			return 1034, false, nil
+		case 3165:
+			// 3165 dup
+			if err := m.Dup(); err != nil {
+				return 3165, false, err
+			}
+			// 3166 call
+			if err := m.PushReturn(3171); err != nil {
+				return 3166, false, err
+			}
+			return 655, false, nil
+		case 3171:
+			// 3171 =
+			if err := m.Eq(); err != nil {
+				return 3171, false, err
+			}
+			// 3172 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 3172, false, err
+			} else if c != 0 {
+				pc = 3213
+			} else {
+				pc = 3177
+			}
+		case 3177:
+			// 3177 call
+			if err := m.PushReturn(3182); err != nil {
+				return 3177, false, err
+			}
+			return 2056, false, nil
+		case 3182:
+			// 3182 @
+			if err := m.Fetch(); err != nil {
+				return 3182, false, err
+			}
+			// 3183 cjmp
+			if c, err := m.Pop(); err != nil {
+				return 3183, false, err
+			} else if c != 0 {
+				pc = 3193
+			} else {
+				pc = 3188
+			}
+		case 3188:
+			// 3188 jmp
+			pc = 3077
+		case 3193: // word.interpret-compile-number
+			// 3193 call
+			if err := m.PushReturn(3198); err != nil {
+				return 3193, false, err
+			}
+			return 512, false, nil
+		case 3198:
+			// 3198 call
+			if err := m.PushReturn(3203); err != nil {
+				return 3198, false, err
+			}
+			return 2114, false, nil
+		case 3203:
+			// 3203 call
+			if err := m.PushReturn(3208); err != nil {
+				return 3203, false, err
+			}
+			return 2092, false, nil
+		case 3208:
+			// 3208 jmp
+			pc = 3077
+		case 3213: // word.interpret-error
+			// 3213 const
+			if err := m.Push(3223); err != nil {
+				return 3213, false, err
+			}
+			// 3218 jmp
+			pc = 3240
+		case 3240:
+			// 3240 call
+			if err := m.PushReturn(3245); err != nil {
+				return 3240, false, err
+			}
+			return 1151, false, nil
+		case 3245:
+			// 3245 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3245, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_file_read_only is compiled from 'file.read-only'.
+func interpreter_file_read_only(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3267: // file.read-only
+			// 3267 const
+			if err := m.Push(0); err != nil {
+				return 3267, false, err
+			}
+			// 3272 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3272, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_file_write_only is compiled from 'file.write-only'.
+func interpreter_file_write_only(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3295: // file.write-only
+			// 3295 const
+			if err := m.Push(1); err != nil {
+				return 3295, false, err
+			}
+			// 3300 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3300, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_file_append is compiled from 'file.append'.
+func interpreter_file_append(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3319: // file.append
+			// 3319 const
+			if err := m.Push(2); err != nil {
+				return 3319, false, err
+			}
+			// 3324 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3324, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_file_open is compiled from 'file.open'.
+func interpreter_file_open(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3341: // file.open
+			// 3341 const
+			if err := m.Push(65536); err != nil {
+				return 3341, false, err
+			}
+			// 3346 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3346, false, err
+			}
+			return 3347, false, nil
+		case 3347:
+			// 3347 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3347, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_file_close is compiled from 'file.close'.
+func interpreter_file_close(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3365: // file.close
+			// 3365 const
+			if err := m.Push(65537); err != nil {
+				return 3365, false, err
+			}
+			// 3370 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3370, false, err
+			}
+			return 3371, false, nil
+		case 3371:
+			// 3371 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3371, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_file_read is compiled from 'file.read'.
+func interpreter_file_read(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3388: // file.read
+			// 3388 const
+			if err := m.Push(65538); err != nil {
+				return 3388, false, err
+			}
+			// 3393 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3393, false, err
+			}
+			return 3394, false, nil
+		case 3394:
+			// 3394 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3394, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_file_write is compiled from 'file.write'.
+func interpreter_file_write(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3412: // file.write
+			// 3412 const
+			if err := m.Push(65539); err != nil {
+				return 3412, false, err
+			}
+			// 3417 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3417, false, err
+			}
+			return 3418, false, nil
+		case 3418:
+			// 3418 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3418, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_clock_now is compiled from 'clock.now'.
+func interpreter_clock_now(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3435: // clock.now
+			// 3435 const
+			if err := m.Push(131072); err != nil {
+				return 3435, false, err
+			}
+			// 3440 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3440, false, err
+			}
+			return 3441, false, nil
+		case 3441:
+			// 3441 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3441, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_clock_millis is compiled from 'clock.millis'.
+func interpreter_clock_millis(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3461: // clock.millis
+			// 3461 const
+			if err := m.Push(131073); err != nil {
+				return 3461, false, err
+			}
+			// 3466 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3466, false, err
+			}
+			return 3467, false, nil
+		case 3467:
+			// 3467 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3467, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_clock_sleep is compiled from 'clock.sleep'.
+func interpreter_clock_sleep(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3486: // clock.sleep
+			// 3486 const
+			if err := m.Push(131074); err != nil {
+				return 3486, false, err
+			}
+			// 3491 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3491, false, err
+			}
+			return 3492, false, nil
+		case 3492:
+			// 3492 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3492, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_random_int is compiled from 'random.int'.
+func interpreter_random_int(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3510: // random.int
+			// 3510 const
+			if err := m.Push(196608); err != nil {
+				return 3510, false, err
+			}
+			// 3515 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3515, false, err
+			}
+			return 3516, false, nil
+		case 3516:
+			// 3516 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3516, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_random_below is compiled from 'random.below'.
+func interpreter_random_below(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3536: // random.below
+			// 3536 const
+			if err := m.Push(196609); err != nil {
+				return 3536, false, err
+			}
+			// 3541 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3541, false, err
+			}
+			return 3542, false, nil
+		case 3542:
+			// 3542 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3542, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_random_seed is compiled from 'random.seed'.
+func interpreter_random_seed(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3561: // random.seed
+			// 3561 const
+			if err := m.Push(196610); err != nil {
+				return 3561, false, err
+			}
+			// 3566 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3566, false, err
+			}
+			return 3567, false, nil
+		case 3567:
+			// 3567 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3567, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_format_string is compiled from 'format.string'.
+func interpreter_format_string(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3588: // format.string
+			// 3588 const
+			if err := m.Push(262144); err != nil {
+				return 3588, false, err
+			}
+			// 3593 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3593, false, err
+			}
+			return 3594, false, nil
+		case 3594:
+			// 3594 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3594, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_format_print is compiled from 'format.print'.
+func interpreter_format_print(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3614: // format.print
+			// 3614 const
+			if err := m.Push(262145); err != nil {
+				return 3614, false, err
+			}
+			// 3619 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3619, false, err
+			}
+			return 3620, false, nil
+		case 3620:
+			// 3620 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3620, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_task_finish is compiled from 'task.finish'.
+func interpreter_task_finish(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3621: // task.finish
+			// 3621 exit
+			return 3621, true, nil
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_task_spawn is compiled from 'task.spawn'.
+func interpreter_task_spawn(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3639: // task.spawn
+			// 3639 const
+			if err := m.Push(3621); err != nil {
+				return 3639, false, err
+			}
+			// 3644 swap
+			if err := m.Swap(); err != nil {
+				return 3644, false, err
+			}
+			// 3645 const
+			if err := m.Push(327680); err != nil {
+				return 3645, false, err
+			}
+			// 3650 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3650, false, err
+			}
+			return 3651, false, nil
+		case 3651:
+			// 3651 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3651, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_task_yield is compiled from 'task.yield'.
+func interpreter_task_yield(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3669: // task.yield
+			// 3669 const
+			if err := m.Push(327681); err != nil {
+				return 3669, false, err
+			}
+			// 3674 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3674, false, err
+			}
+			return 3675, false, nil
+		case 3675:
+			// 3675 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3675, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_task_send is compiled from 'task.send'.
+func interpreter_task_send(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3692: // task.send
+			// 3692 const
+			if err := m.Push(327682); err != nil {
+				return 3692, false, err
+			}
+			// 3697 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3697, false, err
+			}
+			return 3698, false, nil
+		case 3698:
+			// 3698 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3698, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_task_receive is compiled from 'task.receive'.
+func interpreter_task_receive(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3718: // task.receive
+			// 3718 const
+			if err := m.Push(327683); err != nil {
+				return 3718, false, err
+			}
+			// 3723 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3723, false, err
+			}
+			return 3724, false, nil
+		case 3724:
+			// 3724 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3724, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_task_id is compiled from 'task.id'.
+func interpreter_task_id(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3739: // task.id
+			// 3739 const
+			if err := m.Push(327684); err != nil {
+				return 3739, false, err
+			}
+			// 3744 excall
+			if err := m.ExtensionCall(); err != nil {
+				return 3744, false, err
+			}
+			return 3745, false, nil
+		case 3745:
+			// 3745 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3745, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_main is compiled from 'main'.
+func interpreter_main(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3746: // main
+			// 3746 call
+			if err := m.PushReturn(3751); err != nil {
+				return 3746, false, err
+			}
+			return 2223, false, nil
+		case 3751:
+			// 3751 call
+			if err := m.PushReturn(3756); err != nil {
+				return 3751, false, err
+			}
+			return 3077, false, nil
+		case 3756:
+			// 3756 drop
+			if err := m.Drop(); err != nil {
+				return 3756, false, err
+			}
+			// 3757 jmp
+			pc = 3746
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_word_latest is compiled from 'word.latest'.
+func interpreter_word_latest(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3780: // word.latest
+			// 3780 const
+			if err := m.Push(3786); err != nil {
+				return 3780, false, err
+			}
+			// 3785 ret
+			target, err := m.PopReturn()
+			if err != nil {
+				return 3785, false, err
+			}
+			pc = target
+		default:
+			return pc, false, nil
+		}
+	}
+}
+
+// interpreter_init is compiled from 'init'.
+func interpreter_init(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {
+	for {
+		switch pc {
+		case 3790: // init
+			// 3790 const
+			if err := m.Push(3762); err != nil {
+				return 3790, false, err
+			}
+			// 3795 const
+			if err := m.Push(3786); err != nil {
+				return 3795, false, err
+			}
+			// 3800 !
+			if err := m.Store(); err != nil {
+				return 3800, false, err
+			}
+			// 3801 const
+			if err := m.Push(3817); err != nil {
+				return 3801, false, err
+			}
+			// 3806 const
+			if err := m.Push(2088); err != nil {
+				return 3806, false, err
+			}
+			// 3811 !
+			if err := m.Store(); err != nil {
+				return 3811, false, err
+			}
+			// 3812 jmp
+			return 3746, false, nil
+		default:
+			return pc, false, nil
+		}
+	}
+}
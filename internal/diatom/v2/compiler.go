@@ -0,0 +1,400 @@
+package diatom
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CompiledFunc runs the compiled code starting at pc until control
+// leaves the function. It returns the address to continue at and if
+// the program has finished. On errors the returned address is the
+// one of the failing instruction.
+type CompiledFunc func(m *Machine, pc Word) (Word, bool, error)
+
+// CompiledProgram is a memory image together with the Go functions
+// generated for its code by CompileGo.
+type CompiledProgram struct {
+	Image []byte
+	// Blocks maps the start address of every compiled block to the
+	// function that contains it.
+	Blocks map[Word]CompiledFunc
+}
+
+// ExecuteCompiled runs the VM like Execute but uses the compiled
+// functions of p whenever the program counter reaches one of their
+// blocks. Everything else, e.g. code compiled by the Forth
+// interpreter at runtime or the targets of computed jumps in the
+// middle of a block, is executed by the interpreter. The VM has to be
+// created from p.Image and the program must not modify the code of
+// the image. Compiled code is neither traced nor profiled.
+func (vm *VM) ExecuteCompiled(p CompiledProgram) error {
+	blocks := make([]CompiledFunc, min(len(p.Image), len(vm.memory)))
+	for addr, f := range p.Blocks {
+		if addr >= 0 && int(addr) < len(blocks) {
+			blocks[addr] = f
+		}
+	}
+
+	m := &Machine{vm: vm}
+	for {
+		pc := vm.programCounter
+		if pc < 0 || int(pc) >= len(blocks) || blocks[pc] == nil {
+			done, err := vm.step()
+			if err != nil {
+				return err
+			}
+			if done {
+				return nil
+			}
+			continue
+		}
+
+		next, done, err := blocks[pc](m, pc)
+		vm.programCounter = next
+		if err == nil {
+			done, err = vm.schedule(done)
+		}
+		if err != nil {
+			return vm.locate(err, next)
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+// CompilerOptions configures the Go source generated by CompileGo.
+type CompilerOptions struct {
+	// Package is the name of the generated package, "main" by default.
+	Package string
+	// Name is the name of the generated CompiledProgram variable,
+	// "Program" by default. It also prefixes the generated functions.
+	Name string
+}
+
+func (o CompilerOptions) withDefaults() CompilerOptions {
+	if o.Package == "" {
+		o.Package = "main"
+	}
+	if o.Name == "" {
+		o.Name = "Program"
+	}
+
+	return o
+}
+
+type compiledFunc struct {
+	name   string
+	label  string
+	addr   Word
+	end    Word
+	blocks []Word
+}
+
+type goCompiler struct {
+	*disassembler
+	opts      CompilerOptions
+	code      map[Word]disasmItem
+	blockAddr map[Word]struct{}
+	labels    map[Word][]string
+	funcs     []*compiledFunc
+}
+
+// goIdentifier turns a label into a valid Go identifier.
+func goIdentifier(label string) string {
+	b := strings.Builder{}
+	for _, r := range label {
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case r == '.' || r == '-' || r == '_':
+			b.WriteRune('_')
+		default:
+			fmt.Fprintf(&b, "x%02x", r)
+		}
+	}
+
+	return b.String()
+}
+
+func (c *goCompiler) instruction(addr Word) (disasmItem, bool) {
+	item, ok := c.code[addr]
+	return item, ok
+}
+
+// findBlocks marks the addresses execution can continue at: labels,
+// jump and call targets and the instructions after calls, conditional
+// jumps and extension calls.
+func (c *goCompiler) findBlocks(symbols Symbols) {
+	mark := func(addr Word) {
+		if _, ok := c.instruction(addr); ok {
+			c.blockAddr[addr] = struct{}{}
+		}
+	}
+
+	mark(0)
+	for _, addr := range symbols {
+		mark(addr)
+	}
+	for _, item := range c.code {
+		opcode := c.memory[item.addr]
+		switch opcode {
+		case JMP, CJMP, CALL:
+			mark(c.word(item.addr + 1))
+		}
+		switch opcode {
+		case CJMP, CALL, EXCALL:
+			mark(item.addr + item.size)
+		}
+	}
+}
+
+// findFuncs groups the code into one function per codeword. Local
+// labels like 'word.find-loop' belong to the function of their
+// codeword.
+func (c *goCompiler) findFuncs(symbols Symbols) {
+	for label, addr := range symbols {
+		if generatedLabel(label) || addr < 0 || addr >= Word(len(c.memory)) {
+			continue
+		}
+		c.labels[addr] = append(c.labels[addr], label)
+	}
+	for _, labels := range c.labels {
+		slices.SortFunc(labels, func(a, b string) int {
+			if preferLabel(a, b) {
+				return -1
+			}
+			return 1
+		})
+	}
+
+	addrs := slices.Sorted(func(yield func(Word) bool) {
+		for addr := range c.labels {
+			if !yield(addr) {
+				return
+			}
+		}
+	})
+	if len(addrs) == 0 || addrs[0] != 0 {
+		addrs = append([]Word{0}, addrs...)
+		c.labels[0] = []string{"start"}
+	}
+
+	names := map[string]struct{}{}
+	var current *compiledFunc
+	for _, addr := range addrs {
+		label := c.labels[addr][0]
+		if current != nil && strings.HasPrefix(label, current.label+"-") {
+			continue
+		}
+
+		name := strings.ToLower(c.opts.Name[:1]) + c.opts.Name[1:] + "_" + goIdentifier(label)
+		if _, ok := names[name]; ok {
+			name += "_" + strconv.Itoa(int(addr))
+		}
+		names[name] = struct{}{}
+
+		if current != nil {
+			current.end = addr
+		}
+		current = &compiledFunc{name: name, label: label, addr: addr}
+		c.funcs = append(c.funcs, current)
+	}
+	current.end = Word(len(c.memory))
+
+	for _, f := range c.funcs {
+		for addr := f.addr; addr < f.end; addr++ {
+			if _, ok := c.blockAddr[addr]; ok {
+				f.blocks = append(f.blocks, addr)
+			}
+		}
+	}
+	c.funcs = slices.DeleteFunc(c.funcs, func(f *compiledFunc) bool {
+		return len(f.blocks) == 0
+	})
+}
+
+var machineMethods = map[byte]string{
+	DUP: "Dup", DROP: "Drop", SWAP: "Swap", OVER: "Over",
+	RPUSH: "RPush", RPOP: "RPop", RPEEK: "RPeek",
+	STORE: "Store", FETCH: "Fetch", BSTORE: "BStore", BFETCH: "BFetch",
+	ADD: "Add", SUB: "Sub", MULT: "Mult", DIV: "Div", MOD: "Mod",
+	EQ: "Eq", NOT: "Not", AND: "And", OR: "Or", LT: "Lt", GT: "Gt",
+	EMIT: "Emit", DUMP: "Dump", DEPTH: "Depth",
+}
+
+// jump continues at target inside of f or returns to the caller if
+// target belongs to another function.
+func jump(f *compiledFunc, target Word) string {
+	if _, ok := slices.BinarySearch(f.blocks, target); ok {
+		return fmt.Sprintf("pc = %d", target)
+	}
+	return fmt.Sprintf("return %d, false, nil", target)
+}
+
+// writeBlock writes the instructions from addr up to the end of the
+// basic block.
+func (c *goCompiler) writeBlock(w *bytes.Buffer, f *compiledFunc, addr Word) {
+	fail := func(addr Word) string {
+		return fmt.Sprintf("return %d, false, err", addr)
+	}
+
+	for {
+		item, ok := c.instruction(addr)
+		if !ok {
+			fmt.Fprintf(w, "%s\n", jump(f, addr))
+			return
+		}
+		next := addr + item.size
+		opcode := c.memory[addr]
+		fmt.Fprintf(w, "// %d %s\n", addr, instructionFromOpcode(opcode))
+
+		switch opcode {
+		case EXIT:
+			fmt.Fprintf(w, "return %d, true, nil\n", addr)
+			return
+		case RET:
+			fmt.Fprintf(w, "target, err := m.PopReturn()\nif err != nil {\n%s\n}\npc = target\n", fail(addr))
+			return
+		case JMP:
+			fmt.Fprintf(w, "%s\n", jump(f, c.word(addr+1)))
+			return
+		case CJMP:
+			fmt.Fprintf(w, "if c, err := m.Pop(); err != nil {\n%s\n} else if c != 0 {\n%s\n} else {\n%s\n}\n",
+				fail(addr), jump(f, c.word(addr+1)), jump(f, next))
+			return
+		case CALL:
+			fmt.Fprintf(w, "if err := m.PushReturn(%d); err != nil {\n%s\n}\n%s\n",
+				next, fail(addr), jump(f, c.word(addr+1)))
+			return
+		case EXCALL:
+			// Extensions may switch tasks so the scheduler has to run.
+			fmt.Fprintf(w, "if err := m.ExtensionCall(); err != nil {\n%s\n}\nreturn %d, false, nil\n",
+				fail(addr), next)
+			return
+		case CONST:
+			fmt.Fprintf(w, "if err := m.Push(%d); err != nil {\n%s\n}\n", c.word(addr+1), fail(addr))
+		case KEY:
+			fmt.Fprintf(w, "if eof, err := m.Key(); err != nil {\n%s\n} else if eof {\nreturn %d, true, nil\n}\n",
+				fail(addr), addr)
+		default:
+			fmt.Fprintf(w, "if err := m.%s(); err != nil {\n%s\n}\n", machineMethods[opcode], fail(addr))
+		}
+
+		addr = next
+		if _, ok := c.blockAddr[addr]; ok {
+			fmt.Fprintf(w, "%s\n", jump(f, addr))
+			return
+		}
+	}
+}
+
+func (c *goCompiler) writeFunc(w *bytes.Buffer, f *compiledFunc) {
+	fmt.Fprintf(w, "\n// %s is compiled from '%s'.\n", f.name, f.label)
+	fmt.Fprintf(w, "func %s(m *diatom.Machine, pc diatom.Word) (diatom.Word, bool, error) {\n", f.name)
+	fmt.Fprintf(w, "for {\nswitch pc {\n")
+	for _, addr := range f.blocks {
+		fmt.Fprintf(w, "case %d:", addr)
+		if labels := c.labels[addr]; len(labels) > 0 {
+			fmt.Fprintf(w, " // %s", labels[0])
+		}
+		fmt.Fprintln(w)
+		c.writeBlock(w, f, addr)
+	}
+	fmt.Fprintf(w, "default:\nreturn pc, false, nil\n}\n}\n}\n")
+}
+
+// CompileGo translates the machine code of a memory image into Go
+// source that declares a CompiledProgram for VM.ExecuteCompiled. Each
+// codeword, as given by the labels in symbols or by the dictionary if
+// symbols is nil, becomes a function whose basic blocks are the cases
+// of a switch over the program counter. Jumps between blocks of the
+// same function stay in Go while all other control transfers, e.g.
+// calls and returns, go through ExecuteCompiled. Code that is only
+// reached by computed jumps is left to the interpreter.
+func CompileGo(image []byte, symbols Symbols, w io.Writer, opts CompilerOptions) error {
+	opts = opts.withDefaults()
+	if !isGoIdentifier(opts.Package) || !isGoIdentifier(opts.Name) {
+		return fmt.Errorf("package %q and name %q must be Go identifiers", opts.Package, opts.Name)
+	}
+
+	end := len(image)
+	for end > 0 && image[end-1] == 0 {
+		end--
+	}
+
+	d := &disassembler{
+		memory:  image[:end],
+		headers: map[Word]DictionaryEntry{},
+	}
+	for _, entry := range Dictionary(d.memory) {
+		d.headers[entry.Header] = entry
+	}
+	d.decode()
+	if symbols == nil {
+		symbols = dictionarySymbols(d.memory)
+	}
+
+	c := &goCompiler{
+		disassembler: d,
+		opts:         opts,
+		code:         map[Word]disasmItem{},
+		blockAddr:    map[Word]struct{}{},
+		labels:       map[Word][]string{},
+	}
+	for _, item := range d.items {
+		if item.kind == disasmInstruction {
+			c.code[item.addr] = item
+		}
+	}
+	c.findBlocks(symbols)
+	c.findFuncs(symbols)
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by diatom compile; DO NOT EDIT.\n\n")
+	fmt.Fprintf(out, "package %s\n\n", opts.Package)
+	fmt.Fprintf(out, "import \"github.com/eldelto/core/internal/diatom/v2\"\n\n")
+
+	fmt.Fprintf(out, "// %s holds the compiled code of a %d byte memory image.\n", opts.Name, end)
+	fmt.Fprintf(out, "var %s = diatom.CompiledProgram{\nImage: []byte{", opts.Name)
+	for i, b := range d.memory {
+		if i%16 == 0 {
+			fmt.Fprintln(out)
+		}
+		fmt.Fprintf(out, "%d, ", b)
+	}
+	fmt.Fprintf(out, "\n},\nBlocks: map[diatom.Word]diatom.CompiledFunc{\n")
+	for _, f := range c.funcs {
+		for _, addr := range f.blocks {
+			fmt.Fprintf(out, "%d: %s,\n", addr, f.name)
+		}
+	}
+	fmt.Fprintf(out, "},\n}\n")
+
+	for _, f := range c.funcs {
+		c.writeFunc(out, f)
+	}
+
+	source, err := format.Source(out.Bytes())
+	if err != nil {
+		return fmt.Errorf("failed to format generated Go code: %w", err)
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+func isGoIdentifier(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return s != ""
+}
@@ -0,0 +1,71 @@
+package diatom
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+func TestCompileGo(t *testing.T) {
+	program, err := AssembleProgram("test.dasm", strings.NewReader(`
+const 0 const 5
+:loop
+  dup cjmp @body
+  drop exit
+:body
+  swap call @add-ten swap const 1 - jmp @loop
+:add-ten
+  const 10 + ret`))
+	AssertNoError(t, err, "AssembleProgram")
+
+	out := &bytes.Buffer{}
+	err = CompileGo(program.Dopc, program.Labels, out, CompilerOptions{Package: "test"})
+	AssertNoError(t, err, "CompileGo")
+
+	source := out.String()
+	_, err = parser.ParseFile(token.NewFileSet(), "test.go", source, 0)
+	AssertNoError(t, err, "parser.ParseFile")
+
+	for _, want := range []string{
+		"package test\n",
+		"var Program = diatom.CompiledProgram{",
+		"func program_start(",
+		"func program_loop(",
+		"func program_body(",
+		"func program_add_ten(",
+		"24: program_body,",
+		"case 18: // body",
+		"return 36, false, nil",
+	} {
+		AssertStringContains(t, want, source, "generated source")
+	}
+
+	err = CompileGo(program.Dopc, program.Labels, out, CompilerOptions{Name: "no-name"})
+	AssertError(t, err, "CompileGo with invalid name")
+}
+
+func TestExecuteCompiled(t *testing.T) {
+	// const 3 const 4 + exit
+	image := []byte{CONST, 0, 0, 0, 3, CONST, 0, 0, 0, 4, ADD, EXIT}
+	calls := 0
+	compiled := CompiledProgram{
+		Image: image,
+		Blocks: map[Word]CompiledFunc{
+			// Only the addition is compiled, the rest is interpreted.
+			10: func(m *Machine, pc Word) (Word, bool, error) {
+				calls++
+				return 11, false, m.Add()
+			},
+		},
+	}
+
+	vm, err := NewDefaultVM(image)
+	AssertNoError(t, err, "NewDefaultVM")
+	AssertNoError(t, vm.ExecuteCompiled(compiled), "vm.ExecuteCompiled")
+	AssertEquals(t, []Word{7}, vm.DataStack(), "vm.DataStack")
+	AssertEquals(t, 1, calls, "compiled calls")
+}
@@ -0,0 +1,212 @@
+package diatom
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+var ErrProgramFinished = errors.New("program has finished")
+
+// Symbols maps label names to their addresses as produced by Labels.
+type Symbols map[string]Word
+
+// Address resolves the given location that is either a label name,
+// optionally prefixed with '@', or a numeric address.
+func (s Symbols) Address(location string) (Word, error) {
+	if addr, ok := s[strings.TrimPrefix(location, "@")]; ok {
+		return addr, nil
+	}
+
+	addr, err := strconv.ParseInt(location, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%q is neither a known label nor an address", location)
+	}
+
+	return Word(addr), nil
+}
+
+// preferLabel reports if label should be shown instead of other for
+// the same address. User defined labels win over generated ones
+// (e.g. '_dict-').
+func preferLabel(label, other string) bool {
+	generated := strings.HasPrefix(label, "_")
+	otherGenerated := strings.HasPrefix(other, "_")
+	if generated != otherGenerated {
+		return otherGenerated
+	}
+
+	return label < other
+}
+
+// closest returns the closest label at or before addr together with
+// its address.
+func (s Symbols) closest(addr Word) (string, Word, bool) {
+	name := ""
+	closest := Word(-1)
+	for label, labelAddr := range s {
+		if labelAddr > addr || labelAddr < closest {
+			continue
+		}
+
+		if labelAddr == closest && !preferLabel(label, name) {
+			continue
+		}
+
+		name = label
+		closest = labelAddr
+	}
+
+	return name, closest, name != ""
+}
+
+// Resolve returns the name of the closest label at or before addr
+// with the distance to it, e.g. 'word.read+3'. If there is no such
+// label only the address is returned.
+func (s Symbols) Resolve(addr Word) string {
+	name, closest, ok := s.closest(addr)
+
+	switch {
+	case !ok:
+		return strconv.Itoa(int(addr))
+	case closest == addr:
+		return name
+	default:
+		return fmt.Sprintf("%s+%d", name, addr-closest)
+	}
+}
+
+// Debugger executes the program of a VM instruction by instruction
+// and stops at breakpoints.
+type Debugger struct {
+	vm          *VM
+	symbols     Symbols
+	breakpoints map[Word]struct{}
+	done        bool
+}
+
+func NewDebugger(vm *VM, symbols Symbols) *Debugger {
+	if symbols == nil {
+		symbols = Symbols{}
+	}
+
+	return &Debugger{
+		vm:          vm,
+		symbols:     symbols,
+		breakpoints: map[Word]struct{}{},
+	}
+}
+
+func (d *Debugger) VM() *VM {
+	return d.vm
+}
+
+func (d *Debugger) Symbols() Symbols {
+	return d.symbols
+}
+
+// Done reports if the program has finished.
+func (d *Debugger) Done() bool {
+	return d.done
+}
+
+func (d *Debugger) AddBreakpoint(addr Word) {
+	d.breakpoints[addr] = struct{}{}
+}
+
+// RemoveBreakpoint reports if there was a breakpoint at addr.
+func (d *Debugger) RemoveBreakpoint(addr Word) bool {
+	_, ok := d.breakpoints[addr]
+	delete(d.breakpoints, addr)
+	return ok
+}
+
+// Breakpoints returns the addresses of all breakpoints in ascending
+// order.
+func (d *Debugger) Breakpoints() []Word {
+	return slices.Sorted(maps.Keys(d.breakpoints))
+}
+
+func (d *Debugger) atBreakpoint() bool {
+	_, ok := d.breakpoints[d.vm.programCounter]
+	return ok
+}
+
+// Step executes a single instruction.
+func (d *Debugger) Step() error {
+	if d.done {
+		return ErrProgramFinished
+	}
+
+	done, err := d.vm.step()
+	d.done = done
+	return err
+}
+
+// StepOver behaves like Step but executes called words as a whole
+// unless a breakpoint is hit within them.
+func (d *Debugger) StepOver() error {
+	if d.done {
+		return ErrProgramFinished
+	}
+
+	pc := d.vm.programCounter
+	if pc < 0 || pc >= Word(len(d.vm.memory)) || d.vm.memory[pc] != CALL {
+		return d.Step()
+	}
+
+	returnAddr := pc + 1 + WordSize
+	depth := d.vm.returnStack.cursor
+	for {
+		if err := d.Step(); err != nil {
+			return err
+		}
+
+		if d.done || d.atBreakpoint() ||
+			(d.vm.programCounter == returnAddr && d.vm.returnStack.cursor == depth) {
+			return nil
+		}
+	}
+}
+
+// Continue executes instructions until a breakpoint is hit or the
+// program finishes.
+func (d *Debugger) Continue() error {
+	for {
+		if err := d.Step(); err != nil {
+			return err
+		}
+
+		if d.done || d.atBreakpoint() {
+			return nil
+		}
+	}
+}
+
+// Instruction returns the disassembled instruction at addr together
+// with its size in bytes. Jump targets are resolved to labels.
+func (d *Debugger) Instruction(addr Word) (string, Word, error) {
+	opcode, err := d.vm.fetchByte(addr)
+	if err != nil {
+		return "", 0, err
+	}
+
+	name := instructionFromOpcode(opcode)
+	if !hasOperand(opcode) {
+		return name, 1, nil
+	}
+
+	operand, err := d.vm.fetchWord(addr + 1)
+	if err != nil {
+		return "", 0, err
+	}
+
+	if opcode == CONST {
+		return fmt.Sprintf("%s %d", name, operand), 1 + WordSize, nil
+	}
+
+	return fmt.Sprintf("%s @%s", name, d.symbols.Resolve(operand)), 1 + WordSize, nil
+}
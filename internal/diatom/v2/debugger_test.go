@@ -0,0 +1,81 @@
+package diatom_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/eldelto/core/internal/diatom/v2"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+const debugProgram = `
+const 3 call @square
+const 4 call @square
++ exit
+:square dup * ret`
+
+func newDebugger(t *testing.T) *diatom.Debugger {
+	dexp, _, program, err := diatom.Assemble(bytes.NewBufferString(debugProgram))
+	AssertNoError(t, err, "Assemble")
+
+	labels, err := diatom.Labels(bytes.NewBufferString(dexp))
+	AssertNoError(t, err, "Labels")
+
+	vm, err := diatom.NewVM(program, &bytes.Buffer{}, &bytes.Buffer{})
+	AssertNoError(t, err, "NewVM")
+
+	return diatom.NewDebugger(vm, labels)
+}
+
+func TestSymbols(t *testing.T) {
+	symbols := diatom.Symbols{"_dict-square": 10, "square": 10, "main": 0}
+
+	AssertEquals(t, "square", symbols.Resolve(10), "exact address")
+	AssertEquals(t, "square+2", symbols.Resolve(12), "address with offset")
+	AssertEquals(t, "main+4", symbols.Resolve(4), "address in first label")
+
+	addr, err := symbols.Address("@square")
+	AssertNoError(t, err, "symbols.Address")
+	AssertEquals(t, diatom.Word(10), addr, "label address")
+
+	addr, err = symbols.Address("42")
+	AssertNoError(t, err, "symbols.Address")
+	AssertEquals(t, diatom.Word(42), addr, "numeric address")
+
+	_, err = symbols.Address("unknown")
+	AssertError(t, err, "symbols.Address")
+}
+
+func TestDebugger(t *testing.T) {
+	d := newDebugger(t)
+	vm := d.VM()
+
+	instruction, size, err := d.Instruction(5)
+	AssertNoError(t, err, "d.Instruction")
+	AssertEquals(t, "call @square", instruction, "instruction")
+	AssertEquals(t, diatom.Word(5), size, "instruction size")
+
+	AssertNoError(t, d.Step(), "d.Step")
+	AssertEquals(t, []diatom.Word{3}, vm.DataStack(), "data stack after step")
+
+	AssertNoError(t, d.StepOver(), "d.StepOver")
+	AssertEquals(t, diatom.Word(10), vm.ProgramCounter(), "program counter after step over")
+	AssertEquals(t, []diatom.Word{9}, vm.DataStack(), "data stack after step over")
+
+	square, err := d.Symbols().Address("square")
+	AssertNoError(t, err, "Address")
+	d.AddBreakpoint(square)
+
+	AssertNoError(t, d.Continue(), "d.Continue")
+	AssertEquals(t, square, vm.ProgramCounter(), "program counter at breakpoint")
+	AssertEquals(t, []diatom.Word{9, 4}, vm.DataStack(), "data stack at breakpoint")
+	AssertEquals(t, []diatom.Word{20}, vm.ReturnStack(), "return stack at breakpoint")
+
+	AssertEquals(t, true, d.RemoveBreakpoint(square), "removed breakpoint")
+	AssertNoError(t, d.Continue(), "d.Continue")
+	AssertEquals(t, true, d.Done(), "program finished")
+	AssertEquals(t, []diatom.Word{25}, vm.DataStack(), "final data stack")
+
+	AssertError(t, d.Step(), "d.Step after exit")
+}
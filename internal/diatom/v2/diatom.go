@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"strconv"
+	"strings"
 )
 
 type Word int32
@@ -20,10 +22,27 @@ const (
 )
 
 //go:embed preamble.dasm
-var Preamble string
+var preambleTemplate string
+
+// Preamble is the preamble configured for the default VM options.
+var Preamble = PreambleWithOptions(VMOptions{})
+
+// PreambleWithOptions returns the preamble with its memory and stack
+// size words matching the given VM options.
+func PreambleWithOptions(opts VMOptions) string {
+	opts = opts.withDefaults()
+	return strings.NewReplacer(
+		"{{memory-size}}", strconv.Itoa(opts.MemorySize),
+		"{{data-stack-size}}", strconv.Itoa(opts.DataStackSize),
+		"{{return-stack-size}}", strconv.Itoa(opts.ReturnStackSize),
+	).Replace(preambleTemplate)
+}
 
-// //go:embed stdlib.dia
-// var Stdlib string
+// Stdlib is Forth source with common words built on top of the
+// interpreter in the preamble.
+//
+//go:embed stdlib.dia
+var Stdlib string
 
 // //go:embed repl.dopc
 //var ReplDopc []byte
@@ -38,6 +57,17 @@ func wordToBytes(w Word) [WordSize]byte {
 	return bytes
 }
 
+// bytesToWord is the inverse of the big-endian layout used for words
+// in memory.
+func bytesToWord(b []byte) Word {
+	var w Word
+	for i := range WordSize {
+		w = w<<8 | Word(b[i])
+	}
+
+	return w
+}
+
 func writeAsBytes(w io.Writer, value Word) error {
 	bytes := wordToBytes(value)
 
@@ -94,6 +124,7 @@ const (
 	KEY
 	EMIT
 	DUMP
+	DEPTH
 )
 
 var instructions map[string]byte = map[string]byte{
@@ -132,9 +163,10 @@ var instructions map[string]byte = map[string]byte{
 	"<": LT,
 	">": GT,
 
-	"key":  KEY,
-	"emit": EMIT,
-	"dump": DUMP,
+	"key":   KEY,
+	"emit":  EMIT,
+	"dump":  DUMP,
+	"depth": DEPTH,
 }
 
 // TODO: Make this more efficient.
@@ -148,17 +180,33 @@ func instructionFromOpcode(b byte) string {
 	return "UNKNOWN"
 }
 
-// func WithStdlib(program string) (*VM, error) {
-// 	main := ".codeword main !interpret .end"
-// 	repl := strings.Replace(Preamble, MainTemplate, main, 1)
-// 	_, _, dopc, err := Assemble(bytes.NewBufferString(repl))
-// 	if err != nil {
-// 		return nil, err
-// 	}
+// hasOperand reports if the instruction is followed by a word in
+// memory that it consumes.
+func hasOperand(opcode byte) bool {
+	switch opcode {
+	case JMP, CJMP, CALL, CONST:
+		return true
+	default:
+		return false
+	}
+}
+
+// WithStdlib returns a VM running the Forth interpreter of the
+// preamble that evaluates the standard library, the given program and
+// then the input.
+func WithStdlib(program string, input io.Reader, output io.Writer) (*VM, error) {
+	repl, err := AssembleRepl(VMOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("assemble REPL: %w", err)
+	}
 
-// 	input := io.MultiReader(bytes.NewBufferString(Stdlib+" "),
-// 		bytes.NewBufferString(program+" "),
-// 		os.Stdin)
+	source := io.MultiReader(strings.NewReader(Stdlib+"\n"),
+		strings.NewReader(program+"\n"), input)
+	vm, err := NewVM(repl.Dopc, source, output)
+	if err != nil {
+		return nil, err
+	}
+	vm.SetSourceMap(repl.SourceMap)
 
-// 	return NewVM(dopc, input, os.Stdout)
-// }
+	return vm, nil
+}
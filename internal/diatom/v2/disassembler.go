@@ -0,0 +1,342 @@
+package diatom
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// dictionaryHeaderSize is the size of a dictionary header without
+// the name: pointer to the previous word, flags, length and capacity.
+const dictionaryHeaderSize = WordSize + 3
+
+// Flags of a dictionary entry.
+const (
+	FlagHidden    = 1 << 0
+	FlagImmediate = 1 << 1
+)
+
+// DictionaryEntry is a word found in the dictionary of a memory
+// image.
+type DictionaryEntry struct {
+	Header   Word
+	Previous Word
+	Flags    byte
+	Name     string
+	Code     Word
+}
+
+// Dictionary reconstructs the linked list of dictionary entries in
+// memory, starting with the latest word. Headers are recognized by
+// their layout and only the longest chain of headers linked to each
+// other is returned.
+func Dictionary(memory []byte) []DictionaryEntry {
+	type header struct {
+		DictionaryEntry
+		chain int
+	}
+
+	headers := map[Word]header{}
+	var latest Word = -1
+	for addr := 0; addr+dictionaryHeaderSize < len(memory); addr++ {
+		flags := memory[addr+WordSize]
+		length := int(memory[addr+WordSize+1])
+		capacity := int(memory[addr+WordSize+2])
+		nameStart := addr + dictionaryHeaderSize
+		if flags > FlagHidden|FlagImmediate || length == 0 || length != capacity ||
+			length > maxTokenLen || nameStart+length > len(memory) {
+			continue
+		}
+
+		name := memory[nameStart : nameStart+length]
+		if slices.ContainsFunc(name, func(b byte) bool { return b < 33 || b > 126 }) {
+			continue
+		}
+
+		prev := bytesToWord(memory[addr : addr+WordSize])
+		chain := 1
+		if prev != 0 {
+			prevHeader, ok := headers[prev]
+			if !ok {
+				continue
+			}
+			chain = prevHeader.chain + 1
+		}
+
+		headers[Word(addr)] = header{
+			DictionaryEntry: DictionaryEntry{
+				Header:   Word(addr),
+				Previous: prev,
+				Flags:    flags,
+				Name:     string(name),
+				Code:     Word(nameStart + length),
+			},
+			chain: chain,
+		}
+		if latest < 0 || chain > headers[latest].chain {
+			latest = Word(addr)
+		}
+	}
+
+	entries := []DictionaryEntry{}
+	for addr := latest; addr > 0; addr = headers[addr].Previous {
+		entries = append(entries, headers[addr].DictionaryEntry)
+	}
+
+	return entries
+}
+
+type disasmKind int
+
+const (
+	disasmInstruction disasmKind = iota
+	disasmHeader
+	disasmZeros
+	disasmString
+	disasmData
+)
+
+type disasmItem struct {
+	kind  disasmKind
+	addr  Word
+	size  Word
+	entry DictionaryEntry
+}
+
+type disassembler struct {
+	memory  []byte
+	headers map[Word]DictionaryEntry
+	items   []disasmItem
+	starts  map[Word]struct{}
+	labels  map[Word]string
+	names   map[string]struct{}
+}
+
+func (d *disassembler) headerWithin(from, to Word) bool {
+	for addr := from; addr < to; addr++ {
+		if _, ok := d.headers[addr]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *disassembler) word(addr Word) Word {
+	return bytesToWord(d.memory[addr : addr+WordSize])
+}
+
+// decode splits the memory into instructions and data by sweeping
+// over it linearly.
+func (d *disassembler) decode() {
+	end := Word(len(d.memory))
+	for addr := Word(0); addr < end; {
+		item := disasmItem{addr: addr, size: 1}
+		opcode := d.memory[addr]
+
+		if entry, ok := d.headers[addr]; ok {
+			item.kind = disasmHeader
+			item.entry = entry
+			item.size = entry.Code - addr
+		} else if opcode == ABORT {
+			item.kind = disasmZeros
+			for addr+item.size < end && d.memory[addr+item.size] == 0 &&
+				!d.headerWithin(addr+item.size, addr+item.size+1) {
+				item.size++
+			}
+		} else if opcode > DEPTH {
+			item.kind = disasmData
+		} else if hasOperand(opcode) {
+			item.size = 1 + WordSize
+			if addr+item.size > end || d.headerWithin(addr+1, addr+item.size) {
+				item.kind = disasmData
+				item.size = 1
+			}
+		}
+
+		d.items = append(d.items, item)
+		addr += item.size
+
+		// Strings are embedded in the code behind a jump over them.
+		if item.kind == disasmInstruction && opcode == JMP && item.size > 1 {
+			target := d.word(item.addr + 1)
+			if addr+2 <= end && target <= end {
+				length := d.memory[addr]
+				if length > 0 && length == d.memory[addr+1] && addr+2+Word(length) == target &&
+					!d.headerWithin(addr, target) {
+					d.items = append(d.items, disasmItem{kind: disasmString, addr: addr, size: target - addr})
+					addr = target
+				}
+			}
+		}
+	}
+}
+
+func (d *disassembler) addLabel(addr Word, name string) {
+	if _, ok := d.labels[addr]; ok {
+		return
+	}
+	if _, ok := d.names[name]; ok {
+		name += "-" + strconv.Itoa(int(addr))
+	}
+
+	d.labels[addr] = name
+	d.names[name] = struct{}{}
+}
+
+// assignLabels names the dictionary entries and all jump and call
+// targets that start an instruction or data item.
+func (d *disassembler) assignLabels() {
+	for _, item := range d.items {
+		d.starts[item.addr] = struct{}{}
+	}
+
+	for _, item := range d.items {
+		if item.kind == disasmHeader {
+			d.addLabel(item.addr, "_dict-"+item.entry.Name)
+			d.addLabel(item.entry.Code, item.entry.Name)
+		}
+	}
+
+	for _, item := range d.items {
+		if item.kind != disasmInstruction || item.size == 1 {
+			continue
+		}
+		switch d.memory[item.addr] {
+		case JMP, CJMP, CALL:
+			target := d.word(item.addr + 1)
+			if _, ok := d.starts[target]; ok {
+				d.addLabel(target, "addr-"+strconv.Itoa(int(target)))
+			}
+		}
+	}
+}
+
+func bytesString(b []byte) string {
+	s := make([]string, len(b))
+	for i, x := range b {
+		s[i] = strconv.Itoa(int(x))
+	}
+	return strings.Join(s, " ")
+}
+
+// quote returns s as quoted string that can't end a comment.
+func quote(s string) string {
+	return strings.NewReplacer("(", `\x28`, ")", `\x29`).Replace(strconv.Quote(s))
+}
+
+func (d *disassembler) writeItem(w io.Writer, item disasmItem) error {
+	bytes := d.memory[item.addr : item.addr+item.size]
+
+	var err error
+	switch item.kind {
+	case disasmInstruction:
+		opcode := bytes[0]
+		name := instructionFromOpcode(opcode)
+		switch {
+		case item.size == 1:
+			_, err = fmt.Fprintf(w, "( %d ) %s\n", item.addr, name)
+		case opcode == CONST:
+			value := d.word(item.addr + 1)
+			comment := strconv.Itoa(int(value))
+			// Small values are more likely characters or counts than
+			// addresses.
+			if label, ok := d.labels[value]; ok && value > 255 {
+				comment += " @" + label
+			}
+			_, err = fmt.Fprintf(w, "( %d ) %s %s ( %s )\n", item.addr, name, bytesString(bytes[1:]), comment)
+		default:
+			target := d.word(item.addr + 1)
+			if label, ok := d.labels[target]; ok {
+				_, err = fmt.Fprintf(w, "( %d ) %s @%s\n", item.addr, name, label)
+			} else {
+				_, err = fmt.Fprintf(w, "( %d ) %s %s ( -> %d )\n",
+					item.addr, name, bytesString(bytes[1:]), target)
+			}
+		}
+	case disasmHeader:
+		entry := item.entry
+		prev := bytesString(bytes[:WordSize])
+		if label, ok := d.labels[entry.Previous]; ok && entry.Previous != 0 {
+			prev = "@" + label
+		}
+
+		flags := []string{}
+		if entry.Flags&FlagHidden != 0 {
+			flags = append(flags, "hidden")
+		}
+		if entry.Flags&FlagImmediate != 0 {
+			flags = append(flags, "immediate")
+		}
+		flagComment := ""
+		if len(flags) > 0 {
+			flagComment = " ( " + strings.Join(flags, " ") + " )"
+		}
+
+		_, err = fmt.Fprintf(w, "( %d ) %s ( dictionary entry %s )\n%d%s\n%s ( %s )\n",
+			item.addr, prev, quote(entry.Name), entry.Flags, flagComment,
+			bytesString(bytes[WordSize+1:]), quote(entry.Name))
+	case disasmZeros:
+		const perLine = 16
+		for i := 0; i < len(bytes) && err == nil; i += perLine {
+			line := bytes[i:min(i+perLine, len(bytes))]
+			_, err = fmt.Fprintf(w, "( %d ) %s\n", item.addr+Word(i), bytesString(line))
+		}
+	case disasmString:
+		_, err = fmt.Fprintf(w, "( %d ) %s ( string %s )\n",
+			item.addr, bytesString(bytes), quote(string(bytes[2:])))
+	case disasmData:
+		_, err = fmt.Fprintf(w, "( %d ) %d ( data )\n", item.addr, bytes[0])
+	}
+
+	return err
+}
+
+// Disassemble writes the machine code of a memory image as annotated
+// assembly. Dictionary headers and strings are recognized as data and
+// jump and call targets get labels. The output uses the format of the
+// macro expanded assembly (.dexp) so that it can be assembled again
+// with ResolveLabels and GenerateMachineCode. Trailing zero bytes are
+// omitted.
+func Disassemble(image []byte, w io.Writer) error {
+	end := len(image)
+	for end > 0 && image[end-1] == 0 {
+		end--
+	}
+
+	d := &disassembler{
+		memory:  image[:end],
+		headers: map[Word]DictionaryEntry{},
+		starts:  map[Word]struct{}{},
+		labels:  map[Word]string{},
+		names:   map[string]struct{}{},
+	}
+	for _, entry := range Dictionary(d.memory) {
+		d.headers[entry.Header] = entry
+	}
+
+	d.decode()
+	d.assignLabels()
+
+	bw := bufio.NewWriter(w)
+	for _, item := range d.items {
+		if label, ok := d.labels[item.addr]; ok {
+			if _, err := fmt.Fprintf(bw, ":%s\n", label); err != nil {
+				return err
+			}
+		}
+		if err := d.writeItem(bw, item); err != nil {
+			return err
+		}
+	}
+
+	if omitted := len(image) - end; omitted > 0 {
+		if _, err := fmt.Fprintf(bw, "( %d trailing zero bytes omitted )\n", omitted); err != nil {
+			return err
+		}
+	}
+
+	return bw.Flush()
+}
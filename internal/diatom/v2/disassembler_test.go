@@ -0,0 +1,72 @@
+package diatom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+func TestDisassemble(t *testing.T) {
+	repl, err := NewRepl(&bytes.Buffer{})
+	AssertNoError(t, err, "NewRepl")
+	AssertNoError(t, repl.Eval(": quad dup + dup + ;"), "repl.Eval")
+
+	image, err := repl.Image()
+	AssertNoError(t, err, "repl.Image")
+
+	disassembly := &bytes.Buffer{}
+	AssertNoError(t, Disassemble(image, disassembly), "Disassemble")
+
+	dins := &bytes.Buffer{}
+	AssertNoError(t, ResolveLabels(bytes.NewReader(disassembly.Bytes()), dins), "ResolveLabels")
+	dopc := &bytes.Buffer{}
+	AssertNoError(t, GenerateMachineCode(dins, dopc), "GenerateMachineCode")
+
+	reassembled := dopc.Bytes()
+	AssertEquals(t, image[:len(reassembled)], reassembled, "reassembled image")
+	AssertEquals(t, true, !bytes.ContainsFunc(image[len(reassembled):], func(r rune) bool { return r != 0 }),
+		"only trailing zeros omitted")
+
+	text := disassembly.String()
+	AssertStringContains(t, ":quad\n", text, "codeword label")
+	AssertStringContains(t, `( dictionary entry "quad" )`, text, "dictionary header")
+	AssertStringContains(t, "call @dup", text, "resolved call")
+}
+
+func TestDisassembleString(t *testing.T) {
+	_, _, dopc, err := Assemble(bytes.NewBufferString(":main .string a ( b ) .end exit"))
+	AssertNoError(t, err, "Assemble")
+
+	disassembly := &bytes.Buffer{}
+	AssertNoError(t, Disassemble(dopc, disassembly), "Disassemble")
+	AssertStringContains(t, `( string "a \x28 b \x29" )`, disassembly.String(), "escaped string")
+
+	dins := &bytes.Buffer{}
+	AssertNoError(t, ResolveLabels(disassembly, dins), "ResolveLabels")
+	reassembled := &bytes.Buffer{}
+	AssertNoError(t, GenerateMachineCode(dins, reassembled), "GenerateMachineCode")
+	AssertEquals(t, dopc, reassembled.Bytes(), "reassembled program")
+}
+
+func TestDictionary(t *testing.T) {
+	repl, err := NewRepl(&bytes.Buffer{})
+	AssertNoError(t, err, "NewRepl")
+	AssertNoError(t, repl.Eval(": quad dup + dup + ; word.immediate"), "repl.Eval")
+
+	entries := Dictionary(repl.VM().memory)
+	AssertEquals(t, true, len(entries) > 1, "entries found")
+
+	latest := entries[0]
+	AssertEquals(t, "quad", latest.Name, "latest entry")
+	AssertEquals(t, byte(FlagImmediate), latest.Flags, "flags of latest entry")
+	AssertEquals(t, entries[1].Header, latest.Previous, "link to previous entry")
+	AssertEquals(t, Word(0), entries[len(entries)-1].Previous, "end of the dictionary")
+
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name)
+	}
+	AssertStringContains(t, " dup ", " "+strings.Join(names, " ")+" ", "dup in dictionary")
+}
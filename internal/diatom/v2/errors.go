@@ -0,0 +1,153 @@
+package diatom
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// ErrorLocation is embedded by all errors of the VM and holds the
+// address of the instruction that failed. Errors can be told apart
+// with errors.As.
+type ErrorLocation struct {
+	ProgramCounter Word
+	location       string
+}
+
+func (l *ErrorLocation) where() string {
+	if l.location == "" {
+		return strconv.Itoa(int(l.ProgramCounter))
+	}
+	return l.location
+}
+
+func (l *ErrorLocation) setLocation(pc Word, location string) {
+	if l.location == "" {
+		l.ProgramCounter = pc
+		l.location = location
+	}
+}
+
+// StackOverflowError is returned when pushing onto a full stack.
+type StackOverflowError struct {
+	// Stack is either "data" or "return".
+	Stack string
+	Size  int
+	ErrorLocation
+}
+
+func (e *StackOverflowError) Error() string {
+	return fmt.Sprintf("%s stack overflow - stack size: %d, programCounter=%s",
+		e.Stack, e.Size, e.where())
+}
+
+// StackUnderflowError is returned when popping from or peeking at an
+// empty stack.
+type StackUnderflowError struct {
+	// Stack is either "data" or "return".
+	Stack string
+	ErrorLocation
+}
+
+func (e *StackUnderflowError) Error() string {
+	return fmt.Sprintf("%s stack underflow - programCounter=%s", e.Stack, e.where())
+}
+
+// MemoryAccessError is returned when an address outside of the VM's
+// memory is read or written.
+type MemoryAccessError struct {
+	Address Word
+	ErrorLocation
+}
+
+func (e *MemoryAccessError) Error() string {
+	return fmt.Sprintf("out of bound memory access: programCounter=%s address=%d",
+		e.where(), e.Address)
+}
+
+// AbortError is returned when the VM executes the ABORT instruction,
+// which usually means that the program ran into uninitialized memory.
+type AbortError struct {
+	ErrorLocation
+	// Trace holds the last executed instructions like VM.StackTrace.
+	Trace string
+}
+
+func (e *AbortError) Error() string {
+	return fmt.Sprintf("tried to execute uninitialized memory at address %s - aborting",
+		e.where())
+}
+
+// DivisionByZeroError is returned by DIV and MOD with a divisor of
+// zero.
+type DivisionByZeroError struct {
+	ErrorLocation
+}
+
+func (e *DivisionByZeroError) Error() string {
+	return fmt.Sprintf("division by zero at address %s", e.where())
+}
+
+// UnknownInstructionError is returned for opcodes that don't belong
+// to any instruction.
+type UnknownInstructionError struct {
+	Instruction byte
+	ErrorLocation
+}
+
+func (e *UnknownInstructionError) Error() string {
+	return fmt.Sprintf("unknown instruction '%d' at memory address %s - terminating",
+		e.Instruction, e.where())
+}
+
+// UnknownExtensionError is returned by EXCALL for addresses without a
+// registered extension function.
+type UnknownExtensionError struct {
+	Address Word
+	ErrorLocation
+}
+
+func (e *UnknownExtensionError) Error() string {
+	return fmt.Sprintf("extension function at address '%d' not found - programCounter=%s",
+		e.Address, e.where())
+}
+
+// ExtensionError wraps the error returned by an extension function.
+type ExtensionError struct {
+	Address Word
+	ErrorLocation
+	Err error
+}
+
+func (e *ExtensionError) Error() string {
+	return fmt.Sprintf("extension function at address '%d' failed - programCounter=%s: %v",
+		e.Address, e.where(), e.Err)
+}
+
+func (e *ExtensionError) Unwrap() error {
+	return e.Err
+}
+
+// DeadlockError is returned when all remaining tasks wait for a
+// message.
+type DeadlockError struct {
+	Tasks []Word
+	ErrorLocation
+}
+
+func (e *DeadlockError) Error() string {
+	return fmt.Sprintf("deadlock - all tasks %v wait for messages, programCounter=%s",
+		e.Tasks, e.where())
+}
+
+// locate sets the location of all errors of the VM in the chain of
+// err that have been created without knowing the program counter.
+func (vm *VM) locate(err error, pc Word) error {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if l, ok := e.(interface{ setLocation(Word, string) }); ok {
+			l.setLocation(pc, vm.sourceMap.Describe(pc))
+		}
+	}
+
+	return err
+}
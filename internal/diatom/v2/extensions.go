@@ -0,0 +1,422 @@
+package diatom
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"time"
+)
+
+// Module addresses of the standard extensions. The preamble wraps
+// their functions in words with the same names as documented below.
+const (
+	FileExtensionAddr   = 1
+	ClockExtensionAddr  = 2
+	RandomExtensionAddr = 3
+	FormatExtensionAddr = 4
+)
+
+// Results pushed by extension functions that can fail because of the
+// host environment.
+const (
+	iorSuccess Word = 0
+	iorFailure Word = -1
+)
+
+// Modes of file.open.
+const (
+	FileReadOnly Word = iota
+	FileWriteOnly
+	FileAppend
+)
+
+// popWords pops n words and returns them in the order they have been
+// pushed.
+func (vm *VM) popWords(n int) ([]Word, error) {
+	words := make([]Word, n)
+	for i := n - 1; i >= 0; i-- {
+		w, err := vm.dataStack.Pop()
+		if err != nil {
+			return nil, err
+		}
+		words[i] = w
+	}
+
+	return words, nil
+}
+
+func (vm *VM) pushWords(words ...Word) error {
+	for _, w := range words {
+		if err := vm.dataStack.Push(w); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// fetchArray returns the content of the array at addr which is laid
+// out like in the preamble: length, capacity and the bytes.
+func (vm *VM) fetchArray(addr Word) ([]byte, error) {
+	length, err := vm.fetchByte(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	return vm.Memory(addr+2, int(length))
+}
+
+func (vm *VM) arrayCapacity(addr Word) (int, error) {
+	capacity, err := vm.fetchByte(addr + 1)
+	return int(capacity), err
+}
+
+// storeArray replaces the content of the array at addr with data.
+func (vm *VM) storeArray(addr Word, data []byte) error {
+	capacity, err := vm.arrayCapacity(addr)
+	if err != nil {
+		return err
+	}
+	if len(data) > capacity {
+		return fmt.Errorf("%d bytes exceed the capacity of the array at address %d (%d bytes)",
+			len(data), addr, capacity)
+	}
+
+	for i, b := range data {
+		if err := vm.storeByte(addr+2+Word(i), b); err != nil {
+			return err
+		}
+	}
+
+	return vm.storeByte(addr, byte(len(data)))
+}
+
+// FileSystem gives programs access to the files of a single
+// directory. Paths pointing outside of it can't be opened.
+type FileSystem struct {
+	root  *os.Root
+	files map[Word]*os.File
+	next  Word
+}
+
+func OpenFileSystem(dir string) (*FileSystem, error) {
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file system at %q: %w", dir, err)
+	}
+
+	return &FileSystem{root: root, files: map[Word]*os.File{}, next: 1}, nil
+}
+
+// Close closes all files left open by the program and the directory
+// itself.
+func (fs *FileSystem) Close() error {
+	errs := []error{}
+	for fd, f := range fs.files {
+		errs = append(errs, f.Close())
+		delete(fs.files, fd)
+	}
+	errs = append(errs, fs.root.Close())
+
+	return errors.Join(errs...)
+}
+
+// Extension returns the functions
+//
+//	file.open  ( path mode -- fd ior )
+//	file.close ( fd -- ior )
+//	file.read  ( ptr fd -- ior )
+//	file.write ( ptr fd -- ior )
+//
+// where path and ptr are arrays and ior is 0 on success. file.read
+// fills the array up to its capacity and leaves it empty at the end
+// of the file.
+func (fs *FileSystem) Extension() Extension {
+	return Extension{
+		Addr:      FileExtensionAddr,
+		Functions: []ExtensionFunc{fs.open, fs.close, fs.read, fs.write},
+		Name:      "File",
+	}
+}
+
+func (fs *FileSystem) open(vm *VM) error {
+	args, err := vm.popWords(2)
+	if err != nil {
+		return err
+	}
+	path, err := vm.fetchArray(args[0])
+	if err != nil {
+		return err
+	}
+
+	var flag int
+	switch args[1] {
+	case FileReadOnly:
+		flag = os.O_RDONLY
+	case FileWriteOnly:
+		flag = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	case FileAppend:
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	default:
+		return fmt.Errorf("file.open: invalid mode %d", args[1])
+	}
+
+	f, err := fs.root.OpenFile(string(path), flag, 0664)
+	if err != nil {
+		return vm.pushWords(0, iorFailure)
+	}
+
+	fd := fs.next
+	fs.next++
+	fs.files[fd] = f
+
+	return vm.pushWords(fd, iorSuccess)
+}
+
+func (fs *FileSystem) close(vm *VM) error {
+	fd, err := vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+
+	f, ok := fs.files[fd]
+	if !ok {
+		return vm.pushWords(iorFailure)
+	}
+	delete(fs.files, fd)
+
+	if err := f.Close(); err != nil {
+		return vm.pushWords(iorFailure)
+	}
+	return vm.pushWords(iorSuccess)
+}
+
+func (fs *FileSystem) read(vm *VM) error {
+	args, err := vm.popWords(2)
+	if err != nil {
+		return err
+	}
+	ptr, fd := args[0], args[1]
+
+	f, ok := fs.files[fd]
+	if !ok {
+		return vm.pushWords(iorFailure)
+	}
+
+	capacity, err := vm.arrayCapacity(ptr)
+	if err != nil {
+		return err
+	}
+
+	buffer := make([]byte, capacity)
+	n, err := f.Read(buffer)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return vm.pushWords(iorFailure)
+	}
+
+	if err := vm.storeArray(ptr, buffer[:n]); err != nil {
+		return err
+	}
+	return vm.pushWords(iorSuccess)
+}
+
+func (fs *FileSystem) write(vm *VM) error {
+	args, err := vm.popWords(2)
+	if err != nil {
+		return err
+	}
+	ptr, fd := args[0], args[1]
+
+	f, ok := fs.files[fd]
+	if !ok {
+		return vm.pushWords(iorFailure)
+	}
+
+	data, err := vm.fetchArray(ptr)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		return vm.pushWords(iorFailure)
+	}
+	return vm.pushWords(iorSuccess)
+}
+
+// NewClockExtension returns the functions
+//
+//	clock.now    ( -- seconds )
+//	clock.millis ( -- ms )
+//	clock.sleep  ( ms -- )
+//
+// where clock.now returns the Unix time and clock.millis the
+// milliseconds passed since the extension has been created.
+func NewClockExtension() Extension {
+	start := time.Now()
+
+	return Extension{
+		Addr: ClockExtensionAddr,
+		Functions: []ExtensionFunc{
+			func(vm *VM) error {
+				return vm.dataStack.Push(Word(time.Now().Unix()))
+			},
+			func(vm *VM) error {
+				return vm.dataStack.Push(Word(time.Since(start).Milliseconds()))
+			},
+			func(vm *VM) error {
+				ms, err := vm.dataStack.Pop()
+				if err != nil {
+					return err
+				}
+				time.Sleep(time.Duration(ms) * time.Millisecond)
+				return nil
+			},
+		},
+		Name: "Clock",
+	}
+}
+
+// NewRandomExtension returns the functions
+//
+//	random.int   ( -- x )
+//	random.below ( n -- x )
+//	random.seed  ( seed -- )
+//
+// backed by a pseudo-random generator starting from seed.
+func NewRandomExtension(seed uint64) Extension {
+	source := rand.NewPCG(seed, seed)
+	r := rand.New(source)
+
+	return Extension{
+		Addr: RandomExtensionAddr,
+		Functions: []ExtensionFunc{
+			func(vm *VM) error {
+				return vm.dataStack.Push(Word(r.Uint32()))
+			},
+			func(vm *VM) error {
+				n, err := vm.dataStack.Pop()
+				if err != nil {
+					return err
+				}
+				if n <= 0 {
+					return fmt.Errorf("random.below: upper bound must be positive but is %d", n)
+				}
+				return vm.dataStack.Push(Word(r.Int32N(int32(n))))
+			},
+			func(vm *VM) error {
+				seed, err := vm.dataStack.Pop()
+				if err != nil {
+					return err
+				}
+				source.Seed(uint64(seed), uint64(seed))
+				return nil
+			},
+		},
+		Name: "Random",
+	}
+}
+
+// FormatExtension provides the functions
+//
+//	format.string ( args... fmt dest -- )
+//	format.print  ( args... fmt -- )
+//
+// that format the array fmt with the Go verbs %d, %x, %X, %o, %b, %c
+// and %s, where %s takes the address of an array. Each verb consumes
+// one argument from the stack, the last one on top.
+var FormatExtension = Extension{
+	Addr: FormatExtensionAddr,
+	Functions: []ExtensionFunc{
+		func(vm *VM) error {
+			dest, err := vm.dataStack.Pop()
+			if err != nil {
+				return err
+			}
+			s, err := formatFromStack(vm)
+			if err != nil {
+				return err
+			}
+			return vm.storeArray(dest, []byte(s))
+		},
+		func(vm *VM) error {
+			s, err := formatFromStack(vm)
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(vm.output, s); err != nil {
+				return fmt.Errorf("failed to write to the VM output: %w", err)
+			}
+			return nil
+		},
+	},
+	Name: "Format",
+}
+
+// formatVerbs returns the verbs of a format string after validating
+// that only supported verbs are used.
+func formatVerbs(format string) ([]byte, error) {
+	verbs := []byte{}
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		i++
+		for i < len(format) && strings.IndexByte("+-# 0123456789", format[i]) >= 0 {
+			i++
+		}
+		if i >= len(format) {
+			return nil, fmt.Errorf("format %q ends with an incomplete verb", format)
+		}
+
+		switch format[i] {
+		case '%':
+		case 'd', 'x', 'X', 'o', 'b', 'c', 's':
+			verbs = append(verbs, format[i])
+		default:
+			return nil, fmt.Errorf("format %q contains unsupported verb %%%c", format, format[i])
+		}
+	}
+
+	return verbs, nil
+}
+
+func formatFromStack(vm *VM) (string, error) {
+	ptr, err := vm.dataStack.Pop()
+	if err != nil {
+		return "", err
+	}
+	format, err := vm.fetchArray(ptr)
+	if err != nil {
+		return "", err
+	}
+
+	verbs, err := formatVerbs(string(format))
+	if err != nil {
+		return "", err
+	}
+
+	args, err := vm.popWords(len(verbs))
+	if err != nil {
+		return "", err
+	}
+
+	values := make([]any, len(args))
+	for i, verb := range verbs {
+		if verb != 's' {
+			values[i] = args[i]
+			continue
+		}
+
+		s, err := vm.fetchArray(args[i])
+		if err != nil {
+			return "", err
+		}
+		values[i] = string(s)
+	}
+
+	return fmt.Sprintf(string(format), values...), nil
+}
@@ -0,0 +1,133 @@
+package diatom
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+func TestStandardExtensions(t *testing.T) {
+	tests := []struct {
+		assembly      string
+		wantDataStack []Word
+		wantOutput    string
+		expectError   bool
+	}{
+		// File
+		{`.string hello.txt .end call @file.write-only call @file.open
+		  swap dup rpush .string hi .end swap call @file.write
+		  rpop call @file.close`,
+			[]Word{0, 0, 0}, "", false},
+		{`.string data.txt .end call @file.read-only call @file.open drop rpush
+		  call @test.buffer const 16 call @array.init rpeek call @file.read
+		  call @test.buffer call @array.length
+		  call @test.buffer rpop call @file.read
+		  call @test.buffer call @array.length`,
+			[]Word{0, 3, 0, 0}, "", false},
+		{".string ../data.txt .end call @file.read-only call @file.open", []Word{0, -1}, "", false},
+		{".string missing.txt .end call @file.read-only call @file.open", []Word{0, -1}, "", false},
+		{"const 7 call @file.close", []Word{-1}, "", false},
+		{".string data.txt .end const 7 call @file.open", []Word{}, "", true},
+
+		// Clock
+		{"call @clock.now const 0 >", []Word{-1}, "", false},
+		{"call @clock.millis const 0 call @clock.sleep call @clock.millis swap - const -1 >", []Word{-1}, "", false},
+
+		// Random
+		{`const 42 call @random.seed const 1000 call @random.below
+		  const 42 call @random.seed const 1000 call @random.below =`,
+			[]Word{-1}, "", false},
+		{"const 3 call @random.below dup const -1 > swap const 3 < &", []Word{-1}, "", false},
+		{"const 0 call @random.below", []Word{}, "", true},
+
+		// Format
+		{"const 42 .string hi .end .string %d %s! .end call @format.print", []Word{}, "42 hi!", false},
+		{`const 7 .string <%03d|%x> .end dup rpush drop const 255 rpop
+		  call @test.buffer const 16 call @array.init call @format.string
+		  call @test.buffer call @string.print`,
+			[]Word{}, "<007|ff>", false},
+		{"const 1 .string %d%% .end call @format.print", []Word{}, "1%", false},
+		{".string %f .end call @format.print", []Word{}, "", true},
+		{`const 1000000 .string %d .end
+		  call @test.buffer const 4 call @array.init call @format.string`,
+			[]Word{}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.assembly, func(t *testing.T) {
+			dir := t.TempDir()
+			sandbox := filepath.Join(dir, "sandbox")
+			AssertNoError(t, os.Mkdir(sandbox, 0755), "create sandbox")
+			AssertNoError(t, os.WriteFile(filepath.Join(sandbox, "data.txt"), []byte("abc"), 0644),
+				"write data.txt")
+			AssertNoError(t, os.WriteFile(filepath.Join(dir, "data.txt"), []byte("secret"), 0644),
+				"write file outside of sandbox")
+
+			assembly := strings.Replace(Preamble, "( {{main}} )",
+				"\n:main\n"+tt.assembly+"\nexit\n.var test.buffer 18 .end", 1)
+			_, _, program, err := Assemble(bytes.NewBufferString(assembly))
+			AssertNoError(t, err, "Assemble")
+
+			output := &bytes.Buffer{}
+			vm, err := NewVM(program, &bytes.Buffer{}, output)
+			AssertNoError(t, err, "NewVM")
+
+			fs, err := OpenFileSystem(sandbox)
+			AssertNoError(t, err, "OpenFileSystem")
+			defer fs.Close()
+
+			for _, ext := range []Extension{
+				fs.Extension(),
+				NewClockExtension(),
+				NewRandomExtension(1),
+				FormatExtension,
+			} {
+				AssertNoError(t, vm.RegisterExtension(ext), "RegisterExtension")
+			}
+
+			err = vm.Execute()
+			if tt.expectError {
+				AssertError(t, err, "vm.Execute")
+				return
+			}
+
+			AssertNoError(t, err, "vm.Execute")
+			AssertEquals(t, tt.wantDataStack, vm.DataStack(), "vm.dataStack")
+			AssertEquals(t, tt.wantOutput, output.String(), "output")
+		})
+	}
+}
+
+func TestFileExtensionWrite(t *testing.T) {
+	dir := t.TempDir()
+	assembly := strings.Replace(Preamble, "( {{main}} )", `
+:main
+  .string out.txt .end call @file.write-only call @file.open drop rpush
+  .string hello, .end rpeek call @file.write drop
+  rpop call @file.close drop
+  .string out.txt .end call @file.append call @file.open drop rpush
+  .string world .end rpeek call @file.write drop
+  rpop call @file.close drop
+  exit`, 1)
+
+	_, _, program, err := Assemble(bytes.NewBufferString(assembly))
+	AssertNoError(t, err, "Assemble")
+
+	vm, err := NewVM(program, &bytes.Buffer{}, &bytes.Buffer{})
+	AssertNoError(t, err, "NewVM")
+
+	fs, err := OpenFileSystem(dir)
+	AssertNoError(t, err, "OpenFileSystem")
+	defer fs.Close()
+	AssertNoError(t, vm.RegisterExtension(fs.Extension()), "RegisterExtension")
+
+	AssertNoError(t, vm.Execute(), "vm.Execute")
+
+	content, err := os.ReadFile(filepath.Join(dir, "out.txt"))
+	AssertNoError(t, err, "read out.txt")
+	AssertEquals(t, "hello,world", string(content), "file content")
+}
@@ -0,0 +1,282 @@
+package diatom
+
+import (
+	"errors"
+	"io"
+)
+
+// Machine implements the instructions of a VM. The interpreter and
+// code generated by CompileGo both execute instructions through its
+// methods so every instruction has a single implementation. Control
+// flow instructions are left to the caller.
+type Machine struct {
+	vm *VM
+}
+
+func (m *Machine) Push(w Word) error {
+	return m.vm.dataStack.Push(w)
+}
+
+func (m *Machine) Pop() (Word, error) {
+	return m.vm.dataStack.Pop()
+}
+
+// PushReturn pushes a return address as done by CALL.
+func (m *Machine) PushReturn(addr Word) error {
+	return m.vm.returnStack.Push(addr)
+}
+
+// PopReturn pops the return address for RET.
+func (m *Machine) PopReturn() (Word, error) {
+	return m.vm.returnStack.Pop()
+}
+
+func (m *Machine) Dup() error {
+	a, err := m.vm.dataStack.Peek()
+	if err != nil {
+		return err
+	}
+	return m.vm.dataStack.Push(a)
+}
+
+func (m *Machine) Drop() error {
+	_, err := m.vm.dataStack.Pop()
+	return err
+}
+
+func (m *Machine) Swap() error {
+	a, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	b, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	if err := m.vm.dataStack.Push(a); err != nil {
+		return err
+	}
+	return m.vm.dataStack.Push(b)
+}
+
+func (m *Machine) Over() error {
+	a, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	b, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	if err := m.vm.dataStack.Push(b); err != nil {
+		return err
+	}
+	if err := m.vm.dataStack.Push(a); err != nil {
+		return err
+	}
+	return m.vm.dataStack.Push(b)
+}
+
+func (m *Machine) RPush() error {
+	a, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	return m.vm.returnStack.Push(a)
+}
+
+func (m *Machine) RPop() error {
+	a, err := m.vm.returnStack.Pop()
+	if err != nil {
+		return err
+	}
+	return m.vm.dataStack.Push(a)
+}
+
+func (m *Machine) RPeek() error {
+	a, err := m.vm.returnStack.Peek()
+	if err != nil {
+		return err
+	}
+	return m.vm.dataStack.Push(a)
+}
+
+func (m *Machine) Store() error {
+	addr, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	value, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	return m.vm.storeWord(addr, value)
+}
+
+func (m *Machine) Fetch() error {
+	addr, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	w, err := m.vm.fetchWord(addr)
+	if err != nil {
+		return err
+	}
+	return m.vm.dataStack.Push(w)
+}
+
+func (m *Machine) BStore() error {
+	addr, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	value, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	return m.vm.storeByte(addr, byte(value))
+}
+
+func (m *Machine) BFetch() error {
+	addr, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	b, err := m.vm.fetchByte(addr)
+	if err != nil {
+		return err
+	}
+	return m.vm.dataStack.Push(Word(b))
+}
+
+// binary pops b and a (a on top) and pushes f(b, a).
+func (m *Machine) binary(f func(b, a Word) Word) error {
+	a, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	b, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	return m.vm.dataStack.Push(f(b, a))
+}
+
+func (m *Machine) Add() error {
+	return m.binary(add)
+}
+
+func (m *Machine) Sub() error {
+	return m.binary(subtract)
+}
+
+func (m *Machine) Mult() error {
+	return m.binary(multiply)
+}
+
+func (m *Machine) Div() error {
+	a, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	b, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	if a == 0 {
+		return &DivisionByZeroError{}
+	}
+	return m.vm.dataStack.Push(b / a)
+}
+
+func (m *Machine) Mod() error {
+	a, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	b, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	if a == 0 {
+		return &DivisionByZeroError{}
+	}
+	return m.vm.dataStack.Push(b % a)
+}
+
+func (m *Machine) Eq() error {
+	return m.binary(func(b, a Word) Word { return boolToWord(b == a) })
+}
+
+func (m *Machine) Not() error {
+	a, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	return m.vm.dataStack.Push(^a)
+}
+
+func (m *Machine) And() error {
+	return m.binary(func(b, a Word) Word { return b & a })
+}
+
+func (m *Machine) Or() error {
+	return m.binary(func(b, a Word) Word { return b | a })
+}
+
+func (m *Machine) Lt() error {
+	return m.binary(func(b, a Word) Word { return boolToWord(b < a) })
+}
+
+func (m *Machine) Gt() error {
+	return m.binary(func(b, a Word) Word { return boolToWord(a < b) })
+}
+
+// Key pushes the next byte of the input and reports if the input has
+// ended instead.
+func (m *Machine) Key() (bool, error) {
+	b, err := m.vm.key()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return true, nil
+		}
+		return false, err
+	}
+	return false, m.vm.dataStack.Push(Word(b))
+}
+
+func (m *Machine) Emit() error {
+	value, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	return m.vm.emit(byte(value))
+}
+
+func (m *Machine) Dump() error {
+	endAddr, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	return m.vm.dumpMemory("dump.dopc", endAddr)
+}
+
+// Depth pushes the number of values on the data stack.
+func (m *Machine) Depth() error {
+	return m.vm.dataStack.Push(Word(m.vm.dataStack.Len()))
+}
+
+func (m *Machine) ExtensionCall() error {
+	addr, err := m.vm.dataStack.Pop()
+	if err != nil {
+		return err
+	}
+	extFunc, ok := m.vm.extensions[addr]
+	if !ok {
+		return &UnknownExtensionError{Address: addr}
+	}
+	if err := extFunc(m.vm); err != nil {
+		return &ExtensionError{Address: addr, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,398 @@
+package diatom
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxInlineSize is the largest body in bytes a subroutine may have to
+// be inlined at its call sites. It allows a constant together with a
+// single instruction which is only one byte more than the call.
+const maxInlineSize = 1 + WordSize + 1
+
+// OptimizerReport summarizes the changes made by the optimizer.
+type OptimizerReport struct {
+	SizeBefore   int
+	SizeAfter    int
+	InlinedCalls int
+	Rewrites     int
+}
+
+func (r OptimizerReport) Saved() int {
+	return r.SizeBefore - r.SizeAfter
+}
+
+func (r OptimizerReport) String() string {
+	return fmt.Sprintf("%d -> %d bytes (saved %d bytes, %d calls inlined, %d rewrites)",
+		r.SizeBefore, r.SizeAfter, r.Saved(), r.InlinedCalls, r.Rewrites)
+}
+
+// asmNode is a single item of the macro expanded assembly: a label
+// declaration, an instruction together with its operand or a byte of
+// data.
+type asmNode struct {
+	token   string
+	operand []string
+	// origin is the address of the node before optimizing and is used
+	// to keep the source map intact.
+	origin Word
+}
+
+func (n asmNode) isLabel() bool {
+	return len(n.token) > 1 && n.token[0] == ':'
+}
+
+func (n asmNode) isInstruction() bool {
+	_, ok := instructions[n.token]
+	return ok
+}
+
+func (n asmNode) is(instruction string) bool {
+	return n.token == instruction
+}
+
+func (n asmNode) size() Word {
+	switch {
+	case n.isLabel():
+		return 0
+	case len(n.token) > 1 && n.token[0] == '@':
+		return WordSize
+	case len(n.operand) > 0:
+		return 1 + WordSize
+	default:
+		return 1
+	}
+}
+
+// constant returns the value of a const instruction with a numeric
+// operand.
+func (n asmNode) constant() (Word, bool) {
+	if !n.is("const") || len(n.operand) != WordSize {
+		return 0, false
+	}
+
+	b := make([]byte, WordSize)
+	for i, token := range n.operand {
+		value, err := strconv.Atoi(token)
+		if err != nil || value < 0 || value > 255 {
+			return 0, false
+		}
+		b[i] = byte(value)
+	}
+
+	return bytesToWord(b), true
+}
+
+func constNode(value Word, origin Word) asmNode {
+	bytes := wordToBytes(value)
+	operand := make([]string, WordSize)
+	for i := range WordSize {
+		operand[i] = strconv.Itoa(int(bytes[WordSize-1-i]))
+	}
+
+	return asmNode{token: "const", operand: operand, origin: origin}
+}
+
+func parseNodes(dexp string) ([]asmNode, error) {
+	tokens := strings.Fields(dexp)
+	nodes := []asmNode{}
+	var address Word
+
+	for i := 0; i < len(tokens); i++ {
+		token := tokens[i]
+		if token == "(" {
+			for i < len(tokens) && tokens[i] != ")" {
+				i++
+			}
+			continue
+		}
+
+		node := asmNode{token: token, origin: address}
+		if opcode, ok := instructions[token]; ok && hasOperand(opcode) {
+			switch {
+			case i+1 < len(tokens) && len(tokens[i+1]) > 1 && tokens[i+1][0] == '@':
+				node.operand = tokens[i+1 : i+2]
+			case i+WordSize < len(tokens):
+				node.operand = tokens[i+1 : i+1+WordSize]
+			default:
+				return nil, fmt.Errorf("instruction %q at address '%d' is missing its operand",
+					token, address)
+			}
+			i += len(node.operand)
+		}
+
+		nodes = append(nodes, node)
+		address += node.size()
+	}
+
+	return nodes, nil
+}
+
+func writeNodes(w io.Writer, nodes []asmNode) error {
+	for _, n := range nodes {
+		if _, err := fmt.Fprintln(w, n.token); err != nil {
+			return err
+		}
+		if len(n.operand) > 0 {
+			if _, err := fmt.Fprintln(w, strings.Join(n.operand, " ")); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func nodesSize(nodes []asmNode) int {
+	var size Word
+	for _, n := range nodes {
+		size += n.size()
+	}
+	return int(size)
+}
+
+// inlinable reports if the subroutine starting after the label at
+// index i is a straight sequence of instructions that neither touches
+// the return stack nor is too large to be inlined. It returns the body
+// without the final ret.
+func inlinable(nodes []asmNode, i int) ([]asmNode, bool) {
+	var size Word
+	for j := i + 1; j < len(nodes); j++ {
+		n := nodes[j]
+		if n.is("ret") {
+			return nodes[i+1 : j], true
+		}
+
+		if !n.isInstruction() {
+			return nil, false
+		}
+		switch n.token {
+		case "jmp", "cjmp", "call", "exit", "abort", "rpush", "rpop", "rpeek":
+			return nil, false
+		}
+
+		size += n.size()
+		if size > maxInlineSize {
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+func inlineCalls(nodes []asmNode, report *OptimizerReport) []asmNode {
+	bodies := map[string][]asmNode{}
+	for i, n := range nodes {
+		if !n.isLabel() {
+			continue
+		}
+		if body, ok := inlinable(nodes, i); ok {
+			bodies[n.token[1:]] = body
+		}
+	}
+
+	result := make([]asmNode, 0, len(nodes))
+	for _, n := range nodes {
+		if !n.is("call") || len(n.operand) != 1 {
+			result = append(result, n)
+			continue
+		}
+
+		body, ok := bodies[n.operand[0][1:]]
+		if !ok {
+			result = append(result, n)
+			continue
+		}
+
+		for _, b := range body {
+			b.origin = n.origin
+			result = append(result, b)
+		}
+		report.InlinedCalls++
+	}
+
+	return result
+}
+
+func foldConstants(instruction string, b, a Word) (Word, bool) {
+	switch instruction {
+	case "+":
+		return add(b, a), true
+	case "-":
+		return subtract(b, a), true
+	case "*":
+		return multiply(b, a), true
+	case "/":
+		if a == 0 {
+			return 0, false
+		}
+		return b / a, true
+	case "%":
+		if a == 0 {
+			return 0, false
+		}
+		return b % a, true
+	case "&":
+		return b & a, true
+	case "|":
+		return b | a, true
+	case "=":
+		return boolToWord(b == a), true
+	case "<":
+		return boolToWord(b < a), true
+	case ">":
+		return boolToWord(b > a), true
+	default:
+		return 0, false
+	}
+}
+
+// neutralConstants maps instructions to the operand that leaves the
+// value below unchanged.
+var neutralConstants = map[string]Word{
+	"+": 0,
+	"-": 0,
+	"|": 0,
+	"*": 1,
+	"/": 1,
+	"&": -1,
+}
+
+// noOpPairs are instruction pairs that cancel each other out.
+var noOpPairs = map[[2]string]struct{}{
+	{"dup", "drop"}:   {},
+	{"over", "drop"}:  {},
+	{"swap", "swap"}:  {},
+	{"rpush", "rpop"}: {},
+	{"rpop", "rpush"}: {},
+}
+
+// rewrite tries to replace the instructions at the beginning of
+// window with a shorter sequence. It returns the replacement and the
+// number of nodes consumed.
+func rewrite(window []asmNode) ([]asmNode, int, bool) {
+	code := 0
+	for code < len(window) && code < 3 && window[code].isInstruction() {
+		code++
+	}
+	if code < 2 {
+		return nil, 0, false
+	}
+
+	first, second := window[0], window[1]
+	if _, ok := noOpPairs[[2]string{first.token, second.token}]; ok {
+		return nil, 2, true
+	}
+
+	if first.is("const") && second.is("drop") {
+		return nil, 2, true
+	}
+
+	a, ok := first.constant()
+	if !ok {
+		return nil, 0, false
+	}
+
+	if neutral, ok := neutralConstants[second.token]; ok && a == neutral {
+		return nil, 2, true
+	}
+	if second.is("~") {
+		return []asmNode{constNode(^a, first.origin)}, 2, true
+	}
+
+	if code < 3 {
+		return nil, 0, false
+	}
+	third := window[2]
+
+	if second.is("swap") && third.is("drop") {
+		drop := third
+		drop.origin = first.origin
+		return []asmNode{drop, first}, 3, true
+	}
+
+	if b, ok := second.constant(); ok {
+		if value, ok := foldConstants(third.token, a, b); ok {
+			return []asmNode{constNode(value, first.origin)}, 3, true
+		}
+	}
+
+	return nil, 0, false
+}
+
+func peephole(nodes []asmNode, report *OptimizerReport) ([]asmNode, bool) {
+	result := make([]asmNode, 0, len(nodes))
+	changed := false
+
+	for i := 0; i < len(nodes); {
+		replacement, consumed, ok := rewrite(nodes[i:])
+		if !ok {
+			result = append(result, nodes[i])
+			i++
+			continue
+		}
+
+		result = append(result, replacement...)
+		i += consumed
+		report.Rewrites++
+		changed = true
+	}
+
+	return result, changed
+}
+
+func optimizeNodes(nodes []asmNode) ([]asmNode, OptimizerReport) {
+	report := OptimizerReport{SizeBefore: nodesSize(nodes)}
+
+	for changed := true; changed; {
+		nodes, changed = peephole(nodes, &report)
+	}
+	nodes = inlineCalls(nodes, &report)
+	for changed := true; changed; {
+		nodes, changed = peephole(nodes, &report)
+	}
+
+	report.SizeAfter = nodesSize(nodes)
+	return nodes, report
+}
+
+// Optimize rewrites the macro expanded assembly (.dexp) read from r
+// into a smaller equivalent. Calls to trivial subroutines get inlined
+// and short instruction sequences are simplified or folded into
+// constants. Labels stay symbolic so they can be resolved afterwards
+// as usual.
+func Optimize(r io.Reader, w io.Writer) (OptimizerReport, error) {
+	dexp, err := io.ReadAll(r)
+	if err != nil {
+		return OptimizerReport{}, fmt.Errorf("failed to read assembly: %w", err)
+	}
+
+	nodes, err := parseNodes(string(dexp))
+	if err != nil {
+		return OptimizerReport{}, err
+	}
+
+	nodes, report := optimizeNodes(nodes)
+	return report, writeNodes(w, nodes)
+}
+
+// remapSourceMap returns a source map for the optimized nodes based on
+// the source map of the original code.
+func remapSourceMap(m *SourceMap, nodes []asmNode) *SourceMap {
+	remapped := &SourceMap{}
+	for _, n := range nodes {
+		size := n.size()
+		if size == 0 {
+			continue
+		}
+
+		if location, ok := m.Lookup(n.origin); ok {
+			remapped.add(remapped.Size, location)
+		}
+		remapped.Size += size
+	}
+
+	return remapped
+}
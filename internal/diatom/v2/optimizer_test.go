@@ -0,0 +1,72 @@
+package diatom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+func TestOptimize(t *testing.T) {
+	tests := []struct {
+		name     string
+		in       string
+		expected string
+	}{
+		{"no-op pairs", "dup drop swap swap const 1 rpush rpop", "const\n0 0 0 1\n"},
+		{"constant drop", "const 7 drop const @x drop :x", ":x\n"},
+		{"neutral constants", "const 0 + const 1 * const -1 &", ""},
+		{"constant folding", "const 3 const 4 + const 2 *", "const\n0 0 0 14\n"},
+		{"comparison folding", "const 3 const 4 <", "const\n255 255 255 255\n"},
+		{"not folding", "const 0 ~", "const\n255 255 255 255\n"},
+		{"no division by zero", "const 3 const 0 /", "const\n0 0 0 3\nconst\n0 0 0 0\n/\n"},
+		{"replace top", "const 5 swap drop", "drop\nconst\n0 0 0 5\n"},
+		{"labels stop rewrites", "dup :x drop", "dup\n:x\ndrop\n"},
+		{"inline constant", ":x const 4 ret call @x",
+			":x\nconst\n0 0 0 4\nret\nconst\n0 0 0 4\n"},
+		{"inline and fold", ".codeword w+ const 4 + .end const 1 call @w+",
+			":_dict-w+\n0\n0\n0\n0\n0\n2\n2\n119\n43\n:w+\nconst\n0 0 0 4\n+\nret\nconst\n0 0 0 5\n"},
+		{"keep return stack words", ":x rpop ret call @x", ":x\nrpop\nret\ncall\n@x\n"},
+		{"keep large subroutines", ":x dup dup dup dup dup dup dup ret call @x",
+			":x\ndup\ndup\ndup\ndup\ndup\ndup\ndup\nret\ncall\n@x\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dexp := bytes.Buffer{}
+			err := ExpandMacros(bytes.NewBufferString(tt.in), &dexp)
+			AssertNoError(t, err, "ExpandMacros")
+
+			out := bytes.Buffer{}
+			_, err = Optimize(&dexp, &out)
+			AssertNoError(t, err, "Optimize")
+			AssertEquals(t, tt.expected, out.String(), "Optimize output")
+		})
+	}
+}
+
+func TestOptimizeReport(t *testing.T) {
+	in := ".codeword w+ const 4 + .end :main const 1 call @w+ dup drop exit"
+	p, err := AssembleProgramWithOptions("", bytes.NewBufferString(in), AssemblerOptions{Optimize: true})
+	AssertNoError(t, err, "AssembleProgramWithOptions")
+
+	report := p.Optimization
+	AssertEquals(t, 29, report.SizeBefore, "size before")
+	AssertEquals(t, 22, report.SizeAfter, "size after")
+	AssertEquals(t, 1, report.InlinedCalls, "inlined calls")
+	AssertEquals(t, len(p.Dopc), report.SizeAfter, "program size")
+	AssertEquals(t, Word(16), p.Labels["main"], "main label")
+	AssertEquals(t, Word(len(p.Dopc)), p.SourceMap.Size, "source map size")
+
+	location, ok := p.SourceMap.Lookup(p.Labels["main"])
+	AssertEquals(t, true, ok, "main location found")
+	AssertEquals(t, "main", location.Label, "main location label")
+}
+
+func TestOptimizePreamble(t *testing.T) {
+	assembly := strings.Replace(Preamble, "( {{main}} )", ":main exit", 1)
+	p, err := AssembleProgramWithOptions("", bytes.NewBufferString(assembly), AssemblerOptions{Optimize: true})
+	AssertNoError(t, err, "AssembleProgramWithOptions")
+	AssertEquals(t, true, p.Optimization.Saved() > 0, "preamble got smaller")
+}
@@ -205,34 +205,37 @@ func TestPreamble(t *testing.T) {
 		// {"const 10 const 5 !true !cbranch const 20", []Word{10, 20}, []Word{}, "", ""},
 	}
 
-	for _, tt := range tests {
-		t.Run(tt.assembly, func(t *testing.T) {
-			assembly := strings.Replace(Preamble, "( {{main}} )", "\n:main\n"+tt.assembly+"\nexit", 1)
-
-			_, dins, program, err := Assemble(bytes.NewBufferString(assembly))
-			AssertNoError(t, err, "Assemble")
-
-			input := bytes.NewBufferString(tt.input + " ")
-			output := &bytes.Buffer{}
-
-			vm, err := NewVM(program, input, output)
-			AssertNoError(t, err, "NewVM")
-
-			err = vm.Execute()
-			AssertNoError(t, err, "vm.Execute")
-			dataSlice := vm.dataStack.data[:vm.dataStack.cursor]
-			AssertEquals(t, tt.wantDataStack, dataSlice, "vm.dataStack")
-			returnSlice := vm.returnStack.data[:vm.returnStack.cursor]
-			AssertContainsAll(t, tt.wantReturnStack, returnSlice, "vm.returnStack")
-			AssertEquals(t, tt.wantOutput, output.String(), "output")
-
-			if t.Failed() {
-				err = os.WriteFile("preamble.dins", []byte(dins), 0666)
-				AssertNoError(t, err, "write .dins file")
-
-				err = vm.CoreDump()
-				AssertNoError(t, err, "write core dump file")
-			}
-		})
+	for _, opts := range []AssemblerOptions{{}, {Optimize: true}} {
+		for _, tt := range tests {
+			t.Run(tt.assembly, func(t *testing.T) {
+				assembly := strings.Replace(Preamble, "( {{main}} )", "\n:main\n"+tt.assembly+"\nexit", 1)
+
+				p, err := AssembleProgramWithOptions("", bytes.NewBufferString(assembly), opts)
+				AssertNoError(t, err, "Assemble")
+				dins, program := p.Dins, p.Dopc
+
+				input := bytes.NewBufferString(tt.input + " ")
+				output := &bytes.Buffer{}
+
+				vm, err := NewVM(program, input, output)
+				AssertNoError(t, err, "NewVM")
+
+				err = vm.Execute()
+				AssertNoError(t, err, "vm.Execute")
+				dataSlice := vm.dataStack.data[:vm.dataStack.cursor]
+				AssertEquals(t, tt.wantDataStack, dataSlice, "vm.dataStack")
+				returnSlice := vm.returnStack.data[:vm.returnStack.cursor]
+				AssertContainsAll(t, tt.wantReturnStack, returnSlice, "vm.returnStack")
+				AssertEquals(t, tt.wantOutput, output.String(), "output")
+
+				if t.Failed() {
+					err = os.WriteFile("preamble.dins", []byte(dins), 0666)
+					AssertNoError(t, err, "write .dins file")
+
+					err = vm.CoreDump()
+					AssertNoError(t, err, "write core dump file")
+				}
+			})
+		}
 	}
 }
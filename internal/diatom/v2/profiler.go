@@ -0,0 +1,315 @@
+package diatom
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/google/pprof/profile"
+)
+
+type stackSample struct {
+	stack []Word
+	count uint64
+}
+
+// Profile counts the instructions executed by a VM. Calls are tracked
+// through CALL and RET, which makes the call stacks approximate for
+// code that manipulates the return stack itself.
+type Profile struct {
+	vm *VM
+	// Instructions counts the executions of the instruction at each
+	// address.
+	Instructions map[Word]uint64
+	// Calls counts the calls to each address.
+	Calls map[Word]uint64
+	Total uint64
+
+	// callSites holds the addresses of the CALL instructions that
+	// lead to the current program counter.
+	callSites []Word
+	samples   map[string]*stackSample
+	key       []byte
+}
+
+func newProfile(vm *VM) *Profile {
+	return &Profile{
+		vm:           vm,
+		Instructions: map[Word]uint64{},
+		Calls:        map[Word]uint64{},
+		samples:      map[string]*stackSample{},
+	}
+}
+
+// record counts the instruction at the current program counter before
+// it gets executed.
+func (p *Profile) record(instruction byte) {
+	pc := p.vm.programCounter
+	p.Total++
+	p.Instructions[pc]++
+
+	p.key = p.key[:0]
+	p.key = strconv.AppendInt(p.key, int64(pc), 10)
+	for i := len(p.callSites) - 1; i >= 0; i-- {
+		p.key = append(p.key, ' ')
+		p.key = strconv.AppendInt(p.key, int64(p.callSites[i]), 10)
+	}
+
+	if sample, ok := p.samples[string(p.key)]; ok {
+		sample.count++
+	} else {
+		stack := append([]Word{pc}, p.callSites...)
+		slices.Reverse(stack[1:])
+		p.samples[string(p.key)] = &stackSample{stack: stack, count: 1}
+	}
+
+	switch instruction {
+	case CALL:
+		if target, err := p.vm.fetchWord(pc + 1); err == nil {
+			p.Calls[target]++
+		}
+		p.callSites = append(p.callSites, pc)
+	case RET:
+		if len(p.callSites) > 0 {
+			p.callSites = p.callSites[:len(p.callSites)-1]
+		}
+	}
+
+	// There can't be more active calls than return addresses.
+	if max := p.vm.returnStack.cursor + 1; len(p.callSites) > max {
+		p.callSites = p.callSites[len(p.callSites)-max:]
+	}
+}
+
+// DictionarySymbols returns the addresses of the codewords found in
+// the dictionary of the VM's memory. It can be used in place of the
+// assembler labels for programs only available as machine code.
+func (vm *VM) DictionarySymbols() Symbols {
+	return dictionarySymbols(vm.memory)
+}
+
+func dictionarySymbols(memory []byte) Symbols {
+	symbols := Symbols{}
+	for _, entry := range Dictionary(memory) {
+		if _, ok := symbols[entry.Name]; !ok {
+			symbols[entry.Name] = entry.Code
+		}
+	}
+
+	return symbols
+}
+
+// generatedLabel reports if the label has been generated by a macro
+// and therefore doesn't start a codeword of its own.
+func generatedLabel(label string) bool {
+	if strings.HasPrefix(label, "_") {
+		return true
+	}
+
+	n, ok := strings.CutPrefix(label, "string-")
+	if !ok {
+		return false
+	}
+	_, err := strconv.Atoi(strings.TrimSuffix(n, "-end"))
+	return err == nil
+}
+
+func (p *Profile) symbols(symbols Symbols) Symbols {
+	if symbols == nil {
+		return p.vm.DictionarySymbols()
+	}
+
+	codewords := Symbols{}
+	for label, addr := range symbols {
+		if !generatedLabel(label) {
+			codewords[label] = addr
+		}
+	}
+	return codewords
+}
+
+// functionNames returns a function that looks up the name of the
+// codeword or label the code at an address belongs to.
+func functionNames(symbols Symbols) func(addr Word) string {
+	names := map[Word]string{}
+
+	return func(addr Word) string {
+		if name, ok := names[addr]; ok {
+			return name
+		}
+
+		name, _, ok := symbols.closest(addr)
+		if !ok {
+			name = "?"
+		}
+		names[addr] = name
+		return name
+	}
+}
+
+type functionStats struct {
+	name  string
+	self  uint64
+	cum   uint64
+	calls uint64
+}
+
+// WriteReport writes a summary of the instructions executed per
+// codeword and per address. Without symbols the codewords are taken
+// from the dictionary in the VM's memory.
+func (p *Profile) WriteReport(w io.Writer, symbols Symbols) error {
+	symbols = p.symbols(symbols)
+	function := functionNames(symbols)
+
+	stats := map[string]*functionStats{}
+	stat := func(name string) *functionStats {
+		s, ok := stats[name]
+		if !ok {
+			s = &functionStats{name: name}
+			stats[name] = s
+		}
+		return s
+	}
+
+	for addr, count := range p.Instructions {
+		stat(function(addr)).self += count
+	}
+	for addr, count := range p.Calls {
+		stat(function(addr)).calls += count
+	}
+	for _, sample := range p.samples {
+		seen := map[string]struct{}{}
+		for _, addr := range sample.stack {
+			name := function(addr)
+			if _, ok := seen[name]; ok {
+				continue
+			}
+			seen[name] = struct{}{}
+			stat(name).cum += sample.count
+		}
+	}
+
+	functions := make([]*functionStats, 0, len(stats))
+	for _, s := range stats {
+		functions = append(functions, s)
+	}
+	slices.SortFunc(functions, func(a, b *functionStats) int {
+		if c := cmp.Compare(b.self, a.self); c != 0 {
+			return c
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	addresses := make([]Word, 0, len(p.Instructions))
+	for addr := range p.Instructions {
+		addresses = append(addresses, addr)
+	}
+	slices.SortFunc(addresses, func(a, b Word) int {
+		if c := cmp.Compare(p.Instructions[b], p.Instructions[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+
+	percent := func(count uint64) string {
+		if p.Total == 0 {
+			return "0.00%"
+		}
+		return fmt.Sprintf("%.2f%%", float64(count)*100/float64(p.Total))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintf(tw, "Instructions executed: %d\n\n", p.Total)
+
+	fmt.Fprintln(tw, "self\tself%\tcum\tcum%\tcalls\t codeword")
+	for _, f := range functions {
+		fmt.Fprintf(tw, "%d\t%s\t%d\t%s\t%d\t %s\n",
+			f.self, percent(f.self), f.cum, percent(f.cum), f.calls, f.name)
+	}
+
+	fmt.Fprintln(tw, "\ncount\tcount%\taddress\t location")
+	for _, addr := range addresses {
+		count := p.Instructions[addr]
+		fmt.Fprintf(tw, "%d\t%s\t%d\t %s\n",
+			count, percent(count), addr, p.location(symbols, addr))
+	}
+
+	return tw.Flush()
+}
+
+func (p *Profile) location(symbols Symbols, addr Word) string {
+	location := symbols.Resolve(addr)
+	if source, ok := p.vm.sourceMap.Lookup(addr); ok {
+		location += " (" + source.String() + ")"
+	}
+	return location
+}
+
+// WritePprof writes the profile in the gzipped protobuf format read
+// by 'go tool pprof'. Each codeword is reported as a function and the
+// executed instructions as samples.
+func (p *Profile) WritePprof(w io.Writer, symbols Symbols) error {
+	symbols = p.symbols(symbols)
+	function := functionNames(symbols)
+
+	prof := &profile.Profile{
+		SampleType: []*profile.ValueType{{Type: "instructions", Unit: "count"}},
+		PeriodType: &profile.ValueType{Type: "instructions", Unit: "count"},
+		Period:     1,
+	}
+
+	functions := map[string]*profile.Function{}
+	locations := map[Word]*profile.Location{}
+	location := func(addr Word) *profile.Location {
+		if l, ok := locations[addr]; ok {
+			return l
+		}
+
+		name := function(addr)
+		f, ok := functions[name]
+		if !ok {
+			f = &profile.Function{ID: uint64(len(functions) + 1), Name: name, SystemName: name}
+			functions[name] = f
+			prof.Function = append(prof.Function, f)
+		}
+
+		line := profile.Line{Function: f}
+		if source, ok := p.vm.sourceMap.Lookup(addr); ok {
+			line.Line = int64(source.Line)
+			f.Filename = source.File
+		}
+
+		l := &profile.Location{
+			ID:      uint64(len(locations) + 1),
+			Address: uint64(addr),
+			Line:    []profile.Line{line},
+		}
+		locations[addr] = l
+		prof.Location = append(prof.Location, l)
+		return l
+	}
+
+	keys := make([]string, 0, len(p.samples))
+	for key := range p.samples {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	for _, key := range keys {
+		sample := p.samples[key]
+		s := &profile.Sample{Value: []int64{int64(sample.count)}}
+		for _, addr := range sample.stack {
+			s.Location = append(s.Location, location(addr))
+		}
+		prof.Sample = append(prof.Sample, s)
+	}
+
+	if err := prof.CheckValid(); err != nil {
+		return fmt.Errorf("invalid profile: %w", err)
+	}
+	return prof.Write(w)
+}
@@ -0,0 +1,76 @@
+package diatom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+	"github.com/google/pprof/profile"
+)
+
+func TestProfile(t *testing.T) {
+	program, err := AssembleProgram("test.dasm", bytes.NewBufferString(
+		":main call @f call @f exit :f const 1 drop ret"))
+	AssertNoError(t, err, "AssembleProgram")
+
+	vm, err := NewVMWithOptions(program.Dopc, &bytes.Buffer{}, &bytes.Buffer{},
+		VMOptions{Profile: true})
+	AssertNoError(t, err, "NewVMWithOptions")
+	vm.SetSourceMap(program.SourceMap)
+	AssertNoError(t, vm.Execute(), "vm.Execute")
+
+	p := vm.Profile()
+	AssertEquals(t, uint64(9), p.Total, "total instructions")
+	AssertEquals(t, uint64(2), p.Calls[program.Labels["f"]], "calls of f")
+	AssertEquals(t, uint64(2), p.Instructions[program.Labels["f"]], "executions of f")
+
+	report := &bytes.Buffer{}
+	AssertNoError(t, p.WriteReport(report, program.Labels), "WriteReport")
+	fields := strings.Join(strings.Fields(report.String()), " ")
+	for _, want := range []string{
+		"Instructions executed: 9",
+		"6 66.67% 6 66.67% 2 f",
+		"3 33.33% 9 100.00% 0 main",
+		"2 22.22% 11 f (test.dasm:1 at :f)",
+	} {
+		AssertStringContains(t, want, fields, "report")
+	}
+
+	pprof := &bytes.Buffer{}
+	AssertNoError(t, p.WritePprof(pprof, program.Labels), "WritePprof")
+	parsed, err := profile.Parse(pprof)
+	AssertNoError(t, err, "profile.Parse")
+
+	var total int64
+	for _, s := range parsed.Sample {
+		total += s.Value[0]
+		leaf := s.Location[0].Line[0].Function.Name
+		if leaf == "f" {
+			AssertEquals(t, "main", s.Location[1].Line[0].Function.Name, "caller of f")
+		}
+	}
+	AssertEquals(t, int64(9), total, "total samples")
+}
+
+func TestProfileDisabled(t *testing.T) {
+	vm, err := NewVM([]byte{EXIT}, &bytes.Buffer{}, &bytes.Buffer{})
+	AssertNoError(t, err, "NewVM")
+	AssertNoError(t, vm.Execute(), "vm.Execute")
+	AssertEquals(t, true, vm.Profile() == nil, "profile disabled")
+}
+
+func TestDictionarySymbols(t *testing.T) {
+	repl, err := NewRepl(&bytes.Buffer{})
+	AssertNoError(t, err, "NewRepl")
+	AssertNoError(t, repl.Eval(": quad dup + dup + ;"), "repl.Eval")
+
+	symbols := repl.VM().DictionarySymbols()
+	AssertEquals(t, repl.program.Labels["dup"], symbols["dup"], "address of dup")
+	AssertEquals(t, repl.program.Labels["word.read"], symbols["word.read"], "address of word.read")
+
+	quad, ok := symbols["quad"]
+	AssertEquals(t, true, ok, "quad found")
+	AssertEquals(t, true, quad > repl.program.Labels["here-address"], "quad in user dictionary")
+	AssertEquals(t, false, strings.HasPrefix(symbols.Resolve(0), "_"), "no generated labels")
+}
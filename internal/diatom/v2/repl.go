@@ -0,0 +1,154 @@
+package diatom
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// replMain resets the compilation state and interprets words forever.
+// word.interpret only returns after an unknown word, leaving the
+// failed number conversion on the stack.
+const replMain = `
+:main
+  call @[
+  call @word.interpret
+  drop
+  jmp @main
+`
+
+// Repl feeds source code into the Forth interpreter of the preamble
+// and keeps the VM alive between evaluations.
+type Repl struct {
+	vm      *VM
+	input   *bytes.Buffer
+	program Program
+}
+
+// AssembleRepl returns the preamble's Forth interpreter as a program
+// that interprets the VM's input until it ends. It starts with a fresh
+// dictionary and runs on any diatom VM configured by opts.
+func AssembleRepl(opts VMOptions) (Program, error) {
+	source := strings.Replace(PreambleWithOptions(opts), "( {{main}} )", replMain, 1)
+	return AssembleProgram("preamble.dasm", bytes.NewBufferString(source))
+}
+
+func newRepl(program Program, image []byte, output io.Writer,
+	opts VMOptions) (*Repl, error) {
+	input := &bytes.Buffer{}
+	vm, err := NewVMWithOptions(image, input, output, opts)
+	if err != nil {
+		return nil, err
+	}
+	vm.SetSourceMap(program.SourceMap)
+
+	return &Repl{vm: vm, input: input, program: program}, nil
+}
+
+// NewRepl returns a REPL with a fresh dictionary.
+func NewRepl(output io.Writer) (*Repl, error) {
+	return NewReplWithOptions(output, VMOptions{})
+}
+
+// NewReplWithOptions returns a REPL with a fresh dictionary running
+// on a VM configured by opts.
+func NewReplWithOptions(output io.Writer, opts VMOptions) (*Repl, error) {
+	program, err := AssembleRepl(opts)
+	if err != nil {
+		return nil, fmt.Errorf("assemble REPL: %w", err)
+	}
+
+	return newRepl(program, program.Dopc, output, opts)
+}
+
+// LoadRepl returns a REPL that continues with the dictionary of an
+// image previously created by Image.
+func LoadRepl(image []byte, output io.Writer) (*Repl, error) {
+	return LoadReplWithOptions(image, output, VMOptions{})
+}
+
+// LoadReplWithOptions is like LoadRepl but runs the image on a VM
+// configured by opts.
+func LoadReplWithOptions(image []byte, output io.Writer, opts VMOptions) (*Repl, error) {
+	program, err := AssembleRepl(opts)
+	if err != nil {
+		return nil, fmt.Errorf("assemble REPL: %w", err)
+	}
+
+	if len(image) < 1+WordSize || image[0] != JMP ||
+		bytesToWord(image[1:1+WordSize]) != program.Labels["main"] {
+		return nil, errors.New("load REPL image: not an image of this REPL version")
+	}
+
+	return newRepl(program, image, output, opts)
+}
+
+func (r *Repl) VM() *VM {
+	return r.vm
+}
+
+func (r *Repl) variable(name string) Word {
+	w, _ := r.vm.fetchWord(r.program.Labels["_var-"+name])
+	return w
+}
+
+// Compiling reports if the interpreter is in the middle of a word
+// definition.
+func (r *Repl) Compiling() bool {
+	return r.variable("word.compile-state") != 0
+}
+
+func (r *Repl) waitingForInput() bool {
+	instruction, err := r.vm.fetchByte(r.vm.programCounter)
+	return err == nil && instruction == KEY
+}
+
+// restart clears the stacks and the remaining input and continues
+// with a fresh interpreter loop. The dictionary is kept as is.
+func (r *Repl) restart() {
+	r.input.Reset()
+	r.vm.inputBuffer = Input{}
+	r.vm.dataStack.cursor = 0
+	r.vm.returnStack.cursor = 0
+	r.vm.programCounter = r.program.Labels["main"]
+}
+
+// Eval interprets the given code and returns once all of it has been
+// consumed. After an error the REPL starts over with empty stacks.
+func (r *Repl) Eval(code string) error {
+	r.input.WriteString(code)
+	r.input.WriteByte('\n')
+
+	if err := r.vm.execute(); err != nil {
+		r.restart()
+		return err
+	}
+
+	if !r.waitingForInput() {
+		r.restart()
+		return ErrProgramFinished
+	}
+
+	return nil
+}
+
+// Image returns the memory of the VM up to the end of the dictionary
+// so it can be loaded again with LoadRepl.
+func (r *Repl) Image() ([]byte, error) {
+	here := r.variable("word.here")
+	image, err := r.vm.Memory(0, int(here))
+	if err != nil {
+		return nil, fmt.Errorf("create REPL image: %w", err)
+	}
+
+	// Jumping straight to main skips the initialization of the
+	// dictionary variables.
+	main := wordToBytes(r.program.Labels["main"])
+	for i := range WordSize {
+		image[1+i] = main[WordSize-(i+1)]
+	}
+
+	return image, nil
+}
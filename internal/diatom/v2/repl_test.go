@@ -0,0 +1,75 @@
+package diatom_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/eldelto/core/internal/diatom/v2"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+func TestRepl(t *testing.T) {
+	output := &bytes.Buffer{}
+	repl, err := diatom.NewRepl(output)
+	AssertNoError(t, err, "NewRepl")
+
+	AssertNoError(t, repl.Eval("1 2"), "repl.Eval")
+	AssertEquals(t, []diatom.Word{1, 2}, repl.VM().DataStack(), "data stack")
+
+	AssertNoError(t, repl.Eval(": quad"), "repl.Eval")
+	AssertEquals(t, true, repl.Compiling(), "compiling")
+	AssertNoError(t, repl.Eval("  dup + dup + ;"), "repl.Eval")
+	AssertEquals(t, false, repl.Compiling(), "compiling")
+
+	AssertNoError(t, repl.Eval("+ quad ."), "repl.Eval")
+	AssertEquals(t, "12\n", output.String(), "output")
+
+	AssertError(t, repl.Eval("drop drop"), "repl.Eval stack underflow")
+	AssertEquals(t, []diatom.Word{}, repl.VM().DataStack(), "data stack after error")
+
+	AssertNoError(t, repl.Eval("5 quad"), "repl.Eval after error")
+	AssertEquals(t, []diatom.Word{20}, repl.VM().DataStack(), "data stack")
+
+	AssertNoError(t, repl.Eval("unknown"), "repl.Eval unknown word")
+	AssertEquals(t, []diatom.Word{20}, repl.VM().DataStack(), "data stack after unknown word")
+}
+
+func TestReplPrintStack(t *testing.T) {
+	output := &bytes.Buffer{}
+	repl, err := diatom.NewRepl(output)
+	AssertNoError(t, err, "NewRepl")
+	AssertNoError(t, repl.Eval(diatom.Stdlib), "repl.Eval stdlib")
+
+	AssertNoError(t, repl.Eval(".s"), "repl.Eval")
+	AssertNoError(t, repl.Eval("1 2 3 .s"), "repl.Eval")
+	AssertNoError(t, repl.Eval("+ .s"), "repl.Eval")
+	AssertEquals(t, "<0>\n<3> 1 2 3\n<2> 1 5\n", output.String(), "output")
+	AssertEquals(t, []diatom.Word{1, 5}, repl.VM().DataStack(), "data stack")
+}
+
+func TestReplImage(t *testing.T) {
+	repl, err := diatom.NewRepl(&bytes.Buffer{})
+	AssertNoError(t, err, "NewRepl")
+	AssertNoError(t, repl.Eval(": add2 2 + ;"), "repl.Eval")
+
+	image, err := repl.Image()
+	AssertNoError(t, err, "repl.Image")
+
+	loaded, err := diatom.LoadRepl(image, &bytes.Buffer{})
+	AssertNoError(t, err, "LoadRepl")
+	AssertNoError(t, loaded.Eval("3 add2"), "loaded.Eval")
+	AssertEquals(t, []diatom.Word{5}, loaded.VM().DataStack(), "data stack")
+
+	_, err = diatom.LoadRepl([]byte{1, 2, 3}, &bytes.Buffer{})
+	AssertError(t, err, "LoadRepl invalid image")
+}
+
+func TestReplWithOptions(t *testing.T) {
+	opts := diatom.VMOptions{MemorySize: 16384, DataStackSize: 64, ReturnStackSize: 32}
+	repl, err := diatom.NewReplWithOptions(&bytes.Buffer{}, opts)
+	AssertNoError(t, err, "NewReplWithOptions")
+
+	AssertNoError(t, repl.Eval("memory.size stack.size rstack.size"), "repl.Eval")
+	AssertEquals(t, []diatom.Word{16384, 64, 32}, repl.VM().DataStack(), "data stack")
+}
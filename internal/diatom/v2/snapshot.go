@@ -0,0 +1,201 @@
+package diatom
+
+import (
+	"fmt"
+	"io"
+	"slices"
+)
+
+// SnapshotVersion is increased whenever the layout of a Snapshot
+// changes in an incompatible way.
+const SnapshotVersion = 1
+
+// ExtensionID identifies a registered extension. Host functions can't
+// be serialized so a snapshot only records which extensions have to be
+// registered again when it is restored.
+type ExtensionID struct {
+	Addr      uint16 `json:"addr"`
+	Name      string `json:"name"`
+	Functions int    `json:"functions"`
+}
+
+func (ext Extension) id() ExtensionID {
+	return ExtensionID{Addr: ext.Addr, Name: ext.Name, Functions: len(ext.Functions)}
+}
+
+// TaskSnapshot is the state of a task spawned with the task
+// extension.
+type TaskSnapshot struct {
+	ID             Word   `json:"id"`
+	ProgramCounter Word   `json:"programCounter"`
+	DataStack      []Word `json:"dataStack"`
+	ReturnStack    []Word `json:"returnStack"`
+	Mailbox        []Word `json:"mailbox"`
+	Receiving      bool   `json:"receiving"`
+}
+
+// Snapshot holds the complete state of a VM. Its JSON encoding is
+// shared with the JavaScript VM (diatom.js) so that a paused program
+// can be resumed by either of them.
+type Snapshot struct {
+	Version         int    `json:"version"`
+	ProgramCounter  Word   `json:"programCounter"`
+	DataStack       []Word `json:"dataStack"`
+	DataStackSize   int    `json:"dataStackSize"`
+	ReturnStack     []Word `json:"returnStack"`
+	ReturnStackSize int    `json:"returnStackSize"`
+	// Input holds the bytes that have been read from the input but
+	// not yet consumed by KEY.
+	Input []byte `json:"input"`
+	// Memory is stored without trailing zero bytes, MemorySize is the
+	// size of the whole memory.
+	Memory     []byte        `json:"memory"`
+	MemorySize int           `json:"memorySize"`
+	Extensions []ExtensionID `json:"extensions"`
+	// Tasks is empty until the program spawns a task. The registers
+	// above belong to the task at CurrentTask.
+	Tasks       []TaskSnapshot `json:"tasks,omitempty"`
+	CurrentTask int            `json:"currentTask,omitempty"`
+	NextTaskID  Word           `json:"nextTaskId,omitempty"`
+}
+
+// Snapshot captures the state of the VM between two instructions.
+// Execution trace, profile and source map are not part of it.
+func (vm *VM) Snapshot() Snapshot {
+	end := len(vm.memory)
+	for end > 0 && vm.memory[end-1] == 0 {
+		end--
+	}
+
+	snapshot := Snapshot{
+		Version:         SnapshotVersion,
+		ProgramCounter:  vm.programCounter,
+		DataStack:       vm.dataStack.Slice(),
+		DataStackSize:   len(vm.dataStack.data),
+		ReturnStack:     vm.returnStack.Slice(),
+		ReturnStackSize: len(vm.returnStack.data),
+		Input:           slices.Clone(vm.inputBuffer.buffer[vm.inputBuffer.cursor:vm.inputBuffer.len]),
+		Memory:          slices.Clone(vm.memory[:end]),
+		MemorySize:      len(vm.memory),
+		Extensions:      slices.Clone(vm.extensionIDs),
+	}
+
+	if vm.scheduler.active() {
+		vm.saveTask(vm.currentTask())
+		for _, t := range vm.scheduler.tasks {
+			snapshot.Tasks = append(snapshot.Tasks, TaskSnapshot{
+				ID:             t.id,
+				ProgramCounter: t.programCounter,
+				DataStack:      t.dataStack.Slice(),
+				ReturnStack:    t.returnStack.Slice(),
+				Mailbox:        slices.Clone(t.mailbox),
+				Receiving:      t.receiving,
+			})
+		}
+		snapshot.CurrentTask = vm.scheduler.current
+		snapshot.NextTaskID = vm.scheduler.nextID
+	}
+
+	return snapshot
+}
+
+func (vm *VM) restoreTasks(s Snapshot) error {
+	if len(s.Tasks) == 0 {
+		return nil
+	}
+	if s.CurrentTask < 0 || s.CurrentTask >= len(s.Tasks) {
+		return fmt.Errorf("current task %d doesn't exist", s.CurrentTask)
+	}
+
+	for i, ts := range s.Tasks {
+		t := &task{
+			id:             ts.ID,
+			programCounter: ts.ProgramCounter,
+			dataStack:      newNamedStack("data", s.DataStackSize),
+			returnStack:    newNamedStack("return", s.ReturnStackSize),
+			mailbox:        slices.Clone(ts.Mailbox),
+			receiving:      ts.Receiving,
+		}
+		if i == s.CurrentTask {
+			vm.saveTask(t)
+		} else {
+			if err := restoreStack(&t.dataStack, ts.DataStack); err != nil {
+				return fmt.Errorf("task %d: %w", ts.ID, err)
+			}
+			if err := restoreStack(&t.returnStack, ts.ReturnStack); err != nil {
+				return fmt.Errorf("task %d: %w", ts.ID, err)
+			}
+		}
+		vm.scheduler.tasks = append(vm.scheduler.tasks, t)
+	}
+	vm.scheduler.current = s.CurrentTask
+	vm.scheduler.nextID = s.NextTaskID
+
+	return nil
+}
+
+func restoreStack(s *Stack, values []Word) error {
+	// Push refuses to fill the last slot of a stack.
+	if len(values) >= len(s.data) {
+		return fmt.Errorf("%s stack holds %d values but its size is %d",
+			s.name, len(values), len(s.data))
+	}
+
+	s.cursor = copy(s.data, values)
+	return nil
+}
+
+// RestoreVM creates a VM from a snapshot that continues at the
+// instruction where the snapshot has been taken. All extensions
+// recorded in the snapshot have to be passed in again with the same
+// address, name and number of functions.
+func RestoreVM(s Snapshot, input io.Reader, output io.Writer,
+	extensions ...Extension) (*VM, error) {
+	if s.Version != SnapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d",
+			s.Version, SnapshotVersion)
+	}
+	if len(s.Input) > IOBufferSize {
+		return nil, fmt.Errorf("snapshot input (%d bytes) exceeds the input buffer (%d bytes)",
+			len(s.Input), IOBufferSize)
+	}
+
+	opts := VMOptions{
+		MemorySize:      s.MemorySize,
+		DataStackSize:   s.DataStackSize,
+		ReturnStackSize: s.ReturnStackSize,
+	}
+	if opts.MemorySize <= 0 {
+		return nil, fmt.Errorf("invalid snapshot memory size %d", opts.MemorySize)
+	}
+
+	vm, err := NewVMWithOptions(s.Memory, input, output, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore VM from snapshot: %w", err)
+	}
+	vm.programCounter = s.ProgramCounter
+	if err := restoreStack(&vm.dataStack, s.DataStack); err != nil {
+		return nil, fmt.Errorf("failed to restore VM from snapshot: %w", err)
+	}
+	if err := restoreStack(&vm.returnStack, s.ReturnStack); err != nil {
+		return nil, fmt.Errorf("failed to restore VM from snapshot: %w", err)
+	}
+	vm.inputBuffer.len = copy(vm.inputBuffer.buffer[:], s.Input)
+	if err := vm.restoreTasks(s); err != nil {
+		return nil, fmt.Errorf("failed to restore VM from snapshot: %w", err)
+	}
+
+	for _, ext := range extensions {
+		if err := vm.RegisterExtension(ext); err != nil {
+			return nil, fmt.Errorf("failed to restore VM from snapshot: %w", err)
+		}
+	}
+	for _, want := range s.Extensions {
+		if !slices.Contains(vm.extensionIDs, want) {
+			return nil, fmt.Errorf("failed to restore VM from snapshot: extension %q with %d functions is missing at address '%d'",
+				want.Name, want.Functions, want.Addr)
+		}
+	}
+
+	return vm, nil
+}
@@ -0,0 +1,79 @@
+package diatom
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+// snapshotProgram echoes its input shifted by one character and keeps
+// a running sum in memory.
+const snapshotProgram = `
+:loop
+key dup const @sum @ + const @sum !
+const 1 + emit
+jmp @loop
+:sum
+0`
+
+func TestSnapshot(t *testing.T) {
+	_, _, program, err := Assemble(bytes.NewBufferString(snapshotProgram))
+	AssertNoError(t, err, "Assemble")
+
+	input := "HAL"
+	want := &bytes.Buffer{}
+	vm, err := NewVM(program, bytes.NewBufferString(input), want)
+	AssertNoError(t, err, "NewVM")
+	AssertNoError(t, vm.Execute(), "vm.Execute")
+
+	for steps := 0; steps < 30; steps += 7 {
+		output := &bytes.Buffer{}
+		in := bytes.NewBufferString(input)
+		vm, err := NewVM(program, in, output)
+		AssertNoError(t, err, "NewVM")
+		for range steps {
+			_, err := vm.Step()
+			AssertNoError(t, err, "vm.Step")
+		}
+
+		encoded, err := json.Marshal(vm.Snapshot())
+		AssertNoError(t, err, "json.Marshal")
+		var snapshot Snapshot
+		AssertNoError(t, json.Unmarshal(encoded, &snapshot), "json.Unmarshal")
+
+		// Input that has already been buffered is part of the snapshot
+		// and must not be read again.
+		restored, err := RestoreVM(snapshot, in, output)
+		AssertNoError(t, err, "RestoreVM")
+		AssertEquals(t, vm.ProgramCounter(), restored.ProgramCounter(), "program counter")
+		AssertEquals(t, vm.DataStack(), restored.DataStack(), "data stack")
+		AssertNoError(t, restored.Execute(), "restored.Execute")
+
+		AssertEquals(t, want.String(), output.String(), "output")
+		sum, err := restored.Memory(Word(len(program)-WordSize), WordSize)
+		AssertNoError(t, err, "restored.Memory")
+		AssertEquals(t, Word('H'+'A'+'L'), bytesToWord(sum), "sum")
+	}
+}
+
+func TestRestoreVMExtensions(t *testing.T) {
+	vm, err := NewVM([]byte{EXIT}, &bytes.Buffer{}, &bytes.Buffer{})
+	AssertNoError(t, err, "NewVM")
+	AssertNoError(t, vm.RegisterExtension(NewClockExtension()), "vm.RegisterExtension")
+	snapshot := vm.Snapshot()
+
+	_, err = RestoreVM(snapshot, &bytes.Buffer{}, &bytes.Buffer{})
+	AssertError(t, err, "RestoreVM without extension")
+
+	_, err = RestoreVM(snapshot, &bytes.Buffer{}, &bytes.Buffer{}, NewRandomExtension(1))
+	AssertError(t, err, "RestoreVM with other extension")
+
+	_, err = RestoreVM(snapshot, &bytes.Buffer{}, &bytes.Buffer{}, NewClockExtension())
+	AssertNoError(t, err, "RestoreVM")
+
+	snapshot.Version = SnapshotVersion + 1
+	_, err = RestoreVM(snapshot, &bytes.Buffer{}, &bytes.Buffer{}, NewClockExtension())
+	AssertError(t, err, "RestoreVM with unknown version")
+}
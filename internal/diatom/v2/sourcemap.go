@@ -0,0 +1,137 @@
+package diatom
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// SourceLocation describes where the code at an address has been
+// declared in the assembly source.
+type SourceLocation struct {
+	File     string `json:"file,omitempty"`
+	Line     uint   `json:"line"`
+	Codeword string `json:"codeword,omitempty"`
+	Label    string `json:"label,omitempty"`
+}
+
+func (l SourceLocation) String() string {
+	s := "line " + strconv.Itoa(int(l.Line))
+	if l.File != "" {
+		s = l.File + ":" + strconv.Itoa(int(l.Line))
+	}
+
+	if l.Codeword != "" {
+		s += " in " + l.Codeword
+	}
+	if l.Label != "" && l.Label != l.Codeword {
+		s += " at :" + l.Label
+	}
+
+	return s
+}
+
+type SourceMapEntry struct {
+	Address Word `json:"address"`
+	SourceLocation
+}
+
+// SourceMap maps addresses of the assembled machine code back to the
+// source they have been generated from. Each entry covers all
+// addresses up to the next entry.
+type SourceMap struct {
+	Entries []SourceMapEntry `json:"entries"`
+	Size    Word             `json:"size"`
+}
+
+func (m *SourceMap) add(addr Word, location SourceLocation) {
+	if len(m.Entries) > 0 && m.Entries[len(m.Entries)-1].SourceLocation == location {
+		return
+	}
+
+	m.Entries = append(m.Entries, SourceMapEntry{Address: addr, SourceLocation: location})
+}
+
+// Lookup returns the source location of the code at addr.
+func (m *SourceMap) Lookup(addr Word) (SourceLocation, bool) {
+	if m == nil || addr < 0 || addr >= m.Size {
+		return SourceLocation{}, false
+	}
+
+	i := sort.Search(len(m.Entries), func(i int) bool {
+		return m.Entries[i].Address > addr
+	})
+	if i == 0 {
+		return SourceLocation{}, false
+	}
+
+	return m.Entries[i-1].SourceLocation, true
+}
+
+// Describe returns addr together with its source location if it is
+// known.
+func (m *SourceMap) Describe(addr Word) string {
+	location, ok := m.Lookup(addr)
+	if !ok {
+		return strconv.Itoa(int(addr))
+	}
+
+	return fmt.Sprintf("%d (%s)", addr, location)
+}
+
+// sourceMapWriter passes the expanded assembly through while keeping
+// track of the address every token will end up at, using the same
+// rules as readLabels.
+type sourceMapWriter struct {
+	w         io.Writer
+	asm       *assembler
+	file      string
+	sourceMap *SourceMap
+	token     []byte
+	comment   bool
+	label     string
+}
+
+func (w *sourceMapWriter) Write(p []byte) (int, error) {
+	for _, b := range p {
+		if b < 33 {
+			w.flush()
+		} else {
+			w.token = append(w.token, b)
+		}
+	}
+
+	return w.w.Write(p)
+}
+
+func (w *sourceMapWriter) flush() {
+	token := string(w.token)
+	w.token = w.token[:0]
+
+	switch {
+	case token == "":
+	case w.comment:
+		w.comment = token != ")"
+	case token == "(":
+		w.comment = true
+	case len(token) > 1 && token[0] == ':':
+		if label := token[1:]; !w.asm.generatedLabel(label) {
+			w.label = label
+		}
+	case len(token) > 1 && token[0] == '@':
+		w.advance(WordSize)
+	default:
+		w.advance(1)
+	}
+}
+
+func (w *sourceMapWriter) advance(size Word) {
+	w.sourceMap.add(w.sourceMap.Size, SourceLocation{
+		File:     w.file,
+		Line:     w.asm.scanner.start.line + 1,
+		Codeword: w.asm.codeword,
+		Label:    w.label,
+	})
+	w.sourceMap.Size += size
+}
@@ -0,0 +1,70 @@
+package diatom_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/eldelto/core/internal/diatom/v2"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+const sourceMapProgram = `call @fetch exit
+( Fetches from an invalid address. )
+.codeword fetch
+  .string abc .end drop
+  const 9000 @
+.end
+:other
+  ret`
+
+func TestSourceMap(t *testing.T) {
+	program, err := diatom.AssembleProgram("test.dasm",
+		bytes.NewBufferString(sourceMapProgram))
+	AssertNoError(t, err, "AssembleProgram")
+
+	_, _, dopc, err := diatom.Assemble(bytes.NewBufferString(sourceMapProgram))
+	AssertNoError(t, err, "Assemble")
+	AssertEquals(t, dopc, program.Dopc, "machine code")
+	AssertEquals(t, diatom.Word(len(dopc)), program.SourceMap.Size, "source map size")
+
+	fetch := program.Labels["fetch"]
+	other := program.Labels["other"]
+
+	tests := []struct {
+		addr diatom.Word
+		want diatom.SourceLocation
+	}{
+		{0, diatom.SourceLocation{File: "test.dasm", Line: 1}},
+		{5, diatom.SourceLocation{File: "test.dasm", Line: 1}},
+		{fetch - 1, diatom.SourceLocation{File: "test.dasm", Line: 3, Codeword: "fetch"}},
+		{fetch, diatom.SourceLocation{File: "test.dasm", Line: 4, Codeword: "fetch", Label: "fetch"}},
+		{other - 2, diatom.SourceLocation{File: "test.dasm", Line: 5, Codeword: "fetch", Label: "fetch"}},
+		{other - 1, diatom.SourceLocation{File: "test.dasm", Line: 6, Codeword: "fetch", Label: "fetch"}},
+		{other, diatom.SourceLocation{File: "test.dasm", Line: 8, Label: "other"}},
+	}
+
+	for _, tt := range tests {
+		location, ok := program.SourceMap.Lookup(tt.addr)
+		AssertEquals(t, true, ok, "location found")
+		AssertEquals(t, tt.want, location, "location")
+	}
+
+	_, ok := program.SourceMap.Lookup(program.SourceMap.Size)
+	AssertEquals(t, false, ok, "location after the end found")
+}
+
+func TestSymbolicErrors(t *testing.T) {
+	program, err := diatom.AssembleProgram("test.dasm",
+		bytes.NewBufferString(sourceMapProgram))
+	AssertNoError(t, err, "AssembleProgram")
+
+	vm, err := diatom.NewVM(program.Dopc, &bytes.Buffer{}, &bytes.Buffer{})
+	AssertNoError(t, err, "NewVM")
+	vm.SetSourceMap(program.SourceMap)
+
+	err = vm.Execute()
+	AssertError(t, err, "vm.Execute")
+
+	AssertStringContains(t, "(test.dasm:5 in fetch) address=9000", err.Error(), "error")
+}
@@ -0,0 +1,106 @@
+package diatom
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+func TestInterpreter(t *testing.T) {
+	tests := []struct {
+		program       string
+		wantDataStack []Word
+		wantOutput    string
+	}{
+		// Words
+		{": test word b@ ; test dup", []Word{3}, ""},
+		{": test word find ; test dup", []Word{75}, ""},
+		{": test word find ; test nope", []Word{0}, ""},
+		{": test word find word.code ; 7 test dup unsafe.stack-call", []Word{7, 7}, ""},
+		{": test word number ; test -42", []Word{-42}, ""},
+		{"1 ( 2 3 ) 4", []Word{1, 4}, ""},
+		{": a ( x -- x ) 1 + ; 1 a", []Word{2}, ""},
+		{": A 65 emit ; immediate : b A ; b", []Word{}, "A"},
+		{": A immediate 65 emit ; : b A A ; b", []Word{}, "AA"},
+
+		// Conditionals
+		{": test if 11 then 22 ; true test", []Word{11, 22}, ""},
+		{": test if 11 then 22 ; false test", []Word{22}, ""},
+		{": test if 11 then 22 ; 5 test", []Word{11, 22}, ""},
+		{": test if 11 else 22 then 33 ; true test", []Word{11, 33}, ""},
+		{": test if 11 else 22 then 33 ; false test", []Word{22, 33}, ""},
+		{": test if if 1 else 2 then else 3 then ; true true test false true test false test",
+			[]Word{1, 2, 3}, ""},
+
+		// Loops
+		{": test 0 begin 1 + dup 5 = until ; test", []Word{5}, ""},
+		{": test begin dup emit 1 + dup 68 > until drop ; 65 test", []Word{}, "ABCD"},
+
+		// Strings
+		{`." hello world"`, []Word{}, "hello world"},
+		{`: test ." hi" 1 ; test test`, []Word{1, 1}, "hihi"},
+		{`: test s" abc" ; test b@ test 3 + b@`, []Word{3, 98}, ""},
+		{`s" abc" b@`, []Word{3}, ""},
+		{`: test ." a" if ." b" else ." c" then ; true test false test`, []Word{}, "abac"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.program, func(t *testing.T) {
+			repl, err := AssembleRepl(VMOptions{})
+			AssertNoError(t, err, "AssembleRepl")
+
+			output := &bytes.Buffer{}
+			vm, err := NewVM(repl.Dopc, strings.NewReader(tt.program+"\n"), output)
+			AssertNoError(t, err, "NewVM")
+
+			AssertNoError(t, vm.Execute(), "vm.Execute")
+			AssertEquals(t, tt.wantDataStack, vm.DataStack(), "vm.DataStack")
+			AssertEquals(t, tt.wantOutput, output.String(), "output")
+		})
+	}
+}
+
+func TestStdlib(t *testing.T) {
+	tests := []struct {
+		program       string
+		wantDataStack []Word
+		wantOutput    string
+	}{
+		// Fundamental words
+		{"' drop ' dup =", []Word{0}, ""},
+		{": l4 [ 4 ] literal ; l4 l4 +", []Word{8}, ""},
+		{": A immediate 65 emit ; : test [compile] A ; test test", []Word{}, "AA"},
+		{": a immediate ref dup postpone ; : test 5 a ; test", []Word{5, 5}, ""},
+		{"5 1+ 1-", []Word{5}, ""},
+		{"1 2 <> 2 2 <>", []Word{-1, 0}, ""},
+		{".s 1 -2 30 .s", []Word{1, -2, 30}, "<0>\n<3> 1 -2 30\n"},
+
+		// Control flow
+		{": test if 65 emit false recurse then ; true test", []Word{}, "A"},
+		{": test 11 return 22 ; test", []Word{11}, ""},
+		{": a 65 emit ; ' a 5 times", []Word{}, "AAAAA"},
+		{": a dup emit ; 65 ' a 5 times", []Word{65}, "AAAAA"},
+		{": a 66 emit ; ' a 0 times", []Word{}, ""},
+
+		// Math
+		{"0 factorial 1 factorial 5 factorial", []Word{1, 1, 120}, ""},
+
+		// Memory
+		{"word.here @ 3 mem-view", []Word{}, "0\n0\n0\n"},
+		{": x 1 ; 2 mem-tail", []Word{}, "1\n2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.program, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			vm, err := WithStdlib(tt.program, &bytes.Buffer{}, output)
+			AssertNoError(t, err, "WithStdlib")
+
+			AssertNoError(t, vm.Execute(), "vm.Execute")
+			AssertEquals(t, tt.wantDataStack, vm.DataStack(), "vm.DataStack")
+			AssertEquals(t, tt.wantOutput, output.String(), "output")
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package diatom
+
+import (
+	"slices"
+)
+
+// TaskExtensionAddr is the module address of the task extension.
+const TaskExtensionAddr = 5
+
+// MailboxSize is the number of messages a task can hold before
+// task.send fails.
+const MailboxSize = 32
+
+// task holds the registers of a task while another one is running.
+// The registers of the running task live in the VM itself.
+type task struct {
+	id             Word
+	programCounter Word
+	dataStack      Stack
+	returnStack    Stack
+	mailbox        []Word
+	// receiving is set while the task waits for a message.
+	receiving bool
+}
+
+func (t *task) runnable() bool {
+	return !t.receiving || len(t.mailbox) > 0
+}
+
+// scheduler switches between tasks in round-robin order. It stays
+// empty until the first task is spawned so that single-task programs
+// don't pay for it.
+type scheduler struct {
+	tasks   []*task
+	current int
+	nextID  Word
+	// switchRequested makes the VM switch to the next task once the
+	// current instruction is done.
+	switchRequested bool
+}
+
+func (s *scheduler) active() bool {
+	return len(s.tasks) > 0
+}
+
+func (vm *VM) currentTask() *task {
+	if !vm.scheduler.active() {
+		vm.scheduler.tasks = []*task{{id: 0}}
+		vm.scheduler.nextID = 1
+	}
+
+	return vm.scheduler.tasks[vm.scheduler.current]
+}
+
+func (vm *VM) saveTask(t *task) {
+	t.programCounter = vm.programCounter
+	t.dataStack = vm.dataStack
+	t.returnStack = vm.returnStack
+}
+
+func (vm *VM) loadTask(t *task) error {
+	vm.programCounter = t.programCounter
+	vm.dataStack = t.dataStack
+	vm.returnStack = t.returnStack
+
+	if t.receiving {
+		t.receiving = false
+		msg := t.mailbox[0]
+		t.mailbox = t.mailbox[1:]
+		return vm.dataStack.Push(msg)
+	}
+
+	return nil
+}
+
+// switchTask continues with the next runnable task after the current
+// one. The current task is removed if it has finished.
+func (vm *VM) switchTask(finished bool) error {
+	s := &vm.scheduler
+	s.switchRequested = false
+
+	current := s.tasks[s.current]
+	if finished {
+		s.tasks = slices.Delete(s.tasks, s.current, s.current+1)
+		s.current--
+	} else {
+		vm.saveTask(current)
+	}
+
+	for i := 1; i <= len(s.tasks); i++ {
+		next := (s.current + i) % len(s.tasks)
+		if s.tasks[next].runnable() {
+			s.current = next
+			return vm.loadTask(s.tasks[next])
+		}
+	}
+
+	waiting := make([]Word, len(s.tasks))
+	for i, t := range s.tasks {
+		waiting[i] = t.id
+	}
+	return &DeadlockError{Tasks: waiting}
+}
+
+// schedule is called after every instruction and reports if the
+// program has finished. A finished task only ends the program if it
+// has been the last one.
+func (vm *VM) schedule(done bool) (bool, error) {
+	if !vm.scheduler.active() {
+		return done, nil
+	}
+	if done {
+		if len(vm.scheduler.tasks) == 1 {
+			return true, nil
+		}
+		return false, vm.switchTask(true)
+	}
+	if vm.scheduler.switchRequested {
+		return false, vm.switchTask(false)
+	}
+
+	return false, nil
+}
+
+func (vm *VM) findTask(id Word) *task {
+	for _, t := range vm.scheduler.tasks {
+		if t.id == id {
+			return t
+		}
+	}
+	return nil
+}
+
+// NewTaskExtension returns the functions
+//
+//	task.spawn   ( exit ptr -- id )
+//	task.yield   ( -- )
+//	task.send    ( x id -- ior )
+//	task.receive ( -- x )
+//	task.id      ( -- id )
+//
+// for cooperative multitasking. All tasks share the memory but have
+// their own stacks. task.spawn starts a task at ptr which returns to
+// exit once done, the preamble passes code that runs the exit
+// instruction. Tasks only switch on task.yield, on task.receive with
+// an empty mailbox and once they exit. The program exits with the last
+// task and fails if all remaining tasks wait for messages.
+func NewTaskExtension() Extension {
+	return Extension{
+		Addr: TaskExtensionAddr,
+		Functions: []ExtensionFunc{
+			func(vm *VM) error {
+				args, err := vm.popWords(2)
+				if err != nil {
+					return err
+				}
+				vm.currentTask()
+
+				s := &vm.scheduler
+				t := &task{
+					id:             s.nextID,
+					programCounter: args[1],
+					dataStack:      newNamedStack("data", len(vm.dataStack.data)),
+					returnStack:    newNamedStack("return", len(vm.returnStack.data)),
+				}
+				if err := t.returnStack.Push(args[0]); err != nil {
+					return err
+				}
+				s.nextID++
+				s.tasks = append(s.tasks, t)
+
+				return vm.dataStack.Push(t.id)
+			},
+			func(vm *VM) error {
+				vm.currentTask()
+				vm.scheduler.switchRequested = true
+				return nil
+			},
+			func(vm *VM) error {
+				args, err := vm.popWords(2)
+				if err != nil {
+					return err
+				}
+				vm.currentTask()
+
+				t := vm.findTask(args[1])
+				if t == nil || len(t.mailbox) >= MailboxSize {
+					return vm.dataStack.Push(iorFailure)
+				}
+				t.mailbox = append(t.mailbox, args[0])
+				return vm.dataStack.Push(iorSuccess)
+			},
+			func(vm *VM) error {
+				t := vm.currentTask()
+				if len(t.mailbox) > 0 {
+					msg := t.mailbox[0]
+					t.mailbox = t.mailbox[1:]
+					return vm.dataStack.Push(msg)
+				}
+
+				t.receiving = true
+				vm.scheduler.switchRequested = true
+				return nil
+			},
+			func(vm *VM) error {
+				return vm.dataStack.Push(vm.currentTask().id)
+			},
+		},
+		Name: "Task",
+	}
+}
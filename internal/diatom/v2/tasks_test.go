@@ -0,0 +1,84 @@
+package diatom
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+)
+
+func TestTasks(t *testing.T) {
+	tests := []struct {
+		program       string
+		wantDataStack []Word
+		wantOutput    string
+	}{
+		{"task.id", []Word{0}, ""},
+		{": a 65 emit task.yield 65 emit ; : b 66 emit task.yield 66 emit ; " +
+			"' a task.spawn ' b task.spawn task.yield 67 emit", []Word{}, "ABCAB"},
+		{": a task.id ; ' a task.spawn ' a task.spawn", []Word{2}, ""},
+		{": doubler task.receive 2 * 0 task.send drop ; " +
+			"' doubler task.spawn 21 swap task.send task.receive", []Word{0, 42}, ""},
+		{": echo begin task.receive dup emit 0 = until ; ' echo task.spawn " +
+			"dup 72 swap task.send drop dup 73 swap task.send drop 0 swap task.send",
+			[]Word{}, "HI\x00"},
+		{"1 7 task.send", []Word{-1}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.program, func(t *testing.T) {
+			output := &bytes.Buffer{}
+			vm, err := WithStdlib(tt.program, &bytes.Buffer{}, output)
+			AssertNoError(t, err, "WithStdlib")
+			AssertNoError(t, vm.RegisterExtension(NewTaskExtension()), "vm.RegisterExtension")
+
+			AssertNoError(t, vm.Execute(), "vm.Execute")
+			AssertEquals(t, tt.wantDataStack, vm.DataStack(), "vm.DataStack")
+			AssertEquals(t, tt.wantOutput, output.String(), "output")
+		})
+	}
+}
+
+func TestTaskDeadlock(t *testing.T) {
+	vm, err := WithStdlib(": a task.receive ; ' a task.spawn drop task.receive",
+		&bytes.Buffer{}, &bytes.Buffer{})
+	AssertNoError(t, err, "WithStdlib")
+	AssertNoError(t, vm.RegisterExtension(NewTaskExtension()), "vm.RegisterExtension")
+
+	err = vm.Execute()
+	var deadlock *DeadlockError
+	AssertEquals(t, true, errors.As(err, &deadlock), "errors.As DeadlockError")
+	AssertEquals(t, []Word{0, 1}, deadlock.Tasks, "waiting tasks")
+}
+
+func TestTaskSnapshot(t *testing.T) {
+	program := ": a 65 emit task.yield 65 emit ; ' a task.spawn drop task.yield 66 emit"
+
+	want := &bytes.Buffer{}
+	vm, err := WithStdlib(program, &bytes.Buffer{}, want)
+	AssertNoError(t, err, "WithStdlib")
+	AssertNoError(t, vm.RegisterExtension(NewTaskExtension()), "vm.RegisterExtension")
+	AssertNoError(t, vm.Execute(), "vm.Execute")
+
+	output := &bytes.Buffer{}
+	vm, err = WithStdlib(program, &bytes.Buffer{}, output)
+	AssertNoError(t, err, "WithStdlib")
+	AssertNoError(t, vm.RegisterExtension(NewTaskExtension()), "vm.RegisterExtension")
+	for output.Len() == 0 {
+		_, err := vm.Step()
+		AssertNoError(t, err, "vm.Step")
+	}
+
+	encoded, err := json.Marshal(vm.Snapshot())
+	AssertNoError(t, err, "json.Marshal")
+	var snapshot Snapshot
+	AssertNoError(t, json.Unmarshal(encoded, &snapshot), "json.Unmarshal")
+	AssertEquals(t, 2, len(snapshot.Tasks), "tasks")
+
+	restored, err := RestoreVM(snapshot, &bytes.Buffer{}, output, NewTaskExtension())
+	AssertNoError(t, err, "RestoreVM")
+	AssertNoError(t, restored.Execute(), "restored.Execute")
+	AssertEquals(t, want.String(), output.String(), "output")
+}
@@ -1,7 +1,6 @@
 package diatom
 
 import (
-	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -20,13 +19,24 @@ const (
 
 type Stack struct {
 	cursor int
-	data   [StackSize]Word
+	data   []Word
+	// name is used in errors to tell the data and return stack apart.
+	name string
+}
+
+func NewStack(size int) Stack {
+	return Stack{data: make([]Word, size)}
+}
+
+func newNamedStack(name string, size int) Stack {
+	s := NewStack(size)
+	s.name = name
+	return s
 }
 
 func (s *Stack) Push(value Word) error {
 	if s.cursor+1 >= len(s.data) {
-		return fmt.Errorf("push: stack overflow - cursor: %d, stack size: %d",
-			s.cursor, len(s.data))
+		return &StackOverflowError{Stack: s.name, Size: len(s.data)}
 	}
 
 	s.data[s.cursor] = value
@@ -36,8 +46,7 @@ func (s *Stack) Push(value Word) error {
 
 func (s *Stack) Pop() (Word, error) {
 	if s.cursor <= 0 {
-		return 0, fmt.Errorf("pop: stack underflow - cursor: %d, stack size: %d",
-			s.cursor, len(s.data))
+		return 0, &StackUnderflowError{Stack: s.name}
 	}
 
 	s.cursor--
@@ -46,13 +55,29 @@ func (s *Stack) Pop() (Word, error) {
 
 func (s *Stack) Peek() (Word, error) {
 	if s.cursor <= 0 {
-		return 0, fmt.Errorf("peek: stack underflow - cursor: %d, stack size: %d",
-			s.cursor, len(s.data))
+		return 0, &StackUnderflowError{Stack: s.name}
 	}
 
 	return s.data[s.cursor-1], nil
 }
 
+// Len returns the number of values on the stack.
+func (s *Stack) Len() int {
+	return s.cursor
+}
+
+// Slice returns a copy of the stack's values from bottom to top.
+func (s *Stack) Slice() []Word {
+	return slices.Clone(s.data[:s.cursor])
+}
+
+// copyFrom overwrites the stack with the values of other while
+// reusing the already allocated memory.
+func (s *Stack) copyFrom(other *Stack) {
+	s.data = append(s.data[:0], other.data[:other.cursor]...)
+	s.cursor = other.cursor
+}
+
 func (s *Stack) String() string {
 	b := strings.Builder{}
 
@@ -94,7 +119,10 @@ type traceEntry struct {
 	returnStack    Stack
 }
 
-type ExtensionFunc func(vm *VM)
+// ExtensionFunc implements a function that can be called with EXCALL.
+// It works directly on the stacks and memory of the VM and aborts the
+// program by returning an error.
+type ExtensionFunc func(vm *VM) error
 
 type Extension struct {
 	Addr      uint16
@@ -109,25 +137,108 @@ type VM struct {
 	inputBuffer    Input
 	input          io.Reader
 	output         io.Writer
-	memory         [MemorySize]byte
+	memory         []byte
+	traceEnabled   bool
 	executionTrace collections.RingBuffer[traceEntry]
 	extensions     map[Word]ExtensionFunc
+	extensionIDs   []ExtensionID
+	sourceMap      *SourceMap
+	profile        *Profile
+	scheduler      scheduler
+}
+
+// VMOptions configures the resources of a VM. Fields left at zero
+// fall back to the default sizes. A negative TraceLength disables the
+// execution trace.
+type VMOptions struct {
+	MemorySize      int
+	DataStackSize   int
+	ReturnStackSize int
+	TraceLength     int
+	// Profile enables counting the executed instructions, see
+	// VM.Profile.
+	Profile bool
+}
+
+func DefaultVMOptions() VMOptions {
+	return VMOptions{
+		MemorySize:      MemorySize,
+		DataStackSize:   StackSize,
+		ReturnStackSize: StackSize,
+		TraceLength:     StackSize,
+	}
+}
+
+func (o VMOptions) withDefaults() VMOptions {
+	defaults := DefaultVMOptions()
+	if o.MemorySize == 0 {
+		o.MemorySize = defaults.MemorySize
+	}
+	if o.DataStackSize == 0 {
+		o.DataStackSize = defaults.DataStackSize
+	}
+	if o.ReturnStackSize == 0 {
+		o.ReturnStackSize = defaults.ReturnStackSize
+	}
+	if o.TraceLength == 0 {
+		o.TraceLength = defaults.TraceLength
+	}
+
+	return o
+}
+
+// validate expects the defaults to be applied already so zero sizes
+// are rejected like any other invalid size.
+func (o VMOptions) validate() error {
+	if o.MemorySize < 1 || o.MemorySize > WordMax {
+		return fmt.Errorf("memory size must be between 1 and %d bytes but is %d",
+			WordMax, o.MemorySize)
+	}
+	if o.DataStackSize < 2 {
+		return fmt.Errorf("data stack size must be at least 2 but is %d",
+			o.DataStackSize)
+	}
+	if o.ReturnStackSize < 2 {
+		return fmt.Errorf("return stack size must be at least 2 but is %d",
+			o.ReturnStackSize)
+	}
+
+	return nil
 }
 
 func NewVM(program []byte, input io.Reader, output io.Writer) (*VM, error) {
+	return NewVMWithOptions(program, input, output, VMOptions{})
+}
+
+func NewVMWithOptions(program []byte, input io.Reader, output io.Writer,
+	opts VMOptions) (*VM, error) {
+	opts = opts.withDefaults()
+	if err := opts.validate(); err != nil {
+		return nil, fmt.Errorf("invalid VM options: %w", err)
+	}
+
 	programLen := len(program)
-	if programLen > MemorySize {
+	if programLen > opts.MemorySize {
 		return nil, fmt.Errorf("program length (%d bytes) exceeds available memory (%d bytes)",
-			programLen, MemorySize)
+			programLen, opts.MemorySize)
 	}
 
 	vm := VM{
-		input:          input,
-		output:         output,
-		executionTrace: collections.NewRingBuffer[traceEntry](StackSize),
-		extensions:     map[Word]ExtensionFunc{},
+		dataStack:   newNamedStack("data", opts.DataStackSize),
+		returnStack: newNamedStack("return", opts.ReturnStackSize),
+		input:       input,
+		output:      output,
+		memory:      make([]byte, opts.MemorySize),
+		extensions:  map[Word]ExtensionFunc{},
+	}
+	if opts.TraceLength > 0 {
+		vm.traceEnabled = true
+		vm.executionTrace = collections.NewRingBuffer[traceEntry](opts.TraceLength)
+	}
+	if opts.Profile {
+		vm.profile = newProfile(&vm)
 	}
-	copy(vm.memory[:], program)
+	copy(vm.memory, program)
 
 	return &vm, nil
 }
@@ -147,25 +258,38 @@ func (vm *VM) RegisterExtension(ext Extension) error {
 
 		vm.extensions[addr] = extFunc
 	}
+	vm.extensionIDs = append(vm.extensionIDs, ext.id())
 
 	return nil
 }
 
+// Profile returns the instructions counted so far if profiling has
+// been enabled in the VMOptions and nil otherwise.
+func (vm *VM) Profile() *Profile {
+	return vm.profile
+}
+
+// SetSourceMap makes errors and stack traces refer to the source
+// locations of the program.
+func (vm *VM) SetSourceMap(m *SourceMap) {
+	vm.sourceMap = m
+}
+
 func (vm *VM) appendTraceEntry(instruction byte) {
-	entry := traceEntry{
-		programCounter: vm.programCounter,
-		instruction:    instruction,
-		dataStack:      vm.dataStack,
-		returnStack:    vm.returnStack,
+	if !vm.traceEnabled {
+		return
 	}
 
-	vm.executionTrace.Append(entry)
+	entry := vm.executionTrace.Next()
+	entry.programCounter = vm.programCounter
+	entry.instruction = instruction
+	entry.dataStack.copyFrom(&vm.dataStack)
+	entry.returnStack.copyFrom(&vm.returnStack)
 }
 
 func (vm *VM) validateMemoryAccess(addr Word) error {
 	if addr >= Word(len(vm.memory)) || addr < 0 {
-		return fmt.Errorf("out of bound memory access: programCounter=%d address=%d",
-			vm.programCounter, addr)
+		return &MemoryAccessError{Address: addr}
 	}
 
 	return nil
@@ -282,377 +406,221 @@ func multiply(a, b Word) Word {
 	}
 }
 
-func (vm *VM) execute() error {
-	for {
-		instruction := vm.memory[vm.programCounter]
-		vm.appendTraceEntry(instruction)
+// step executes the instruction at the current program counter and
+// reports if the program has finished.
+func (vm *VM) step() (bool, error) {
+	pc := vm.programCounter
+	done, err := vm.execInstruction()
+	if err == nil {
+		done, err = vm.schedule(done)
+	}
+	if err != nil {
+		return false, vm.locate(err, pc)
+	}
 
-		switch instruction {
-		case ABORT:
-			return fmt.Errorf("tried to execute uninitialized memory at address %d - aborting", vm.programCounter)
-		case EXIT:
-			return nil
-		case RET:
-			addr, err := vm.returnStack.Pop()
-			if err != nil {
-				return err
-			}
-			vm.programCounter = addr
-			continue
-		case JMP:
-			vm.programCounter++
-			addr, err := vm.fetchWord(vm.programCounter)
-			if err != nil {
-				return err
-			}
-			vm.programCounter = addr
-			continue
-		case CJMP:
-			vm.programCounter++
-			conditional, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
+	return done, nil
+}
 
-			if conditional == 0 {
-				vm.programCounter += WordSize
-			} else {
-				vm.programCounter, err = vm.fetchWord(vm.programCounter)
-				if err != nil {
-					return err
-				}
-			}
-			continue
-		case CALL:
-			vm.programCounter++
-			if err := vm.returnStack.Push(vm.programCounter + WordSize); err != nil {
-				return err
-			}
+func (vm *VM) execInstruction() (bool, error) {
+	instruction, err := vm.fetchByte(vm.programCounter)
+	if err != nil {
+		return false, err
+	}
+	vm.appendTraceEntry(instruction)
+	if vm.profile != nil {
+		vm.profile.record(instruction)
+	}
 
-			target, err := vm.fetchWord(vm.programCounter)
-			if err != nil {
-				return err
-			}
-			vm.programCounter = target
-			continue
-		case EXCALL:
-			addr, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			extFunc, ok := vm.extensions[addr]
-			if !ok {
-				return fmt.Errorf("extension function at address '%d' not found",
-					addr)
-			}
-			extFunc(vm)
+	m := Machine{vm: vm}
+	switch instruction {
+	case ABORT:
+		return false, &AbortError{Trace: vm.StackTrace()}
+	case EXIT:
+		return true, nil
+	case RET:
+		addr, err := m.PopReturn()
+		if err != nil {
+			return false, err
+		}
+		vm.programCounter = addr
+		return false, nil
+	case JMP:
+		vm.programCounter++
+		addr, err := vm.fetchWord(vm.programCounter)
+		if err != nil {
+			return false, err
+		}
+		vm.programCounter = addr
+		return false, nil
+	case CJMP:
+		vm.programCounter++
+		conditional, err := m.Pop()
+		if err != nil {
+			return false, err
+		}
 
-		case CONST:
-			vm.programCounter++
-			w, err := vm.fetchWord(vm.programCounter)
+		if conditional == 0 {
+			vm.programCounter += WordSize
+		} else {
+			vm.programCounter, err = vm.fetchWord(vm.programCounter)
 			if err != nil {
-				return err
+				return false, err
 			}
+		}
+		return false, nil
+	case CALL:
+		vm.programCounter++
+		if err := m.PushReturn(vm.programCounter + WordSize); err != nil {
+			return false, err
+		}
 
-			if err := vm.dataStack.Push(w); err != nil {
-				return err
-			}
+		target, err := vm.fetchWord(vm.programCounter)
+		if err != nil {
+			return false, err
+		}
+		vm.programCounter = target
+		return false, nil
+	case CONST:
+		vm.programCounter++
+		w, err := vm.fetchWord(vm.programCounter)
+		if err != nil {
+			return false, err
+		}
 
-			vm.programCounter += WordSize
-			continue
-		case DUP:
-			a, err := vm.dataStack.Peek()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(a); err != nil {
-				return err
-			}
-		case DROP:
-			if _, err := vm.dataStack.Pop(); err != nil {
-				return err
-			}
-		case SWAP:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(a); err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(b); err != nil {
-				return err
-			}
-		case OVER:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(b); err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(a); err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(b); err != nil {
-				return err
-			}
-		case RPUSH:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.returnStack.Push(a); err != nil {
-				return err
-			}
-		case RPOP:
-			a, err := vm.returnStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(a); err != nil {
-				return err
-			}
-		case RPEEK:
-			a, err := vm.returnStack.Peek()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(a); err != nil {
-				return err
-			}
-		case STORE:
-			addr, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			value, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.storeWord(addr, value); err != nil {
-				return err
-			}
-		case FETCH:
-			addr, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			w, err := vm.fetchWord(addr)
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(w); err != nil {
-				return err
-			}
-		case BSTORE:
-			addr, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			value, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.storeByte(addr, byte(value)); err != nil {
-				return err
-			}
-		case BFETCH:
-			addr, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.fetchByte(addr)
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(Word(b)); err != nil {
-				return err
-			}
+		if err := m.Push(w); err != nil {
+			return false, err
+		}
 
-		case ADD:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(add(b, a)); err != nil {
-				return err
-			}
-		case SUB:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(subtract(b, a)); err != nil {
-				return err
-			}
-		case MULT:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(multiply(b, a)); err != nil {
-				return err
-			}
-		case DIV:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(b / a); err != nil {
-				return err
-			}
-		case MOD:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(b % a); err != nil {
-				return err
-			}
+		vm.programCounter += WordSize
+		return false, nil
+	case KEY:
+		eof, err := m.Key()
+		if err != nil {
+			return false, err
+		}
+		if eof {
+			return true, nil
+		}
 
-		case EQ:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(boolToWord(b == a)); err != nil {
-				return err
-			}
-		case NOT:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(^a); err != nil {
-				return err
-			}
-		case AND:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(b & a); err != nil {
-				return err
-			}
-		case OR:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(b | a); err != nil {
-				return err
-			}
-		case LT:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(boolToWord(b < a)); err != nil {
-				return err
-			}
-		case GT:
-			a, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			b, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dataStack.Push(boolToWord(a < b)); err != nil {
-				return err
-			}
+	case EXCALL:
+		err = m.ExtensionCall()
+	case DUP:
+		err = m.Dup()
+	case DROP:
+		err = m.Drop()
+	case SWAP:
+		err = m.Swap()
+	case OVER:
+		err = m.Over()
+	case RPUSH:
+		err = m.RPush()
+	case RPOP:
+		err = m.RPop()
+	case RPEEK:
+		err = m.RPeek()
+	case STORE:
+		err = m.Store()
+	case FETCH:
+		err = m.Fetch()
+	case BSTORE:
+		err = m.BStore()
+	case BFETCH:
+		err = m.BFetch()
+	case ADD:
+		err = m.Add()
+	case SUB:
+		err = m.Sub()
+	case MULT:
+		err = m.Mult()
+	case DIV:
+		err = m.Div()
+	case MOD:
+		err = m.Mod()
+	case EQ:
+		err = m.Eq()
+	case NOT:
+		err = m.Not()
+	case AND:
+		err = m.And()
+	case OR:
+		err = m.Or()
+	case LT:
+		err = m.Lt()
+	case GT:
+		err = m.Gt()
+	case EMIT:
+		err = m.Emit()
+	case DUMP:
+		err = m.Dump()
+	case DEPTH:
+		err = m.Depth()
+	default:
+		return false, &UnknownInstructionError{Instruction: instruction}
+	}
+	if err != nil {
+		return false, err
+	}
 
-		case KEY:
-			b, err := vm.key()
-			if err != nil {
-				if errors.Is(err, io.EOF) {
-					return nil
-				}
-				return err
-			}
-			if err := vm.dataStack.Push(Word(b)); err != nil {
-				return err
-			}
-		case EMIT:
-			value, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.emit(byte(value)); err != nil {
-				return err
-			}
-		case DUMP:
-			endAddr, err := vm.dataStack.Pop()
-			if err != nil {
-				return err
-			}
-			if err := vm.dumpMemory("dump.dopc", endAddr); err != nil {
-				return err
-			}
-		default:
-			return fmt.Errorf("unknown instruction '%d' at memory address '%d' - terminating",
-				instruction, vm.programCounter)
+	vm.programCounter++
+	return false, nil
+}
+
+func (vm *VM) execute() error {
+	for {
+		done, err := vm.step()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
 		}
+	}
+}
 
-		vm.programCounter++
+// Step executes a single instruction and reports if the program has
+// finished.
+func (vm *VM) Step() (bool, error) {
+	return vm.step()
+}
+
+func (vm *VM) ProgramCounter() Word {
+	return vm.programCounter
+}
+
+func (vm *VM) DataStack() []Word {
+	return vm.dataStack.Slice()
+}
+
+func (vm *VM) ReturnStack() []Word {
+	return vm.returnStack.Slice()
+}
+
+// Memory returns a copy of length bytes of the VM's memory starting
+// at addr.
+func (vm *VM) Memory(addr Word, length int) ([]byte, error) {
+	if err := vm.validateMemoryAccess(addr); err != nil {
+		return nil, err
+	}
+	if err := vm.validateMemoryAccess(addr + Word(length) - 1); err != nil {
+		return nil, err
 	}
+
+	return slices.Clone(vm.memory[addr : addr+Word(length)]), nil
 }
 
 func (vm *VM) StackTrace() string {
 	b := strings.Builder{}
 
+	if !vm.traceEnabled {
+		return ""
+	}
+
 	trace := vm.executionTrace.Slice()
 	slices.Reverse(trace)
 
 	for _, entry := range trace {
 		b.WriteString("Program counter: ")
-		b.WriteString(strconv.FormatInt(int64(entry.programCounter), 10))
+		b.WriteString(vm.sourceMap.Describe(entry.programCounter))
 
 		b.WriteString("  Instruction: ")
 		b.WriteString(instructionFromOpcode(entry.instruction))
@@ -672,8 +640,12 @@ func (vm *VM) StackTrace() string {
 	return b.String()
 }
 
+// CoreDumpFile is the file the whole memory is written to by
+// CoreDump.
+const CoreDumpFile = "core-dump.dopc"
+
 func (vm *VM) CoreDump() error {
-	return vm.dumpMemory("core-dump.dopc", MemorySize-1)
+	return vm.dumpMemory(CoreDumpFile, Word(len(vm.memory)))
 }
 
 func (vm *VM) Execute() error {
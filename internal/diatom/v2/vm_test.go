@@ -2,6 +2,8 @@ package diatom
 
 import (
 	"bytes"
+	"errors"
+	"strings"
 	"testing"
 
 	. "github.com/eldelto/core/internal/testutils"
@@ -10,9 +12,12 @@ import (
 var testExtension = Extension{
 	Addr: 1,
 	Functions: []ExtensionFunc{
-		func(vm *VM) {
-			word, _ := vm.dataStack.Pop()
-			_ = vm.dataStack.Push(word * word)
+		func(vm *VM) error {
+			word, err := vm.dataStack.Pop()
+			if err != nil {
+				return err
+			}
+			return vm.dataStack.Push(word * word)
 		},
 	},
 	Name: "Test Extension",
@@ -36,6 +41,8 @@ func TestVM(t *testing.T) {
 		{"const 5 const -3 *", []Word{-15}, []Word{}, false},
 		{"const 7 const -3 /", []Word{-2}, []Word{}, false},
 		{"const 7 const -3 %", []Word{1}, []Word{}, false},
+		{"const 7 const 0 /", []Word{}, []Word{}, true},
+		{"const 7 const 0 %", []Word{}, []Word{}, true},
 		{"const 7 dup", []Word{7, 7}, []Word{}, false},
 		{"const 7 dup drop", []Word{7}, []Word{}, false},
 		{"const 7 const 2 swap", []Word{2, 7}, []Word{}, false},
@@ -63,6 +70,7 @@ func TestVM(t *testing.T) {
 		{"const 10 const 65536 excall", []Word{100}, []Word{}, false},
 		{"const 10 const 5 excall", []Word{}, []Word{}, true},
 		{"const 10 dump", []Word{}, []Word{}, false},
+		{"depth const 7 depth", []Word{0, 7, 2}, []Word{}, false},
 	}
 
 	for _, tt := range tests {
@@ -108,3 +116,115 @@ func TestVMIO(t *testing.T) {
 
 	AssertEquals(t, input, output.String(), "output")
 }
+
+func TestVMOptions(t *testing.T) {
+	_, _, program, err := Assemble(bytes.NewBufferString("const 1 const 2 const 3 exit"))
+	AssertNoError(t, err, "Assemble")
+
+	vm, err := NewVMWithOptions(program, &bytes.Buffer{}, &bytes.Buffer{},
+		VMOptions{DataStackSize: 3})
+	AssertNoError(t, err, "NewVMWithOptions")
+	AssertError(t, vm.Execute(), "vm.Execute with small data stack")
+
+	vm, err = NewVMWithOptions(program, &bytes.Buffer{}, &bytes.Buffer{},
+		VMOptions{DataStackSize: 4, TraceLength: -1})
+	AssertNoError(t, err, "NewVMWithOptions")
+	AssertNoError(t, vm.Execute(), "vm.Execute")
+	AssertEquals(t, []Word{1, 2, 3}, vm.DataStack(), "data stack")
+	AssertEquals(t, "", vm.StackTrace(), "disabled stack trace")
+
+	_, err = NewVMWithOptions(program, &bytes.Buffer{}, &bytes.Buffer{},
+		VMOptions{MemorySize: 8})
+	AssertError(t, err, "NewVMWithOptions with too little memory")
+
+	_, err = NewVMWithOptions(program, &bytes.Buffer{}, &bytes.Buffer{},
+		VMOptions{MemorySize: -1})
+	AssertError(t, err, "NewVMWithOptions with negative memory size")
+	AssertStringContains(t, "between 1 and", err.Error(), "error message")
+
+	large := make([]byte, MemorySize+1)
+	large[0] = EXIT
+	_, err = NewVM(large, &bytes.Buffer{}, &bytes.Buffer{})
+	AssertError(t, err, "NewVM with too large program")
+
+	vm, err = NewVMWithOptions(large, &bytes.Buffer{}, &bytes.Buffer{},
+		VMOptions{MemorySize: 2 * MemorySize})
+	AssertNoError(t, err, "NewVMWithOptions with more memory")
+	AssertNoError(t, vm.Execute(), "vm.Execute")
+}
+
+func TestVMErrors(t *testing.T) {
+	tests := []struct {
+		assembly string
+		wantPC   Word
+		// check returns the program counter of the expected error type
+		// or false if err has a different type.
+		check func(err error) (Word, bool)
+	}{
+		{"const 1 abort", 5, func(err error) (Word, bool) {
+			var e *AbortError
+			ok := errors.As(err, &e) && strings.Contains(e.Trace, "abort")
+			return e.ProgramCounter, ok
+		}},
+		{"const 1 drop drop", 6, func(err error) (Word, bool) {
+			var e *StackUnderflowError
+			ok := errors.As(err, &e) && e.Stack == "data"
+			return e.ProgramCounter, ok
+		}},
+		{"const 1 rpop", 5, func(err error) (Word, bool) {
+			var e *StackUnderflowError
+			ok := errors.As(err, &e) && e.Stack == "return"
+			return e.ProgramCounter, ok
+		}},
+		{"const 1 :loop call @loop", 5, func(err error) (Word, bool) {
+			var e *StackOverflowError
+			ok := errors.As(err, &e) && e.Stack == "return" && e.Size == StackSize
+			return e.ProgramCounter, ok
+		}},
+		{"const 1 const 8192 b@", 10, func(err error) (Word, bool) {
+			var e *MemoryAccessError
+			ok := errors.As(err, &e) && e.Address == 8192
+			return e.ProgramCounter, ok
+		}},
+		{"const 1 const 0 %", 10, func(err error) (Word, bool) {
+			var e *DivisionByZeroError
+			ok := errors.As(err, &e)
+			return e.ProgramCounter, ok
+		}},
+		{"jmp @x :x -939524096", 5, func(err error) (Word, bool) {
+			var e *UnknownInstructionError
+			ok := errors.As(err, &e) && e.Instruction == 200
+			return e.ProgramCounter, ok
+		}},
+		{"const 7 excall", 5, func(err error) (Word, bool) {
+			var e *UnknownExtensionError
+			ok := errors.As(err, &e) && e.Address == 7
+			return e.ProgramCounter, ok
+		}},
+		{"const 65536 excall", 5, func(err error) (Word, bool) {
+			var e *ExtensionError
+			var underflow *StackUnderflowError
+			ok := errors.As(err, &e) && e.Address == 65536 &&
+				errors.As(err, &underflow) && underflow.ProgramCounter == 5
+			return e.ProgramCounter, ok
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.assembly, func(t *testing.T) {
+			_, _, program, err := Assemble(bytes.NewBufferString(tt.assembly + " exit"))
+			AssertNoError(t, err, "Assemble")
+
+			vm, err := NewDefaultVM(program)
+			AssertNoError(t, err, "NewVM")
+			AssertNoError(t, vm.RegisterExtension(testExtension), "RegisterExtension")
+
+			err = vm.Execute()
+			AssertError(t, err, "vm.Execute")
+
+			pc, ok := tt.check(err)
+			AssertEquals(t, true, ok, "error type")
+			AssertEquals(t, tt.wantPC, pc, "program counter")
+		})
+	}
+}
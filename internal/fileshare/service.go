@@ -88,7 +88,7 @@ func (s *Service) initUser(authn legacyweb.Auth) (string, error) {
 	}
 	dir := userAuth.Email.String()
 
-	err := s.db.Write(func(tx *storage.Tx) error {
+	err := s.db.Write(func(tx *storage.WriteTx) error {
 		if err := s.root.Mkdir(dir, 0744); err != nil && !errors.Is(err, os.ErrExist) {
 			return fmt.Errorf("create new home dir %q: %w", dir, err)
 		}
@@ -100,23 +100,20 @@ func (s *Service) initUser(authn legacyweb.Auth) (string, error) {
 }
 
 func (s *Service) getHomeDir(auth legacyweb.Auth) (string, error) {
-	var data UserData
-	err := s.db.Read(func(tx *storage.Tx) error {
-		d, err := storage.Load[*UserData](tx, []byte(auth.UserID().String()))
-		data = *d
-		if err != nil {
-			return fmt.Errorf("get home dir for %q: %w", auth.UserID(), err)
-		}
-		return nil
+	data, err := storage.ReadValue(s.db, func(tx *storage.ReadTx) (*UserData, error) {
+		return storage.Load[*UserData](tx, []byte(auth.UserID().String()))
 	})
 	if errors.Is(err, storage.ErrNotFound) {
 		return s.initUser(auth)
 	}
+	if err != nil {
+		return "", fmt.Errorf("get home dir for %q: %w", auth.UserID(), err)
+	}
 
-	return data.HomeDir, err
+	return data.HomeDir, nil
 }
 
-func (s *Service) setHomeDir(tx *storage.Tx, authn, toModify legacyweb.Auth, dir string) error {
+func (s *Service) setHomeDir(tx *storage.WriteTx, authn, toModify legacyweb.Auth, dir string) error {
 	data := UserData{
 		ID:      toModify.UserID().UUID,
 		HomeDir: dir,
@@ -217,7 +214,7 @@ func (s *Service) InitFile(ctx context.Context,
 		return "", err
 	}
 
-	err = s.db.Write(func(tx *storage.Tx) error {
+	err = s.db.Write(func(tx *storage.WriteTx) error {
 		return storage.Store(tx, &chunkedFile, auth.UserID(authn.UserID()))
 	})
 	if err != nil {
@@ -231,11 +228,8 @@ func (s *Service) InitFile(ctx context.Context,
 func (s *Service) AddFileChunk(ctx context.Context, reference string, r io.Reader) error {
 	// TODO: Chunked file should be in a user bucket.
 
-	var cFile *chunkedFile
-	err := s.db.Read(func(tx *storage.Tx) error {
-		cf, err := storage.Load[*chunkedFile](tx, []byte(reference))
-		cFile = cf
-		return err
+	cFile, err := storage.ReadValue(s.db, func(tx *storage.ReadTx) (*chunkedFile, error) {
+		return storage.Load[*chunkedFile](tx, []byte(reference))
 	})
 	if err != nil {
 		return fmt.Errorf("add file chunk: reference=%q, err=%w", reference, err)
@@ -257,16 +251,18 @@ func (s *Service) AddFileChunk(ctx context.Context, reference string, r io.Reade
 	return nil
 }
 func (s *Service) CommitFile(ctx context.Context, reference string) error {
-	root, err := s.userRoot(ctx)
+	authn, err := legacyweb.GetAuth(ctx)
 	if err != nil {
 		return err
 	}
 
-	var cFile chunkedFile
-	err = s.db.Read(func(tx *storage.Tx) error {
-		cf, err := storage.Load[*chunkedFile](tx, []byte(reference))
-		cFile = *cf
+	root, err := s.userRoot(ctx)
+	if err != nil {
 		return err
+	}
+
+	cFile, err := storage.ReadValue(s.db, func(tx *storage.ReadTx) (*chunkedFile, error) {
+		return storage.Load[*chunkedFile](tx, []byte(reference))
 	})
 	if err != nil {
 		return fmt.Errorf("commit file: reference=%q, err=%w",
@@ -293,5 +289,19 @@ func (s *Service) CommitFile(ctx context.Context, reference string) error {
 			reference, err)
 	}
 
+	err = s.db.Write(func(tx *storage.WriteTx) error {
+		return storage.Delete[*chunkedFile](tx, []byte(reference),
+			auth.UserID(authn.UserID()))
+	})
+	if err != nil {
+		return fmt.Errorf("delete chunked file: reference=%q, err=%w",
+			reference, err)
+	}
+
+	if err := os.Remove(temp.Name()); err != nil {
+		return fmt.Errorf("remove temp file: reference=%q, err=%w",
+			reference, err)
+	}
+
 	return nil
 }
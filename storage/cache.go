@@ -0,0 +1,153 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/eldelto/core/internal/boltutil"
+	"go.etcd.io/bbolt"
+)
+
+// cacheBucketName is the top-level bucket holding the materialized
+// latest value of every entity. It is laid out as
+// _cache -> bucket name -> entity ID -> snapshot record.
+const cacheBucketName = "_cache"
+
+// snapshotOf folds the given per-field records into a single
+// snapshot record ordered by insertion time.
+func snapshotOf(id []byte, fields map[string]Record) Record {
+	records := make([]Record, 0, len(fields))
+	for _, r := range fields {
+		records = append(records, r)
+	}
+	slices.SortStableFunc(records, func(a, b Record) int {
+		if a.InsertedAt != b.InsertedAt {
+			return int(a.InsertedAt - b.InsertedAt)
+		}
+		return bytes.Compare([]byte(a.Value), []byte(b.Value))
+	})
+
+	var insertedAt int64
+	if len(records) > 0 {
+		insertedAt = records[len(records)-1].InsertedAt
+	}
+
+	return Record{
+		ID:         id,
+		InsertedAt: insertedAt,
+		Snapshot:   records,
+	}
+}
+
+func latestRecords(codec boltutil.Codec, bucket *bbolt.Bucket, bucketName string) (map[string]Record, error) {
+	records := map[string]Record{}
+	err := eachRecordReverse(codec, bucket, bucketName, func(r Record) bool {
+		if _, ok := records[r.Value]; !ok {
+			records[r.Value] = r
+		}
+		return true
+	})
+
+	return records, err
+}
+
+// ensureCache creates and fills the cache of a bucket if enabled or
+// drops it otherwise so no stale entries survive disabling it.
+func ensureCache(db *bbolt.DB, b Bucket) error {
+	return db.Update(func(btx *bbolt.Tx) error {
+		root := btx.Bucket([]byte(cacheBucketName))
+		if !b.Cache {
+			if root == nil || root.Bucket([]byte(b.Name)) == nil {
+				return nil
+			}
+			return root.DeleteBucket([]byte(b.Name))
+		}
+
+		root, err := btx.CreateBucketIfNotExists([]byte(cacheBucketName))
+		if err != nil {
+			return fmt.Errorf("ensure cache on bucket %q: %w", b.Name, err)
+		}
+		if root.Bucket([]byte(b.Name)) != nil {
+			return nil
+		}
+
+		cache, err := root.CreateBucket([]byte(b.Name))
+		if err != nil {
+			return fmt.Errorf("ensure cache on bucket %q: %w", b.Name, err)
+		}
+
+		bucket := btx.Bucket([]byte(b.Name))
+		if bucket == nil {
+			return nil
+		}
+
+		codec, err := boltutil.CodecOf(btx, b.Name)
+		if err != nil {
+			return err
+		}
+
+		return bucket.ForEachBucket(func(id []byte) error {
+			records, err := latestRecords(codec, bucket.Bucket(id), b.Name)
+			if err != nil {
+				return err
+			}
+
+			return putRecord(codec, cache, b.Name, id, snapshotOf(id, records))
+		})
+	})
+}
+
+func cachedRecord(tx Tx, bucketName string, id []byte) (Record, bool, error) {
+	if !tx.bucketConf(bucketName).Cache {
+		return Record{}, false, nil
+	}
+
+	bucket := tx.boltTx().Bucket([]byte(cacheBucketName))
+	if bucket != nil {
+		bucket = bucket.Bucket([]byte(bucketName))
+	}
+	if bucket == nil {
+		return Record{}, false, nil
+	}
+
+	v := bucket.Get(id)
+	if v == nil {
+		return Record{}, false, nil
+	}
+
+	codec, err := tx.codecFor(bucketName)
+	if err != nil {
+		return Record{}, false, err
+	}
+
+	r, err := decodeRecord(codec, bucketName, id, v)
+	return r, err == nil, err
+}
+
+func updateCache(tx *WriteTx, bucketName string, id []byte,
+	existing map[string]Record, records []Record) error {
+	if !tx.buckets[bucketName].Cache {
+		return nil
+	}
+
+	bucket, err := getBucket(tx, []byte(cacheBucketName), []byte(bucketName))
+	if err != nil {
+		return fmt.Errorf("update cache: %w", err)
+	}
+
+	codec, err := tx.codecFor(bucketName)
+	if err != nil {
+		return fmt.Errorf("update cache: %w", err)
+	}
+
+	latest := make(map[string]Record, len(existing))
+	for k, r := range existing {
+		latest[k] = r
+	}
+	for _, r := range records {
+		latest[r.Value] = r
+	}
+
+	return putRecord(codec, bucket, bucketName, id, snapshotOf(id, latest))
+}
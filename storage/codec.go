@@ -0,0 +1,325 @@
+package storage
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"sync"
+
+	"github.com/eldelto/core/auth"
+	"github.com/eldelto/core/internal/boltutil"
+	"go.etcd.io/bbolt"
+)
+
+var (
+	typesMutex sync.RWMutex
+	types      = map[string]reflect.Type{}
+)
+
+func init() {
+	for _, v := range []any{
+		false, 0, int8(0), int16(0), int32(0), int64(0),
+		uint(0), uint8(0), uint16(0), uint32(0), uint64(0),
+		float32(0), float64(0), "",
+		[]bool{}, []int{}, []int64{}, []uint{}, []uint64{}, []byte{},
+		[]float64{}, []string{}, map[string]string{},
+	} {
+		registerType(reflect.TypeOf(v))
+	}
+}
+
+// typeName follows the naming scheme of gob.Register.
+func typeName(t reflect.Type) string {
+	star := ""
+	if t.Name() == "" && t.Kind() == reflect.Pointer {
+		star = "*"
+		t = t.Elem()
+	}
+
+	if t.Name() == "" {
+		return star + t.String()
+	}
+	if t.PkgPath() == "" {
+		return star + t.Name()
+	}
+	return star + t.PkgPath() + "." + t.Name()
+}
+
+func registerType(t reflect.Type) {
+	name := typeName(t)
+
+	typesMutex.RLock()
+	_, ok := types[name]
+	typesMutex.RUnlock()
+	if ok {
+		return
+	}
+
+	typesMutex.Lock()
+	types[name] = t
+	typesMutex.Unlock()
+}
+
+// RegisterType makes the type of value known to all codecs so it can
+// be decoded as an attribute. Like with gob.Register, this is only
+// needed for types that are not part of a struct passed to Store or
+// Load before.
+func RegisterType(value any) {
+	gob.Register(value)
+	registerType(reflect.TypeOf(value))
+}
+
+// ErrTypeNotRegistered is returned when decoding a record whose
+// attribute type was neither registered with RegisterType nor stored
+// before by the running program.
+var ErrTypeNotRegistered = errors.New("not registered")
+
+func lookupType(name string) (reflect.Type, error) {
+	typesMutex.RLock()
+	defer typesMutex.RUnlock()
+
+	t, ok := types[name]
+	if !ok {
+		return nil, fmt.Errorf("type %q is %w", name, ErrTypeNotRegistered)
+	}
+
+	return t, nil
+}
+
+// recordData is the representation of a Record for codecs other than
+// gob, which can not encode interface values. The attribute is encoded
+// separately together with the name of its type.
+type recordData struct {
+	ID            []byte
+	Value         string          `json:",omitempty"`
+	AttributeType string          `json:",omitempty"`
+	Attribute     json.RawMessage `json:",omitempty"`
+	InsertedAt    int64
+	InsertedBy    auth.UserID
+	Retraction    bool         `json:",omitempty"`
+	Snapshot      []recordData `json:",omitempty"`
+}
+
+func toRecordData(codec boltutil.Codec, r Record) (recordData, error) {
+	d := recordData{
+		ID:         r.ID,
+		Value:      r.Value,
+		InsertedAt: r.InsertedAt,
+		InsertedBy: r.InsertedBy,
+		Retraction: r.Retraction,
+	}
+
+	if r.Attribute != nil {
+		t := reflect.TypeOf(r.Attribute)
+		registerType(t)
+
+		attribute, err := codec.Marshal(r.Attribute)
+		if err != nil {
+			return d, fmt.Errorf("encode attribute %q: %w", r.Value, err)
+		}
+		d.AttributeType = typeName(t)
+		d.Attribute = attribute
+	}
+
+	for _, s := range r.Snapshot {
+		sd, err := toRecordData(codec, s)
+		if err != nil {
+			return d, err
+		}
+		d.Snapshot = append(d.Snapshot, sd)
+	}
+
+	return d, nil
+}
+
+func fromRecordData(codec boltutil.Codec, d recordData) (Record, error) {
+	r := Record{
+		ID:         d.ID,
+		Value:      d.Value,
+		InsertedAt: d.InsertedAt,
+		InsertedBy: d.InsertedBy,
+		Retraction: d.Retraction,
+	}
+
+	if d.AttributeType != "" {
+		t, err := lookupType(d.AttributeType)
+		if err != nil {
+			return r, fmt.Errorf("decode attribute %q: %w", d.Value, err)
+		}
+
+		attribute := reflect.New(t)
+		if err := codec.Unmarshal(d.Attribute, attribute.Interface()); err != nil {
+			return r, fmt.Errorf("decode attribute %q: %w", d.Value, err)
+		}
+		r.Attribute = attribute.Elem().Interface()
+	}
+
+	for _, sd := range d.Snapshot {
+		s, err := fromRecordData(codec, sd)
+		if err != nil {
+			return r, err
+		}
+		r.Snapshot = append(r.Snapshot, s)
+	}
+
+	return r, nil
+}
+
+// EncodeRecord encodes a single record the way it is persisted with
+// the given codec.
+func EncodeRecord(codec boltutil.Codec, r Record) ([]byte, error) {
+	// Gob handles interface values itself and keeps the format of
+	// databases written before codecs existed.
+	if codec.Name() == boltutil.Gob.Name() {
+		return codec.Marshal(r)
+	}
+
+	d, err := toRecordData(codec, r)
+	if err != nil {
+		return nil, err
+	}
+
+	return codec.Marshal(d)
+}
+
+// DecodeRecord is the counterpart of EncodeRecord.
+func DecodeRecord(codec boltutil.Codec, data []byte) (Record, error) {
+	if codec.Name() == boltutil.Gob.Name() {
+		var r Record
+		err := codec.Unmarshal(data, &r)
+		return r, err
+	}
+
+	var d recordData
+	if err := codec.Unmarshal(data, &d); err != nil {
+		return Record{}, err
+	}
+
+	return fromRecordData(codec, d)
+}
+
+// ensureCodec records the configured codec of a bucket and converts
+// existing data if it was encoded differently before.
+func (s *Storage) ensureCodec(b Bucket) error {
+	codec := b.Codec
+	if codec == nil {
+		codec = s.codec
+	}
+
+	var existing boltutil.Codec
+	err := s.db.Update(func(btx *bbolt.Tx) error {
+		c, err := boltutil.EnsureCodec(btx, b.Name, codec)
+		existing = c
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("ensure codec of bucket %q: %w", b.Name, err)
+	}
+
+	if existing.Name() == codec.Name() {
+		return nil
+	}
+
+	converted, err := s.ConvertBucket(b.Name, codec)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("converted %d records of bucket %q from codec %q to %q",
+		converted, b.Name, existing.Name(), codec.Name())
+	return nil
+}
+
+func convertValues(bucket *bbolt.Bucket, convert func(k, v []byte) ([]byte, error)) error {
+	updates := map[string][]byte{}
+	err := bucket.ForEach(func(k, v []byte) error {
+		if v == nil {
+			return nil
+		}
+
+		data, err := convert(k, v)
+		updates[string(k)] = data
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	// Modifying a bucket while iterating it is not allowed.
+	for k, v := range updates {
+		if err := bucket.Put([]byte(k), v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ConvertBucket rewrites all records of the given bucket, including
+// its cache and change feed, with the given codec. It returns the
+// number of converted records.
+func (s *Storage) ConvertBucket(name string, to boltutil.Codec) (int, error) {
+	converted := 0
+	err := s.db.Update(func(btx *bbolt.Tx) error {
+		from, err := boltutil.CodecOf(btx, name)
+		if err != nil {
+			return err
+		}
+		if from.Name() == to.Name() {
+			return nil
+		}
+
+		convertRecord := func(k, v []byte) ([]byte, error) {
+			r, err := decodeRecord(from, name, k, v)
+			if err != nil {
+				return nil, err
+			}
+			converted++
+			return EncodeRecord(to, r)
+		}
+
+		if bucket := btx.Bucket([]byte(name)); bucket != nil {
+			err := bucket.ForEachBucket(func(id []byte) error {
+				return convertValues(bucket.Bucket(id), convertRecord)
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		if cache, err := getBoltBucket(btx, []byte(cacheBucketName), []byte(name)); err == nil {
+			if err := convertValues(cache, func(k, v []byte) ([]byte, error) {
+				r, err := decodeRecord(from, name, k, v)
+				if err != nil {
+					return nil, err
+				}
+				return EncodeRecord(to, r)
+			}); err != nil {
+				return err
+			}
+		}
+
+		if feed, err := getBoltBucket(btx, []byte(feedBucketName), []byte(name)); err == nil {
+			if err := convertValues(feed, func(k, v []byte) ([]byte, error) {
+				e, err := decodeEvent(from, name, k, v)
+				if err != nil {
+					return nil, err
+				}
+				return encodeEvent(to, e)
+			}); err != nil {
+				return err
+			}
+		}
+
+		return boltutil.SetCodec(btx, name, to)
+	})
+	if err != nil {
+		return 0, fmt.Errorf("convert bucket %q to codec %q: %w",
+			name, to.Name(), err)
+	}
+
+	return converted, nil
+}
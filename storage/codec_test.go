@@ -0,0 +1,86 @@
+package storage_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/eldelto/core/internal/boltutil"
+	. "github.com/eldelto/core/internal/testutils"
+	"github.com/eldelto/core/storage"
+)
+
+func TestCodecs(t *testing.T) {
+	for _, codec := range []boltutil.Codec{boltutil.JSON, boltutil.CBOR} {
+		t.Run(codec.Name(), func(t *testing.T) {
+			store := newStorage()
+			defer os.Remove("storage-test.db")
+			defer store.Close()
+
+			store.RegisterBucket(storage.Bucket{
+				Name:    "payload",
+				Codec:   codec,
+				Indexes: []storage.Index{{Field: "Int"}},
+				Cache:   true,
+			})
+
+			p := newPayload()
+			user := newUser()
+
+			err := store.Write(func(tx *storage.WriteTx) error {
+				return storage.Store(tx, p, user)
+			})
+			AssertNoError(t, err, "storage.Store")
+
+			loaded, err := storage.ReadValue(store, func(tx *storage.ReadTx) (*payload, error) {
+				return storage.Load[*payload](tx, p.Key)
+			})
+			AssertNoError(t, err, "storage.Load")
+			AssertEquals(t, p, loaded, "loaded payload")
+
+			found, err := storage.ReadValue(store, func(tx *storage.ReadTx) ([]*payload, error) {
+				return storage.FindBy[*payload](tx, "Int", p.Int)
+			})
+			AssertNoError(t, err, "storage.FindBy")
+			AssertEquals(t, []*payload{p}, found, "found payloads")
+		})
+	}
+}
+
+func TestConvertBucket(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	converted, err := store.ConvertBucket("payload", boltutil.CBOR)
+	AssertNoError(t, err, "store.ConvertBucket")
+	AssertEquals(t, 5, converted, "converted records")
+
+	// Registering the bucket with another codec converts it again.
+	store.RegisterBucket(storage.Bucket{Name: "payload", Codec: boltutil.JSON})
+
+	p.String = "edited"
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	records, err := storage.ReadValue(store, func(tx *storage.ReadTx) ([]storage.Record, error) {
+		return storage.Records[*payload](tx, p.Key)
+	})
+	AssertNoError(t, err, "storage.Records")
+	AssertEquals(t, 6, len(records), "record length")
+
+	loaded, err := storage.ReadValue(store, func(tx *storage.ReadTx) (*payload, error) {
+		return storage.Load[*payload](tx, p.Key)
+	})
+	AssertNoError(t, err, "storage.Load")
+	AssertEquals(t, p, loaded, "loaded payload")
+}
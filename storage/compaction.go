@@ -0,0 +1,158 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/eldelto/core/internal/boltutil"
+	"github.com/go-co-op/gocron/v2"
+	"go.etcd.io/bbolt"
+)
+
+type CompactionResult struct {
+	Entities      int
+	RecordsBefore int
+	RecordsAfter  int
+}
+
+func (cr *CompactionResult) add(other CompactionResult) {
+	cr.Entities += other.Entities
+	cr.RecordsBefore += other.RecordsBefore
+	cr.RecordsAfter += other.RecordsAfter
+}
+
+// compactEntity folds all records inserted up to cutoff into a
+// single snapshot record that takes the place of the latest folded
+// record so the order of the log is preserved.
+func compactEntity(codec boltutil.Codec, bucket *bbolt.Bucket, bucketName string,
+	id []byte, cutoff int64) (CompactionResult, error) {
+	result := CompactionResult{Entities: 1}
+
+	keys := [][]byte{}
+	folded := map[string]Record{}
+	retaining := false
+	cursor := bucket.Cursor()
+	for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+		result.RecordsBefore++
+		// Only records in front of the first retained one can be
+		// folded without reordering the log.
+		if retaining {
+			continue
+		}
+
+		r, err := decodeRecord(codec, bucketName, k, v)
+		if err != nil {
+			return result, err
+		}
+
+		if len(r.Snapshot) < 1 && r.InsertedAt > cutoff {
+			retaining = true
+			continue
+		}
+
+		keys = append(keys, bytes.Clone(k))
+		eachUnfolded(r, func(r Record) bool {
+			folded[r.Value] = r
+			return true
+		})
+	}
+
+	if len(keys) < 2 {
+		result.RecordsAfter = result.RecordsBefore
+		return result, nil
+	}
+
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return result, fmt.Errorf("delete compacted record - bucket=%q, key=%q: %w",
+				bucketName, k, err)
+		}
+	}
+
+	lastKey := keys[len(keys)-1]
+	if err := putRecord(codec, bucket, bucketName, lastKey, snapshotOf(id, folded)); err != nil {
+		return result, err
+	}
+
+	result.RecordsAfter = result.RecordsBefore - len(keys) + 1
+	return result, nil
+}
+
+// CompactBucket folds the records of every entity in the given bucket
+// that are older than the retention window into a snapshot record.
+// History within the retention window is kept as is.
+func (s *Storage) CompactBucket(name string, retention time.Duration) (CompactionResult, error) {
+	result := CompactionResult{}
+	cutoff := time.Now().Add(-retention).UnixMilli()
+
+	err := s.db.Update(func(btx *bbolt.Tx) error {
+		bucket := btx.Bucket([]byte(name))
+		if bucket == nil {
+			return fmt.Errorf("bucket %q does not exist: %w", name, ErrNotFound)
+		}
+
+		codec, err := boltutil.CodecOf(btx, name)
+		if err != nil {
+			return err
+		}
+
+		err = bucket.ForEachBucket(func(id []byte) error {
+			r, err := compactEntity(codec, bucket.Bucket(id), name, id, cutoff)
+			if err != nil {
+				return err
+			}
+			result.add(r)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		// Events older than the retention window can no longer be
+		// replayed consistently with the compacted log.
+		return trimFeed(btx, name, cutoff)
+	})
+	if err != nil {
+		return result, fmt.Errorf("compact bucket %q: %w", name, err)
+	}
+
+	return result, nil
+}
+
+// Compact runs CompactBucket for all registered buckets.
+func (s *Storage) Compact(retention time.Duration) (CompactionResult, error) {
+	result := CompactionResult{}
+	for name := range s.buckets {
+		r, err := s.CompactBucket(name, retention)
+		if err != nil {
+			return result, err
+		}
+		result.add(r)
+	}
+
+	return result, nil
+}
+
+// ScheduleCompaction registers a job with the given scheduler that
+// compacts all registered buckets every interval.
+func (s *Storage) ScheduleCompaction(scheduler gocron.Scheduler,
+	interval, retention time.Duration) error {
+	_, err := scheduler.NewJob(gocron.DurationJob(interval),
+		gocron.NewTask(func() {
+			result, err := s.Compact(retention)
+			if err != nil {
+				log.Printf("failed to compact storage: %v", err)
+				return
+			}
+
+			log.Printf("compacted %d entities from %d to %d records",
+				result.Entities, result.RecordsBefore, result.RecordsAfter)
+		}))
+	if err != nil {
+		return fmt.Errorf("schedule storage compaction: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package storage_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/eldelto/core/internal/testutils"
+	"github.com/eldelto/core/storage"
+)
+
+func TestCompactBucket(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	for _, value := range []string{"a", "b", "c"} {
+		p.String = value
+		err := store.Write(func(tx *storage.WriteTx) error {
+			return storage.Store(tx, p, user)
+		})
+		AssertNoError(t, err, "storage.Store")
+	}
+
+	result, err := store.CompactBucket("payload", time.Hour)
+	AssertNoError(t, err, "store.CompactBucket")
+	AssertEquals(t, storage.CompactionResult{
+		Entities:      1,
+		RecordsBefore: 7,
+		RecordsAfter:  7,
+	}, result, "compaction within retention window")
+
+	result, err = store.CompactBucket("payload", 0)
+	AssertNoError(t, err, "store.CompactBucket")
+	AssertEquals(t, storage.CompactionResult{
+		Entities:      1,
+		RecordsBefore: 7,
+		RecordsAfter:  1,
+	}, result, "compaction result")
+
+	var p2 *payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		p, err := storage.Load[*payload](tx, p.Key)
+		p2 = p
+		return err
+	})
+	AssertNoError(t, err, "storage.Load")
+	AssertEquals(t, p, p2, "loaded record")
+
+	var records []storage.Record
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.Records[*payload](tx, p.Key)
+		records = r
+		return err
+	})
+	AssertNoError(t, err, "storage.Records")
+	AssertEquals(t, 5, len(records), "record length")
+
+	// Records written after compaction take precedence.
+	p.String = "d"
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		p, err := storage.Load[*payload](tx, p.Key)
+		p2 = p
+		return err
+	})
+	AssertNoError(t, err, "storage.Load")
+	AssertEquals(t, p, p2, "loaded record")
+
+	result, err = store.Compact(0)
+	AssertNoError(t, err, "store.Compact")
+	AssertEquals(t, 1, result.RecordsAfter, "records after compaction")
+
+	// Deletions survive compaction.
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Delete[*payload](tx, p.Key, user)
+	})
+	AssertNoError(t, err, "storage.Delete")
+
+	_, err = store.Compact(0)
+	AssertNoError(t, err, "store.Compact")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		_, err := storage.Load[*payload](tx, p.Key)
+		return err
+	})
+	AssertEquals(t, true, errors.Is(err, storage.ErrNotFound), "load deleted")
+}
+
+func TestCache(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	// Enabling the cache fills it from the existing records.
+	store.RegisterBucket(storage.Bucket{Name: "payload", Cache: true})
+
+	var p2 *payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		p, err := storage.Load[*payload](tx, p.Key)
+		p2 = p
+		return err
+	})
+	AssertNoError(t, err, "storage.Load")
+	AssertEquals(t, p, p2, "loaded record")
+
+	p.String = "edited"
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		p, err := storage.Load[*payload](tx, p.Key)
+		p2 = p
+		return err
+	})
+	AssertNoError(t, err, "storage.Load")
+	AssertEquals(t, p, p2, "loaded record")
+
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Delete[*payload](tx, p.Key, user)
+	})
+	AssertNoError(t, err, "storage.Delete")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		_, err := storage.Load[*payload](tx, p.Key)
+		return err
+	})
+	AssertEquals(t, true, errors.Is(err, storage.ErrNotFound), "load deleted")
+}
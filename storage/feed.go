@@ -0,0 +1,421 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/eldelto/core/internal/boltutil"
+	"go.etcd.io/bbolt"
+)
+
+// feedBucketName is the top-level bucket holding the persisted change
+// feed of every bucket with ChangeFeed enabled. It is laid out as
+// _feed -> bucket name -> sequence number -> event.
+const feedBucketName = "_feed"
+
+// feedTrimmedBucketName is the top-level bucket holding the highest
+// sequence number removed from the change feed of every bucket by
+// compaction.
+const feedTrimmedBucketName = "_feed_trimmed"
+
+// ErrFeedTrimmed is returned when events a caller asks for were already
+// removed by compaction. The caller has to reload the current state and
+// resume from FeedSequence instead.
+var ErrFeedTrimmed = errors.New("change feed has been trimmed")
+
+// Event contains the records committed for a single entity. Sequence
+// increases monotonically per bucket and can be used to resume a
+// subscription.
+type Event struct {
+	Sequence uint64
+	Bucket   string
+	ID       []byte
+	Records  []Record
+}
+
+// eventData is the representation of an Event for codecs other than
+// gob.
+type eventData struct {
+	Sequence uint64
+	Bucket   string
+	ID       []byte
+	Records  []recordData
+}
+
+func encodeEvent(codec boltutil.Codec, e Event) ([]byte, error) {
+	if codec.Name() == boltutil.Gob.Name() {
+		return codec.Marshal(e)
+	}
+
+	d := eventData{
+		Sequence: e.Sequence,
+		Bucket:   e.Bucket,
+		ID:       e.ID,
+		Records:  make([]recordData, 0, len(e.Records)),
+	}
+	for _, r := range e.Records {
+		rd, err := toRecordData(codec, r)
+		if err != nil {
+			return nil, err
+		}
+		d.Records = append(d.Records, rd)
+	}
+
+	return codec.Marshal(d)
+}
+
+func decodeEvent(codec boltutil.Codec, bucketName string, k, v []byte) (Event, error) {
+	var e Event
+	var err error
+	if codec.Name() == boltutil.Gob.Name() {
+		err = codec.Unmarshal(v, &e)
+	} else {
+		var d eventData
+		if err = codec.Unmarshal(v, &d); err == nil {
+			e = Event{Sequence: d.Sequence, Bucket: d.Bucket, ID: d.ID}
+			for _, rd := range d.Records {
+				var r Record
+				if r, err = fromRecordData(codec, rd); err != nil {
+					break
+				}
+				e.Records = append(e.Records, r)
+			}
+		}
+	}
+	if err != nil {
+		return e, fmt.Errorf("decode event - bucket=%q, key=%q: %w",
+			bucketName, k, err)
+	}
+
+	return e, nil
+}
+
+type subscriber struct {
+	bucket string
+	mutex  sync.Mutex
+	queue  []Event
+	signal chan struct{}
+	done   chan struct{}
+}
+
+func (s *subscriber) push(events []Event) {
+	s.mutex.Lock()
+	s.queue = append(s.queue, events...)
+	s.mutex.Unlock()
+
+	select {
+	case s.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber) pop() []Event {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	events := s.queue
+	s.queue = nil
+	return events
+}
+
+type changeFeed struct {
+	mutex       sync.Mutex
+	subscribers map[*subscriber]struct{}
+}
+
+func newChangeFeed() *changeFeed {
+	return &changeFeed{subscribers: map[*subscriber]struct{}{}}
+}
+
+func (cf *changeFeed) publish(events []Event) {
+	if len(events) < 1 {
+		return
+	}
+
+	cf.mutex.Lock()
+	defer cf.mutex.Unlock()
+
+	for sub := range cf.subscribers {
+		matching := make([]Event, 0, len(events))
+		for _, e := range events {
+			if e.Bucket == sub.bucket {
+				matching = append(matching, e)
+			}
+		}
+
+		if len(matching) > 0 {
+			sub.push(matching)
+		}
+	}
+}
+
+// appendEvent persists the records of an entity to the bucket's change
+// feed and queues them for publishing after the transaction commits.
+func appendEvent(tx *WriteTx, bucketName string, id []byte, records []Record) error {
+	if !tx.buckets[bucketName].ChangeFeed || len(records) < 1 {
+		return nil
+	}
+
+	root, err := tx.tx.CreateBucketIfNotExists([]byte(feedBucketName))
+	if err != nil {
+		return fmt.Errorf("append event: %w", err)
+	}
+	bucket, err := root.CreateBucketIfNotExists([]byte(bucketName))
+	if err != nil {
+		return fmt.Errorf("append event: %w", err)
+	}
+
+	sequence, err := bucket.NextSequence()
+	if err != nil {
+		return fmt.Errorf("append event: %w", err)
+	}
+
+	codec, err := tx.codecFor(bucketName)
+	if err != nil {
+		return fmt.Errorf("append event: %w", err)
+	}
+
+	event := Event{
+		Sequence: sequence,
+		Bucket:   bucketName,
+		ID:       id,
+		Records:  records,
+	}
+
+	data, err := encodeEvent(codec, event)
+	if err != nil {
+		return fmt.Errorf("encode event - bucket=%q, sequence=%d: %w",
+			bucketName, sequence, err)
+	}
+	if err := bucket.Put(itob(sequence), data); err != nil {
+		return fmt.Errorf("persist event - bucket=%q, sequence=%d: %w",
+			bucketName, sequence, err)
+	}
+
+	tx.events = append(tx.events, event)
+	return nil
+}
+
+// FeedSequence returns the sequence number of the latest event
+// committed to the change feed of the given bucket.
+func FeedSequence(tx Tx, bucketName string) uint64 {
+	bucket, err := getBucket(tx, []byte(feedBucketName), []byte(bucketName))
+	if err != nil {
+		return 0
+	}
+
+	return bucket.Sequence()
+}
+
+// Events returns all persisted events of the given bucket with a
+// sequence number greater than after. ErrFeedTrimmed is returned if
+// some of them were already removed by compaction.
+func Events(tx Tx, bucketName string, after uint64) ([]Event, error) {
+	events := []Event{}
+
+	if trimmed := trimmedSequence(tx.boltTx(), bucketName); after < trimmed {
+		return nil, fmt.Errorf("events of bucket %q after %d, trimmed up to %d: %w",
+			bucketName, after, trimmed, ErrFeedTrimmed)
+	}
+
+	bucket, err := getBucket(tx, []byte(feedBucketName), []byte(bucketName))
+	if err != nil {
+		return events, nil
+	}
+
+	codec, err := tx.codecFor(bucketName)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor := bucket.Cursor()
+	for k, v := cursor.Seek(itob(after + 1)); k != nil; k, v = cursor.Next() {
+		e, err := decodeEvent(codec, bucketName, k, v)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, nil
+}
+
+// trimFeed removes all events of a bucket's change feed whose records
+// were inserted up to cutoff.
+func trimFeed(btx *bbolt.Tx, bucketName string, cutoff int64) error {
+	root := btx.Bucket([]byte(feedBucketName))
+	if root == nil {
+		return nil
+	}
+	bucket := root.Bucket([]byte(bucketName))
+	if bucket == nil {
+		return nil
+	}
+
+	codec, err := boltutil.CodecOf(btx, bucketName)
+	if err != nil {
+		return err
+	}
+
+	keys := [][]byte{}
+	cursor := bucket.Cursor()
+	for k, v := cursor.First(); k != nil; k, v = cursor.Next() {
+		e, err := decodeEvent(codec, bucketName, k, v)
+		if err != nil {
+			return err
+		}
+
+		if len(e.Records) > 0 && e.Records[0].InsertedAt > cutoff {
+			break
+		}
+		keys = append(keys, bytes.Clone(k))
+	}
+
+	if len(keys) < 1 {
+		return nil
+	}
+
+	for _, k := range keys {
+		if err := bucket.Delete(k); err != nil {
+			return fmt.Errorf("trim change feed of bucket %q: %w", bucketName, err)
+		}
+	}
+
+	// Remember how far the feed was trimmed so subscribers resuming
+	// from an older sequence number don't silently miss events.
+	trimmed, err := btx.CreateBucketIfNotExists([]byte(feedTrimmedBucketName))
+	if err != nil {
+		return fmt.Errorf("trim change feed of bucket %q: %w", bucketName, err)
+	}
+	if err := trimmed.Put([]byte(bucketName), keys[len(keys)-1]); err != nil {
+		return fmt.Errorf("trim change feed of bucket %q: %w", bucketName, err)
+	}
+
+	return nil
+}
+
+// trimmedSequence returns the highest sequence number removed from
+// the change feed of the given bucket.
+func trimmedSequence(btx *bbolt.Tx, bucketName string) uint64 {
+	trimmed := btx.Bucket([]byte(feedTrimmedBucketName))
+	if trimmed == nil {
+		return 0
+	}
+
+	v := trimmed.Get([]byte(bucketName))
+	if len(v) != 8 {
+		return 0
+	}
+
+	return binary.BigEndian.Uint64(v)
+}
+
+// SubscribeFunc calls f for every event committed to the given bucket
+// after the event with the sequence number after. Already persisted
+// events are replayed first so a subscriber can resume from the last
+// sequence number it has seen. ErrFeedTrimmed is returned if compaction
+// already removed some of these events. f is called from a separate
+// goroutine and never concurrently. The returned function cancels the
+// subscription.
+func (s *Storage) SubscribeFunc(bucketName string, after uint64, f func(e Event)) (func(), error) {
+	return s.subscribe(bucketName, after, f, func() {})
+}
+
+// subscribe implements SubscribeFunc and additionally calls stopped
+// once the subscription got cancelled and f won't be called anymore.
+func (s *Storage) subscribe(bucketName string, after uint64, f func(e Event),
+	stopped func()) (func(), error) {
+	if !s.buckets[bucketName].ChangeFeed {
+		return nil, fmt.Errorf("subscribe to bucket %q: change feed is not enabled",
+			bucketName)
+	}
+
+	sub := &subscriber{
+		bucket: bucketName,
+		signal: make(chan struct{}, 1),
+		done:   make(chan struct{}),
+	}
+
+	// Registering before replaying ensures no event gets lost in
+	// between, duplicates are filtered by their sequence number.
+	s.feed.mutex.Lock()
+	s.feed.subscribers[sub] = struct{}{}
+	s.feed.mutex.Unlock()
+
+	var replay []Event
+	err := s.Read(func(tx *ReadTx) error {
+		events, err := Events(tx, bucketName, after)
+		replay = events
+		return err
+	})
+	if err != nil {
+		s.unsubscribe(sub)
+		return nil, fmt.Errorf("subscribe to bucket %q: %w", bucketName, err)
+	}
+
+	go func() {
+		defer stopped()
+
+		last := after
+		deliver := func(events []Event) {
+			for _, e := range events {
+				if e.Sequence <= last {
+					continue
+				}
+				last = e.Sequence
+				f(e)
+			}
+		}
+
+		deliver(replay)
+		for {
+			select {
+			case <-sub.done:
+				return
+			case <-sub.signal:
+				deliver(sub.pop())
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { s.unsubscribe(sub) })
+	}, nil
+}
+
+// Subscribe works like SubscribeFunc but delivers the events through
+// the returned channel which gets closed on cancellation.
+func (s *Storage) Subscribe(bucketName string, after uint64) (<-chan Event, func(), error) {
+	events := make(chan Event)
+	done := make(chan struct{})
+
+	cancel, err := s.subscribe(bucketName, after, func(e Event) {
+		select {
+		case events <- e:
+		case <-done:
+		}
+	}, func() { close(events) })
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			close(done)
+			cancel()
+		})
+	}, nil
+}
+
+func (s *Storage) unsubscribe(sub *subscriber) {
+	s.feed.mutex.Lock()
+	delete(s.feed.subscribers, sub)
+	s.feed.mutex.Unlock()
+
+	close(sub.done)
+}
@@ -0,0 +1,198 @@
+package storage_test
+
+import (
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/eldelto/core/internal/testutils"
+	"github.com/eldelto/core/storage"
+)
+
+func newFeedStorage() *storage.Storage {
+	s := newStorage()
+	s.RegisterBucket(storage.Bucket{Name: "payload", ChangeFeed: true})
+	return s
+}
+
+func receiveEvent(t *testing.T, events <-chan storage.Event) storage.Event {
+	t.Helper()
+
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return storage.Event{}
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	store := newFeedStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	events, cancel, err := store.Subscribe("payload", 0)
+	AssertNoError(t, err, "store.Subscribe")
+	defer cancel()
+
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	e := receiveEvent(t, events)
+	AssertEquals(t, uint64(1), e.Sequence, "event sequence")
+	AssertEquals(t, p.Key, e.ID, "event ID")
+	AssertEquals(t, 5, len(e.Records), "event records")
+
+	// Rolled back transactions are never published.
+	p.String = "rolled back"
+	err = store.Write(func(tx *storage.WriteTx) error {
+		if err := storage.Store(tx, p, user); err != nil {
+			return err
+		}
+		return errors.New("rollback")
+	})
+	AssertError(t, err, "rolled back transaction")
+
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Delete[*payload](tx, p.Key, user)
+	})
+	AssertNoError(t, err, "storage.Delete")
+
+	e = receiveEvent(t, events)
+	AssertEquals(t, uint64(2), e.Sequence, "event sequence")
+	AssertEquals(t, true, e.Records[0].Retraction, "retraction event")
+}
+
+func TestSubscribeConcurrentWriters(t *testing.T) {
+	store := newFeedStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	events, cancel, err := store.Subscribe("payload", 0)
+	AssertNoError(t, err, "store.Subscribe")
+	defer cancel()
+
+	const writers = 20
+	user := newUser()
+	wg := sync.WaitGroup{}
+	for range writers {
+		wg.Go(func() {
+			p := newPayload()
+			err := store.Write(func(tx *storage.WriteTx) error {
+				return storage.Store(tx, p, user)
+			})
+			AssertNoError(t, err, "storage.Store")
+		})
+	}
+
+	// Every committed event arrives in order, none is skipped.
+	for i := range writers {
+		e := receiveEvent(t, events)
+		AssertEquals(t, uint64(i+1), e.Sequence, "event sequence")
+	}
+	wg.Wait()
+}
+
+func TestSubscribeReplay(t *testing.T) {
+	store := newFeedStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	for _, value := range []string{"a", "b", "c"} {
+		p.String = value
+		err := store.Write(func(tx *storage.WriteTx) error {
+			return storage.Store(tx, p, user)
+		})
+		AssertNoError(t, err, "storage.Store")
+	}
+
+	// Resuming after the first event replays the remaining ones.
+	events, cancel, err := store.Subscribe("payload", 1)
+	AssertNoError(t, err, "store.Subscribe")
+	defer cancel()
+
+	e := receiveEvent(t, events)
+	AssertEquals(t, uint64(2), e.Sequence, "event sequence")
+	AssertEquals(t, "b", e.Records[0].Attribute, "event attribute")
+
+	e = receiveEvent(t, events)
+	AssertEquals(t, uint64(3), e.Sequence, "event sequence")
+	AssertEquals(t, "c", e.Records[0].Attribute, "event attribute")
+
+	_, err = store.Compact(0)
+	AssertNoError(t, err, "store.Compact")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		// Resuming from a trimmed sequence number requires a resync.
+		_, err := storage.Events(tx, "payload", 1)
+		AssertEquals(t, true, errors.Is(err, storage.ErrFeedTrimmed), "trimmed events")
+
+		after := storage.FeedSequence(tx, "payload")
+		AssertEquals(t, uint64(3), after, "storage.FeedSequence")
+		events, err := storage.Events(tx, "payload", after)
+		AssertEquals(t, 0, len(events), "events after compaction")
+		return err
+	})
+	AssertNoError(t, err, "storage.Events")
+
+	_, _, err = store.Subscribe("payload", 0)
+	AssertEquals(t, true, errors.Is(err, storage.ErrFeedTrimmed), "subscribe to trimmed events")
+}
+
+func TestSubscribeCancel(t *testing.T) {
+	store := newFeedStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	events, cancel, err := store.Subscribe("payload", 0)
+	AssertNoError(t, err, "store.Subscribe")
+
+	p := newPayload()
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, newUser())
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	received := make(chan int)
+	go func() {
+		count := 0
+		for range events {
+			count++
+			if count == 1 {
+				cancel()
+			}
+		}
+		received <- count
+	}()
+
+	// Ranging over the channel ends once the subscription is cancelled.
+	select {
+	case count := <-received:
+		AssertEquals(t, 1, count, "received events")
+	case <-time.After(time.Second):
+		t.Fatal("channel was not closed after cancel")
+	}
+
+	// Cancelling again is a no-op.
+	cancel()
+}
+
+func TestSubscribeWithoutChangeFeed(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	_, _, err := store.Subscribe("payload", 0)
+	AssertError(t, err, "store.Subscribe")
+}
@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/eldelto/core/auth"
+	"github.com/eldelto/core/internal/collections"
+)
+
+// Change describes a single modification of an entity's field.
+type Change struct {
+	Field      string
+	Previous   any
+	Value      any
+	ChangedAt  time.Time
+	ChangedBy  auth.UserID
+	Retraction bool
+}
+
+// LoadAsOf reconstructs the entity with the given ID as it was at the
+// given point in time. If the entity did not exist yet ErrNotFound is
+// returned.
+//
+// Compaction only keeps the latest value of each field older than the
+// retention window. For times before the compaction cutoff fields
+// that changed again before the cutoff are therefore missing from the
+// result and the entity may not be found at all.
+func LoadAsOf[T Storable](tx Tx, id []byte, at time.Time) (T, error) {
+	asOf := at.UnixMilli()
+	data, found, err := loadWhere[T](tx, id, func(r Record) bool {
+		return r.InsertedAt <= asOf
+	})
+	if err != nil {
+		return data, err
+	}
+
+	if !found {
+		return data, fmt.Errorf("load %q as of %v: %w", id, at, ErrNotFound)
+	}
+
+	return data, nil
+}
+
+// History returns every change to the fields of the entity with the
+// given ID in chronological order.
+//
+// Before the compaction cutoff only the last change of each field is
+// retained, without the value it replaced.
+func History[T Storable](tx Tx, id []byte) ([]Change, error) {
+	records, err := Records[T](tx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var data T
+	fields := collections.SetFromSliceValue(structFields(valueFor[T]()),
+		func(f reflect.StructField) string {
+			return f.Name
+		})
+
+	previous := map[string]any{}
+	changes := make([]Change, 0, len(records))
+	for _, r := range records {
+		if !fields.Contains(r.Value) {
+			continue
+		}
+
+		value := r.Attribute
+		if r.Retraction {
+			value = nil
+		}
+
+		changes = append(changes, Change{
+			Field:      r.Value,
+			Previous:   previous[r.Value],
+			Value:      value,
+			ChangedAt:  time.UnixMilli(r.InsertedAt),
+			ChangedBy:  r.InsertedBy,
+			Retraction: r.Retraction,
+		})
+		previous[r.Value] = value
+	}
+
+	if len(changes) < 1 {
+		return nil, fmt.Errorf("history of %q in bucket %q: %w",
+			id, data.Bucket(), ErrNotFound)
+	}
+
+	return changes, nil
+}
@@ -0,0 +1,103 @@
+package storage_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	. "github.com/eldelto/core/internal/testutils"
+	"github.com/eldelto/core/storage"
+)
+
+func TestLoadAsOf(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	before := time.Now().Add(-time.Second)
+	err := store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	time.Sleep(2 * time.Millisecond)
+	between := time.Now()
+	time.Sleep(2 * time.Millisecond)
+
+	original := *p
+	p.String = "edited"
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	var p2 *payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		p, err := storage.LoadAsOf[*payload](tx, p.Key, between)
+		p2 = p
+		return err
+	})
+	AssertNoError(t, err, "storage.LoadAsOf")
+	AssertEquals(t, &original, p2, "loaded record")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		p, err := storage.LoadAsOf[*payload](tx, p.Key, time.Now())
+		p2 = p
+		return err
+	})
+	AssertNoError(t, err, "storage.LoadAsOf")
+	AssertEquals(t, p, p2, "loaded record")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		_, err := storage.LoadAsOf[*payload](tx, p.Key, before)
+		return err
+	})
+	AssertEquals(t, true, errors.Is(err, storage.ErrNotFound),
+		"load before creation")
+}
+
+func TestHistory(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	p.String = "edited"
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	var changes []storage.Change
+	err = store.Read(func(tx *storage.ReadTx) error {
+		c, err := storage.History[*payload](tx, p.Key)
+		changes = c
+		return err
+	})
+	AssertNoError(t, err, "storage.History")
+	AssertEquals(t, 6, len(changes), "change count")
+
+	last := changes[len(changes)-1]
+	AssertEquals(t, "String", last.Field, "change field")
+	AssertEquals(t, "string-value", last.Previous, "change previous")
+	AssertEquals(t, "edited", last.Value, "change value")
+	AssertEquals(t, user, last.ChangedBy, "change user")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		_, err := storage.History[*payload](tx, []byte("unknown-ID"))
+		return err
+	})
+	AssertEquals(t, true, errors.Is(err, storage.ErrNotFound),
+		"history of non-existing")
+}
@@ -0,0 +1,414 @@
+package storage
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/eldelto/core/internal/boltutil"
+	"go.etcd.io/bbolt"
+)
+
+// indexBucketName is the top-level bucket holding all secondary
+// indexes. It is laid out as
+// _index -> bucket name -> field name -> encoded value -> entity ID.
+const indexBucketName = "_index"
+
+// indexKeyPrefix is prepended to every encoded index value because
+// bbolt does not allow empty bucket names (e.g. for empty strings).
+const indexKeyPrefix = byte(1)
+
+var ErrUniqueViolation = errors.New("unique index violation")
+
+type Index struct {
+	Field  string
+	Unique bool
+}
+
+func encodeIndexValue(value any) ([]byte, error) {
+	key := []byte{indexKeyPrefix}
+
+	switch v := value.(type) {
+	case time.Time:
+		return binary.BigEndian.AppendUint64(key,
+			uint64(v.UnixNano())^(1<<63)), nil
+	case []byte:
+		return append(key, v...), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.String:
+		return append(key, rv.String()...), nil
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(key, 1), nil
+		}
+		return append(key, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		// Flipping the sign bit keeps negative numbers in front of
+		// positive ones when comparing byte-wise.
+		return binary.BigEndian.AppendUint64(key,
+			uint64(rv.Int())^(1<<63)), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return binary.BigEndian.AppendUint64(key, rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		bits := math.Float64bits(rv.Float())
+		if bits&(1<<63) != 0 {
+			bits = ^bits
+		} else {
+			bits |= 1 << 63
+		}
+		return binary.BigEndian.AppendUint64(key, bits), nil
+	case reflect.Slice:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			return append(key, rv.Bytes()...), nil
+		}
+	case reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			for i := range rv.Len() {
+				key = append(key, byte(rv.Index(i).Uint()))
+			}
+			return key, nil
+		}
+	}
+
+	if m, ok := value.(encoding.BinaryMarshaler); ok {
+		b, err := m.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("encode index value %v: %w", value, err)
+		}
+		return append(key, b...), nil
+	}
+
+	return nil, fmt.Errorf("values of type '%T' can not be indexed", value)
+}
+
+func indexFor(tx Tx, bucketName, field string) (Index, error) {
+	for _, idx := range tx.bucketConf(bucketName).Indexes {
+		if idx.Field == field {
+			return idx, nil
+		}
+	}
+
+	return Index{}, fmt.Errorf("no index for field %q on bucket %q",
+		field, bucketName)
+}
+
+func getIndexBucket(tx Tx, bucketName, field string) (*bbolt.Bucket, error) {
+	return getBucket(tx, []byte(indexBucketName), []byte(bucketName), []byte(field))
+}
+
+func ensureIndexBucket(btx *bbolt.Tx, bucketName, field string) (*bbolt.Bucket, bool, error) {
+	root, err := btx.CreateBucketIfNotExists([]byte(indexBucketName))
+	if err != nil {
+		return nil, false, err
+	}
+
+	bucket, err := root.CreateBucketIfNotExists([]byte(bucketName))
+	if err != nil {
+		return nil, false, err
+	}
+
+	if b := bucket.Bucket([]byte(field)); b != nil {
+		return b, false, nil
+	}
+
+	b, err := bucket.CreateBucket([]byte(field))
+	return b, true, err
+}
+
+func addIndexEntry(bucket *bbolt.Bucket, idx Index, value any, id []byte) error {
+	key, err := encodeIndexValue(value)
+	if err != nil {
+		return err
+	}
+
+	ids, err := bucket.CreateBucketIfNotExists(key)
+	if err != nil {
+		return fmt.Errorf("add index entry - field=%q: %w", idx.Field, err)
+	}
+
+	if idx.Unique {
+		k, _ := ids.Cursor().First()
+		if k != nil && !bytes.Equal(k, id) {
+			return fmt.Errorf("field %q with value %v already exists: %w",
+				idx.Field, value, ErrUniqueViolation)
+		}
+	}
+
+	return ids.Put(id, []byte{})
+}
+
+func removeIndexEntry(bucket *bbolt.Bucket, idx Index, value any, id []byte) error {
+	key, err := encodeIndexValue(value)
+	if err != nil {
+		return err
+	}
+
+	ids := bucket.Bucket(key)
+	if ids == nil {
+		return nil
+	}
+
+	if err := ids.Delete(id); err != nil {
+		return fmt.Errorf("remove index entry - field=%q: %w", idx.Field, err)
+	}
+
+	if k, _ := ids.Cursor().First(); k == nil {
+		return bucket.DeleteBucket(key)
+	}
+
+	return nil
+}
+
+func updateIndexes(tx *WriteTx, bucketName string, id []byte,
+	existing map[string]Record, records []Record) error {
+	for _, idx := range tx.buckets[bucketName].Indexes {
+		for _, r := range records {
+			if r.Value != idx.Field {
+				continue
+			}
+
+			bucket, err := getIndexBucket(tx, bucketName, idx.Field)
+			if err != nil {
+				return err
+			}
+
+			if old, ok := existing[idx.Field]; ok {
+				if err := removeIndexEntry(bucket, idx, old.Attribute, id); err != nil {
+					return err
+				}
+			}
+
+			if r.Retraction {
+				continue
+			}
+
+			if err := addIndexEntry(bucket, idx, r.Attribute, id); err != nil {
+				return fmt.Errorf("update index on bucket %q: %w", bucketName, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildIndex populates a freshly created index from the records that
+// already exist in the bucket so indexes can be added at any time.
+func buildIndex(btx *bbolt.Tx, bucketName string, idx Index, index *bbolt.Bucket) error {
+	bucket := btx.Bucket([]byte(bucketName))
+	if bucket == nil {
+		return nil
+	}
+
+	codec, err := boltutil.CodecOf(btx, bucketName)
+	if err != nil {
+		return err
+	}
+
+	return bucket.ForEachBucket(func(id []byte) error {
+		var err error
+		walkErr := eachRecordReverse(codec, bucket.Bucket(id), bucketName, func(r Record) bool {
+			if r.Value != idx.Field {
+				return true
+			}
+
+			if !r.Retraction {
+				err = addIndexEntry(index, idx, r.Attribute, id)
+			}
+			return false
+		})
+		if walkErr != nil {
+			return walkErr
+		}
+		if err != nil {
+			return fmt.Errorf("build index on bucket %q: %w", bucketName, err)
+		}
+
+		return nil
+	})
+}
+
+func ensureIndexes(db *bbolt.DB, b Bucket) error {
+	return db.Update(func(btx *bbolt.Tx) error {
+		for _, idx := range b.Indexes {
+			index, created, err := ensureIndexBucket(btx, b.Name, idx.Field)
+			if err != nil {
+				return fmt.Errorf("ensure index %q on bucket %q: %w",
+					idx.Field, b.Name, err)
+			}
+
+			if !created {
+				continue
+			}
+
+			if err := buildIndex(btx, b.Name, idx, index); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func indexValueFor[T Storable](field string, value any) (any, error) {
+	t := reflect.TypeFor[T]().Elem()
+	f, ok := t.FieldByName(field)
+	if !ok {
+		return nil, fmt.Errorf("'%v' has no field %q", t, field)
+	}
+
+	v := reflect.ValueOf(value)
+	switch {
+	case !v.IsValid():
+	case v.Type().AssignableTo(f.Type):
+		return value, nil
+	case isNumeric(v.Kind()) && isNumeric(f.Type.Kind()):
+		return v.Convert(f.Type).Interface(), nil
+	}
+
+	return nil, fmt.Errorf("value %v of type '%T' is not compatible with field %q of type '%v'",
+		value, value, field, f.Type)
+}
+
+func isNumeric(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
+
+func loadAll[T Storable](tx Tx, ids [][]byte) ([]T, error) {
+	results := make([]T, 0, len(ids))
+	for _, id := range ids {
+		data, err := Load[T](tx, id)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, data)
+	}
+
+	return results, nil
+}
+
+func collectIDs(bucket *bbolt.Bucket, ids [][]byte) [][]byte {
+	cursor := bucket.Cursor()
+	for k, _ := cursor.First(); k != nil; k, _ = cursor.Next() {
+		ids = append(ids, bytes.Clone(k))
+	}
+
+	return ids
+}
+
+// FindBy returns all entities of type T whose indexed field equals
+// the given value.
+func FindBy[T Storable](tx Tx, field string, value any) ([]T, error) {
+	var data T
+	if _, err := indexFor(tx, data.Bucket(), field); err != nil {
+		return nil, err
+	}
+
+	value, err := indexValueFor[T](field, value)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := encodeIndexValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := getIndexBucket(tx, data.Bucket(), field)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := [][]byte{}
+	if bucket := index.Bucket(key); bucket != nil {
+		ids = collectIDs(bucket, ids)
+	}
+
+	results, err := loadAll[T](tx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("find by %q: %w", field, err)
+	}
+
+	return results, nil
+}
+
+// FindOneBy returns the single entity of type T whose indexed field
+// equals the given value or ErrNotFound if there is none.
+func FindOneBy[T Storable](tx Tx, field string, value any) (T, error) {
+	results, err := FindBy[T](tx, field, value)
+	if err != nil {
+		var empty T
+		return empty, err
+	}
+
+	if len(results) < 1 {
+		var empty T
+		return empty, fmt.Errorf("find one by %q with value %v: %w",
+			field, value, ErrNotFound)
+	}
+
+	return results[0], nil
+}
+
+// FindRange returns all entities of type T whose indexed field lies
+// within [from, to), ordered by the field's value. A nil bound is
+// treated as unbounded.
+func FindRange[T Storable](tx Tx, field string, from, to any) ([]T, error) {
+	var data T
+	if _, err := indexFor(tx, data.Bucket(), field); err != nil {
+		return nil, err
+	}
+
+	var fromKey, toKey []byte
+	if from != nil {
+		value, err := indexValueFor[T](field, from)
+		if err != nil {
+			return nil, err
+		}
+		if fromKey, err = encodeIndexValue(value); err != nil {
+			return nil, err
+		}
+	}
+	if to != nil {
+		value, err := indexValueFor[T](field, to)
+		if err != nil {
+			return nil, err
+		}
+		if toKey, err = encodeIndexValue(value); err != nil {
+			return nil, err
+		}
+	}
+
+	index, err := getIndexBucket(tx, data.Bucket(), field)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := [][]byte{}
+	cursor := index.Cursor()
+	k, _ := cursor.First()
+	if fromKey != nil {
+		k, _ = cursor.Seek(fromKey)
+	}
+	for ; k != nil; k, _ = cursor.Next() {
+		if toKey != nil && bytes.Compare(k, toKey) >= 0 {
+			break
+		}
+
+		ids = collectIDs(index.Bucket(k), ids)
+	}
+
+	results, err := loadAll[T](tx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("find range %q: %w", field, err)
+	}
+
+	return results, nil
+}
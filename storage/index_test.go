@@ -0,0 +1,222 @@
+package storage_test
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	. "github.com/eldelto/core/internal/testutils"
+	"github.com/eldelto/core/storage"
+)
+
+func newIndexedStorage() *storage.Storage {
+	s := newStorage()
+	s.RegisterBucket(storage.Bucket{
+		Name: "payload",
+		Indexes: []storage.Index{
+			{Field: "String"},
+			{Field: "Int", Unique: true},
+		},
+	})
+	return s
+}
+
+func TestFindBy(t *testing.T) {
+	store := newIndexedStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p1 := newPayload()
+	p2 := newPayload()
+	p2.Int = 2
+	p3 := newPayload()
+	p3.String = "other"
+	p3.Int = 3
+	user := newUser()
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		for _, p := range []*payload{p1, p2, p3} {
+			if err := storage.Store(tx, p, user); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	var results []*payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.FindBy[*payload](tx, "String", "string-value")
+		results = r
+		return err
+	})
+	AssertNoError(t, err, "storage.FindBy")
+	AssertEquals(t, 2, len(results), "result length")
+
+	var result *payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.FindOneBy[*payload](tx, "Int", 3)
+		result = r
+		return err
+	})
+	AssertNoError(t, err, "storage.FindOneBy")
+	AssertEquals(t, p3, result, "result")
+
+	// Changing a field moves the entity to the new index entry.
+	p1.String = "other"
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p1, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.FindBy[*payload](tx, "String", "string-value")
+		results = r
+		return err
+	})
+	AssertNoError(t, err, "storage.FindBy")
+	AssertEquals(t, []*payload{p2}, results, "results")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		_, err := storage.FindOneBy[*payload](tx, "Int", 99)
+		return err
+	})
+	AssertEquals(t, true, errors.Is(err, storage.ErrNotFound), "find non-existing")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		_, err := storage.FindBy[*payload](tx, "Time", p1.Time)
+		return err
+	})
+	AssertError(t, err, "find by non-indexed field")
+}
+
+func TestUniqueIndex(t *testing.T) {
+	store := newIndexedStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p1 := newPayload()
+	p2 := newPayload()
+	user := newUser()
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p1, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p2, user)
+	})
+	AssertEquals(t, true, errors.Is(err, storage.ErrUniqueViolation),
+		"store duplicate")
+
+	// Storing the same entity again must not violate its own entry.
+	p1.String = "edited"
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p1, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+}
+
+func TestFindRange(t *testing.T) {
+	store := newIndexedStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	user := newUser()
+	payloads := []*payload{}
+	for _, i := range []int{5, -3, 10, 0} {
+		p := newPayload()
+		p.Int = i
+		payloads = append(payloads, p)
+	}
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		for _, p := range payloads {
+			if err := storage.Store(tx, p, user); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	var results []*payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.FindRange[*payload](tx, "Int", -3, 10)
+		results = r
+		return err
+	})
+	AssertNoError(t, err, "storage.FindRange")
+	AssertEquals(t, []*payload{payloads[1], payloads[3], payloads[0]},
+		results, "results")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.FindRange[*payload](tx, "Int", 1, nil)
+		results = r
+		return err
+	})
+	AssertNoError(t, err, "storage.FindRange")
+	AssertEquals(t, []*payload{payloads[0], payloads[2]}, results, "results")
+}
+
+func TestIndexBackfill(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	store.RegisterBucket(storage.Bucket{
+		Name:    "payload",
+		Indexes: []storage.Index{{Field: "String"}},
+	})
+
+	var results []*payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.FindBy[*payload](tx, "String", p.String)
+		results = r
+		return err
+	})
+	AssertNoError(t, err, "storage.FindBy")
+	AssertEquals(t, []*payload{p}, results, "results")
+}
+
+func TestDeleteRemovesIndexEntry(t *testing.T) {
+	store := newIndexedStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p1 := newPayload()
+	p2 := newPayload()
+	user := newUser()
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		if err := storage.Store(tx, p1, user); err != nil {
+			return err
+		}
+		return storage.Delete[*payload](tx, p1.Key, user)
+	})
+	AssertNoError(t, err, "storage.Delete")
+
+	// The unique value is free again after the deletion.
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p2, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	var results []*payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.FindBy[*payload](tx, "Int", p2.Int)
+		results = r
+		return err
+	})
+	AssertNoError(t, err, "storage.FindBy")
+	AssertEquals(t, []*payload{p2}, results, "results")
+}
@@ -0,0 +1,141 @@
+package storage
+
+import (
+	"fmt"
+
+	"github.com/eldelto/core/internal/boltutil"
+	"go.etcd.io/bbolt"
+)
+
+// MapRecords returns a migration that passes every record of a bucket,
+// including the ones folded into snapshots, through f. f reports if
+// it changed the record.
+func MapRecords(f func(r Record) (Record, bool, error)) boltutil.MigrationFunc {
+	return func(btx *bbolt.Tx, bucketName string) (int, error) {
+		bucket := btx.Bucket([]byte(bucketName))
+		if bucket == nil {
+			return 0, fmt.Errorf("bucket %q does not exist: %w", bucketName, ErrNotFound)
+		}
+
+		codec, err := boltutil.CodecOf(btx, bucketName)
+		if err != nil {
+			return 0, err
+		}
+
+		changed := 0
+		err = bucket.ForEachBucket(func(id []byte) error {
+			entity := bucket.Bucket(id)
+			updates := map[string]Record{}
+
+			err := entity.ForEach(func(k, v []byte) error {
+				r, err := decodeRecord(codec, bucketName, k, v)
+				if err != nil {
+					return err
+				}
+
+				var modified bool
+				if len(r.Snapshot) > 0 {
+					for i := range r.Snapshot {
+						var ok bool
+						r.Snapshot[i], ok, err = f(r.Snapshot[i])
+						if err != nil {
+							return err
+						}
+						modified = modified || ok
+					}
+				} else {
+					r, modified, err = f(r)
+					if err != nil {
+						return err
+					}
+				}
+
+				if modified {
+					updates[string(k)] = r
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+
+			for k, r := range updates {
+				if err := putRecord(codec, entity, bucketName, []byte(k), r); err != nil {
+					return err
+				}
+			}
+			changed += len(updates)
+			return nil
+		})
+
+		return changed, err
+	}
+}
+
+// RenameField returns a migration that moves all records of the field
+// from to the field to.
+func RenameField(from, to string) boltutil.MigrationFunc {
+	return MapRecords(func(r Record) (Record, bool, error) {
+		if r.Value != from {
+			return r, false, nil
+		}
+
+		r.Value = to
+		return r, true, nil
+	})
+}
+
+// ConvertField returns a migration that converts the attributes of all
+// records of the given field from Old to New. Non-builtin types of New
+// need to be registered with RegisterType.
+func ConvertField[Old, New any](field string, f func(old Old) (New, error)) boltutil.MigrationFunc {
+	return MapRecords(func(r Record) (Record, bool, error) {
+		if r.Value != field {
+			return r, false, nil
+		}
+
+		old, ok := r.Attribute.(Old)
+		if !ok {
+			var o Old
+			return r, false, fmt.Errorf("attribute of field %q is of type '%T' instead of '%T'",
+				field, r.Attribute, o)
+		}
+
+		value, err := f(old)
+		if err != nil {
+			return r, false, fmt.Errorf("convert field %q: %w", field, err)
+		}
+
+		r.Attribute = value
+		return r, true, nil
+	})
+}
+
+// ResetDerivedData drops the indexes and the cache of a bucket so they
+// get rebuilt from the migrated records.
+func ResetDerivedData(db *bbolt.DB, bucketName string) error {
+	return db.Update(func(btx *bbolt.Tx) error {
+		for _, name := range []string{indexBucketName, cacheBucketName} {
+			root := btx.Bucket([]byte(name))
+			if root == nil || root.Bucket([]byte(bucketName)) == nil {
+				continue
+			}
+
+			if err := root.DeleteBucket([]byte(bucketName)); err != nil {
+				return fmt.Errorf("reset %q of bucket %q: %w", name, bucketName, err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// DryRunMigrations reports which of the bucket's migrations would be
+// applied and what they would change without persisting anything.
+func (s *Storage) DryRunMigrations(b Bucket) (boltutil.MigrationReport, error) {
+	if err := boltutil.EnsureBucketExists(s.db, b.Name); err != nil {
+		return boltutil.MigrationReport{}, err
+	}
+
+	return boltutil.Migrate(s.db, b.Name, b.Migrations, true)
+}
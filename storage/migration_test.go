@@ -0,0 +1,100 @@
+package storage_test
+
+import (
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/eldelto/core/internal/boltutil"
+	. "github.com/eldelto/core/internal/testutils"
+	"github.com/eldelto/core/storage"
+)
+
+type legacyPayload struct {
+	Key   []byte
+	Text  string
+	Count string
+}
+
+func (p *legacyPayload) Bucket() string {
+	return "payload"
+}
+
+func (p *legacyPayload) BucketKey() []byte {
+	return p.Key
+}
+
+var payloadMigrations = []boltutil.Migration{
+	{
+		Version:     1,
+		Description: "rename Text to String",
+		Func:        storage.RenameField("Text", "String"),
+	},
+	{
+		Version:     2,
+		Description: "convert Count to Int",
+		Func: storage.ConvertField("Count", func(old string) (int, error) {
+			return strconv.Atoi(old)
+		}),
+	},
+	{
+		Version:     3,
+		Description: "rename Count to Int",
+		Func:        storage.RenameField("Count", "Int"),
+	},
+}
+
+func TestMigrations(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	legacy := &legacyPayload{Key: p.Key, Text: "legacy", Count: "7"}
+	user := newUser()
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, legacy, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	bucket := storage.Bucket{
+		Name:       "payload",
+		Indexes:    []storage.Index{{Field: "Int"}},
+		Migrations: payloadMigrations,
+	}
+
+	report, err := store.DryRunMigrations(bucket)
+	AssertNoError(t, err, "store.DryRunMigrations")
+	AssertEquals(t, uint64(0), report.From, "report.From")
+	AssertEquals(t, uint64(3), report.To, "report.To")
+	AssertEquals(t, []boltutil.MigrationResult{
+		{Version: 1, Description: "rename Text to String", Changed: 1},
+		{Version: 2, Description: "convert Count to Int", Changed: 1},
+		{Version: 3, Description: "rename Count to Int", Changed: 1},
+	}, report.Results, "report.Results")
+
+	// A dry run must not persist anything.
+	report, err = store.DryRunMigrations(bucket)
+	AssertNoError(t, err, "store.DryRunMigrations")
+	AssertEquals(t, uint64(0), report.From, "report.From")
+
+	store.RegisterBucket(bucket)
+
+	var results []*payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.FindBy[*payload](tx, "Int", 7)
+		results = r
+		return err
+	})
+	AssertNoError(t, err, "storage.FindBy")
+	AssertEquals(t, 1, len(results), "result length")
+	AssertEquals(t, "legacy", results[0].String, "migrated String")
+	AssertEquals(t, 7, results[0].Int, "migrated Int")
+
+	// Migrations are only applied once.
+	report, err = store.DryRunMigrations(bucket)
+	AssertNoError(t, err, "store.DryRunMigrations")
+	AssertEquals(t, uint64(3), report.From, "report.From")
+	AssertEquals(t, 0, len(report.Results), "applied migrations")
+}
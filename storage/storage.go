@@ -2,17 +2,15 @@ package storage
 
 // TODO:
 //   - Storable should not expose the bucket key
-//   - Different types for read/write transaction
-//   - Materialized cache for faster reads? (e.g. "payload"/cache)
 //   - Should ListAll always have a defined sort order?
 
 import (
-	"bytes"
 	"encoding/binary"
-	"encoding/gob"
 	"errors"
 	"fmt"
+	"log"
 	"reflect"
+	"sync"
 	"time"
 
 	"github.com/eldelto/core/auth"
@@ -23,8 +21,8 @@ import (
 )
 
 func init() {
-	gob.Register(time.Time{})
-	gob.Register(uuid.UUID{})
+	RegisterType(time.Time{})
+	RegisterType(uuid.UUID{})
 }
 
 func itob(v uint64) []byte {
@@ -40,13 +38,28 @@ type Record struct {
 	InsertedAt int64
 	InsertedBy auth.UserID
 	Retraction bool
+	// Snapshot holds the folded records of an entity after
+	// compaction. Records with a snapshot carry no value themselves.
+	Snapshot []Record
 }
 
-type TriggerFunc func(tx *Tx, r []Record) error
+type TriggerFunc func(tx *WriteTx, r []Record) error
 
 type Bucket struct {
 	Name         string
 	TriggerFuncs []TriggerFunc
+	Indexes      []Index
+	// Cache enables a materialized view of the latest value of every
+	// entity so Load does not need to walk the record log.
+	Cache bool
+	// Migrations are applied once when the bucket gets registered.
+	Migrations []boltutil.Migration
+	// ChangeFeed persists an Event for every Store and Delete so
+	// subscribers get notified after the transaction committed.
+	ChangeFeed bool
+	// Codec overrides the codec of the Storage for this bucket.
+	// Existing data gets converted when the bucket is registered.
+	Codec boltutil.Codec
 }
 
 type Storable interface {
@@ -54,12 +67,51 @@ type Storable interface {
 	BucketKey() []byte
 }
 
-type Tx struct {
+// Tx is implemented by ReadTx and WriteTx so all reading functions
+// can be used within both kinds of transactions.
+type Tx interface {
+	boltTx() *bbolt.Tx
+	bucketConf(name string) Bucket
+	codecFor(bucketName string) (boltutil.Codec, error)
+}
+
+type ReadTx struct {
 	tx      *bbolt.Tx
 	buckets map[string]Bucket
+	codec   boltutil.Codec
+}
+
+func (tx *ReadTx) boltTx() *bbolt.Tx {
+	return tx.tx
+}
+
+func (tx *ReadTx) bucketConf(name string) Bucket {
+	return tx.buckets[name]
+}
+
+// configuredCodec returns the codec a bucket without any data gets
+// encoded with.
+func (tx *ReadTx) configuredCodec(bucketName string) boltutil.Codec {
+	if c := tx.buckets[bucketName].Codec; c != nil {
+		return c
+	}
+	return tx.codec
+}
+
+func (tx *ReadTx) codecFor(bucketName string) (boltutil.Codec, error) {
+	return boltutil.DetectCodec(tx.tx, bucketName, tx.configuredCodec(bucketName))
 }
 
-type TxFunc func(tx *Tx) error
+// WriteTx is a ReadTx that additionally allows modifications like
+// Store and Delete.
+type WriteTx struct {
+	ReadTx
+	events []Event
+}
+
+type ReadFunc func(tx *ReadTx) error
+
+type WriteFunc func(tx *WriteTx) error
 
 func structFields(data any) []reflect.StructField {
 	strct := reflect.ValueOf(data).Elem()
@@ -70,15 +122,15 @@ func structFields(data any) []reflect.StructField {
 		panic(err)
 	}
 
-	return reflect.VisibleFields(t)
-}
-
-func toRecords[T Storable](tx *Tx, data T, user auth.UserID) ([]Record, error) {
-	existingRecords, err := loadUniqueRecords(tx, data)
-	if err != nil {
-		return nil, err
+	fields := reflect.VisibleFields(t)
+	for _, f := range fields {
+		registerType(f.Type)
 	}
 
+	return fields
+}
+
+func toRecords[T Storable](existingRecords map[string]Record, data T, user auth.UserID) []Record {
 	strct := reflect.ValueOf(data).Elem()
 	insertedAt := time.Now().UnixMilli()
 
@@ -86,7 +138,8 @@ func toRecords[T Storable](tx *Tx, data T, user auth.UserID) ([]Record, error) {
 	for i, f := range structFields(data) {
 		attribute := strct.Field(i).Interface()
 
-		if reflect.DeepEqual(existingRecords[f.Name].Attribute, attribute) {
+		existing := existingRecords[f.Name]
+		if !existing.Retraction && reflect.DeepEqual(existing.Attribute, attribute) {
 			continue
 		}
 
@@ -100,18 +153,32 @@ func toRecords[T Storable](tx *Tx, data T, user auth.UserID) ([]Record, error) {
 		records = append(records, r)
 	}
 
-	return records, nil
+	return records
 }
 
 type Storage struct {
 	db      *bbolt.DB
 	buckets map[string]Bucket
+	feed    *changeFeed
+	codec   boltutil.Codec
+	// publishMutex is held from the start of a write transaction until
+	// its events are published so that subscribers receive them in
+	// commit order.
+	publishMutex sync.Mutex
 }
 
 func New(db *bbolt.DB) *Storage {
+	return NewWithCodec(db, boltutil.Gob)
+}
+
+// NewWithCodec creates a Storage that encodes the records of all
+// buckets without an explicitly configured codec with the given one.
+func NewWithCodec(db *bbolt.DB, codec boltutil.Codec) *Storage {
 	return &Storage{
 		db:      db,
 		buckets: map[string]Bucket{},
+		feed:    newChangeFeed(),
+		codec:   codec,
 	}
 }
 
@@ -124,86 +191,206 @@ func (s *Storage) RegisterBucket(b Bucket) {
 	if err := boltutil.EnsureBucketExists(s.db, b.Name); err != nil {
 		panic(err)
 	}
+	if err := s.ensureCodec(b); err != nil {
+		panic(err)
+	}
+
+	report, err := boltutil.Migrate(s.db, b.Name, b.Migrations, false)
+	if err != nil {
+		panic(err)
+	}
+	if len(report.Results) > 0 {
+		log.Println(report.String())
+		if err := ResetDerivedData(s.db, b.Name); err != nil {
+			panic(err)
+		}
+	}
+
+	if err := ensureIndexes(s.db, b); err != nil {
+		panic(err)
+	}
+	if err := ensureCache(s.db, b); err != nil {
+		panic(err)
+	}
 }
 
-func (s *Storage) Read(f TxFunc) error {
+func (s *Storage) Read(f ReadFunc) error {
 	return s.db.View(func(btx *bbolt.Tx) error {
-		tx := Tx{
+		tx := ReadTx{
 			tx:      btx,
 			buckets: s.buckets,
+			codec:   s.codec,
 		}
 		return f(&tx)
 	})
 }
 
-func (s *Storage) Write(f TxFunc) error {
-	return s.db.Update(func(btx *bbolt.Tx) error {
-		tx := Tx{
-			tx:      btx,
-			buckets: s.buckets,
+func (s *Storage) Write(f WriteFunc) error {
+	s.publishMutex.Lock()
+	defer s.publishMutex.Unlock()
+
+	var events []Event
+	err := s.db.Update(func(btx *bbolt.Tx) error {
+		tx := WriteTx{
+			ReadTx: ReadTx{
+				tx:      btx,
+				buckets: s.buckets,
+				codec:   s.codec,
+			},
 		}
-		return f(&tx)
+		if err := f(&tx); err != nil {
+			return err
+		}
+
+		events = tx.events
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// Subscribers must never see changes of a rolled back transaction.
+	s.feed.publish(events)
+	return nil
+}
+
+// ReadValue runs f within a read transaction and returns its result.
+func ReadValue[T any](s *Storage, f func(tx *ReadTx) (T, error)) (T, error) {
+	var value T
+	err := s.Read(func(tx *ReadTx) error {
+		v, err := f(tx)
+		value = v
+		return err
+	})
+
+	return value, err
 }
 
-func storeRecord(r Record, bucket *bbolt.Bucket, bucketName string) error {
+// WriteValue runs f within a write transaction and returns its
+// result.
+func WriteValue[T any](s *Storage, f func(tx *WriteTx) (T, error)) (T, error) {
+	var value T
+	err := s.Write(func(tx *WriteTx) error {
+		v, err := f(tx)
+		value = v
+		return err
+	})
+
+	return value, err
+}
+
+func storeRecord(codec boltutil.Codec, r Record, bucket *bbolt.Bucket, bucketName string) error {
 	id, err := bucket.NextSequence()
 	if err != nil {
 		return err
 	}
-	key := itob(id)
 
-	// TODO: Reuse encoder and buffer
-	buffer := bytes.Buffer{}
-	if err := gob.NewEncoder(&buffer).Encode(r); err != nil {
+	return putRecord(codec, bucket, bucketName, itob(id), r)
+}
+
+func putRecord(codec boltutil.Codec, bucket *bbolt.Bucket, bucketName string, key []byte, r Record) error {
+	data, err := EncodeRecord(codec, r)
+	if err != nil {
 		return fmt.Errorf("encode value - bucket=%q, key=%q: %w",
 			bucketName, key, err)
 	}
 
-	if err := bucket.Put([]byte(key), buffer.Bytes()); err != nil {
+	if err := bucket.Put(key, data); err != nil {
 		return fmt.Errorf("persist value - bucket=%q, key=%q: %w",
 			bucketName, key, err)
 	}
 	return nil
 }
 
-func loadUniqueRecords[T Storable](tx *Tx, data T) (map[string]Record, error) {
+func decodeRecord(codec boltutil.Codec, bucketName string, k, v []byte) (Record, error) {
+	r, err := DecodeRecord(codec, v)
+	if err != nil {
+		return r, fmt.Errorf("decode value - bucket=%q, key=%q: %w",
+			bucketName, k, err)
+	}
+
+	return r, nil
+}
+
+// eachUnfolded calls f for the given record or, in case of a
+// snapshot, for all records it contains, latest first. It returns
+// false as soon as f does.
+func eachUnfolded(r Record, f func(r Record) bool) bool {
+	if len(r.Snapshot) < 1 {
+		return f(r)
+	}
+
+	for i := len(r.Snapshot) - 1; i >= 0; i-- {
+		if !f(r.Snapshot[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// eachRecordReverse calls f for every record of an entity bucket
+// starting with the latest one until f returns false.
+func eachRecordReverse(codec boltutil.Codec, bucket *bbolt.Bucket, bucketName string,
+	f func(r Record) bool) error {
+	cursor := bucket.Cursor()
+	for k, v := cursor.Last(); v != nil; k, v = cursor.Prev() {
+		r, err := decodeRecord(codec, bucketName, k, v)
+		if err != nil {
+			return err
+		}
+
+		if !eachUnfolded(r, f) {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func loadUniqueRecords[T Storable](tx Tx, id []byte) (map[string]Record, error) {
 	records := map[string]Record{}
 
+	data := valueFor[T]()
 	fieldsToStore := collections.SetFromSliceValue(structFields(data),
 		func(f reflect.StructField) string {
 			return f.Name
 		})
 
-	bucket, err := getBucketFor(tx, data)
+	bucket, err := getBucketForType[T](tx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	cursor := bucket.Cursor()
-	for k, v := cursor.Last(); v != nil; k, v = cursor.Prev() {
-		var r Record
-		if err := gob.NewDecoder(bytes.NewBuffer(v)).
-			Decode(&r); err != nil {
-			return nil, fmt.Errorf("decode value - bucket=%q, key=%q: %w",
-				data.Bucket(), k, err)
-		}
+	codec, err := tx.codecFor(data.Bucket())
+	if err != nil {
+		return nil, err
+	}
 
+	err = eachRecordReverse(codec, bucket, data.Bucket(), func(r Record) bool {
 		if !fieldsToStore.Contains(r.Value) {
-			continue
+			return true
 		}
 
-		records[r.Value] = r
-	}
+		// Only keep the latest record of every field.
+		if _, ok := records[r.Value]; !ok {
+			records[r.Value] = r
+		}
+		return true
+	})
 
-	return records, nil
+	return records, err
 }
 
-func getBucket(tx *Tx, buckets ...[]byte) (*bbolt.Bucket, error) {
+func getBucket(tx Tx, buckets ...[]byte) (*bbolt.Bucket, error) {
+	return getBoltBucket(tx.boltTx(), buckets...)
+}
+
+func getBoltBucket(btx *bbolt.Tx, buckets ...[]byte) (*bbolt.Bucket, error) {
 	var bucket *bbolt.Bucket
 	for _, bucketName := range buckets {
 		if bucket == nil {
-			bucket = tx.tx.Bucket(bucketName)
+			bucket = btx.Bucket(bucketName)
 		} else {
 			bucket = bucket.Bucket(bucketName)
 		}
@@ -217,25 +404,34 @@ func getBucket(tx *Tx, buckets ...[]byte) (*bbolt.Bucket, error) {
 	return bucket, nil
 }
 
-func getBucketFor(tx *Tx, data Storable) (*bbolt.Bucket, error) {
+func getBucketFor(tx Tx, data Storable) (*bbolt.Bucket, error) {
 	return getBucket(tx, []byte(data.Bucket()), data.BucketKey())
 }
 
-func getBucketForType[T Storable](tx *Tx, parts ...[]byte) (*bbolt.Bucket, error) {
+func getBucketForType[T Storable](tx Tx, parts ...[]byte) (*bbolt.Bucket, error) {
 	var data T
 	parts = append([][]byte{[]byte(data.Bucket())}, parts...)
 	return getBucket(tx, parts...)
 }
 
-func Store[T Storable](tx *Tx, data T, user auth.UserID) error {
+func Store[T Storable](tx *WriteTx, data T, user auth.UserID) error {
+	// The codec needs to be determined before the bucket gets any
+	// content, otherwise it would be mistaken for legacy gob data.
+	codec, err := boltutil.EnsureCodec(tx.tx, data.Bucket(),
+		tx.configuredCodec(data.Bucket()))
+	if err != nil {
+		return fmt.Errorf("store '%T': %w", data, err)
+	}
+
 	if err := ensureBucketExists(tx, data.Bucket(), string(data.BucketKey())); err != nil {
 		return fmt.Errorf("ensure bucket exists for '%T': %w", data, err)
 	}
 
-	records, err := toRecords(tx, data, user)
+	existingRecords, err := loadUniqueRecords[T](tx, data.BucketKey())
 	if err != nil {
 		return err
 	}
+	records := toRecords(existingRecords, data, user)
 
 	bucket, err := getBucketFor(tx, data)
 	if err != nil {
@@ -250,40 +446,127 @@ func Store[T Storable](tx *Tx, data T, user auth.UserID) error {
 	}
 
 	for _, r := range records {
-		err := storeRecord(r, bucket, data.Bucket())
+		err := storeRecord(codec, r, bucket, data.Bucket())
 		if err != nil {
 			return err
 		}
 	}
 
-	// TODO: afterInsertFuncs
-	// TODO: Do I really need both?
+	if err := updateIndexes(tx, data.Bucket(), data.BucketKey(),
+		existingRecords, records); err != nil {
+		return err
+	}
 
-	return nil
+	if err := updateCache(tx, data.Bucket(), data.BucketKey(),
+		existingRecords, records); err != nil {
+		return err
+	}
+
+	return appendEvent(tx, data.Bucket(), data.BucketKey(), records)
 }
 
-func Records[T Storable](tx *Tx, id []byte) ([]Record, error) {
-	records := make([]Record, 0, 10)
+// Delete retracts all fields of the entity with the given ID. The
+// retracted records stay part of the entity's history.
+func Delete[T Storable](tx *WriteTx, id []byte, user auth.UserID) error {
+	var data T
+	existingRecords, err := loadUniqueRecords[T](tx, id)
+	if err != nil {
+		return fmt.Errorf("delete %q from bucket %q: %w", id, data.Bucket(), err)
+	}
+
+	insertedAt := time.Now().UnixMilli()
+	records := make([]Record, 0, len(existingRecords))
+	for _, f := range structFields(valueFor[T]()) {
+		existing, ok := existingRecords[f.Name]
+		if !ok || existing.Retraction {
+			continue
+		}
+
+		records = append(records, Record{
+			ID:         id,
+			Value:      f.Name,
+			Attribute:  existing.Attribute,
+			InsertedAt: insertedAt,
+			InsertedBy: user,
+			Retraction: true,
+		})
+	}
+
+	if len(records) < 1 {
+		return fmt.Errorf("delete %q from bucket %q: %w",
+			id, data.Bucket(), ErrNotFound)
+	}
 
 	bucket, err := getBucketForType[T](tx, id)
+	if err != nil {
+		return err
+	}
+
+	codec, err := tx.codecFor(data.Bucket())
+	if err != nil {
+		return err
+	}
+
+	bucketConf := tx.buckets[data.Bucket()]
+	for _, f := range bucketConf.TriggerFuncs {
+		if err := f(tx, records); err != nil {
+			return fmt.Errorf("delete '%T': %w", data, err)
+		}
+	}
+
+	for _, r := range records {
+		err := storeRecord(codec, r, bucket, data.Bucket())
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := updateIndexes(tx, data.Bucket(), id, existingRecords, records); err != nil {
+		return err
+	}
+
+	if err := updateCache(tx, data.Bucket(), id, existingRecords, records); err != nil {
+		return err
+	}
+
+	return appendEvent(tx, data.Bucket(), id, records)
+}
+
+func Records[T Storable](tx Tx, id []byte) ([]Record, error) {
+	var data T
+	return RecordsOf(tx, data.Bucket(), id)
+}
+
+// RecordsOf works like Records for callers that do not know the type
+// of the entity.
+func RecordsOf(tx Tx, bucketName string, id []byte) ([]Record, error) {
+	records := make([]Record, 0, 10)
+
+	bucket, err := getBucket(tx, []byte(bucketName), id)
+	if err != nil {
+		return nil, err
+	}
+
+	codec, err := tx.codecFor(bucketName)
 	if err != nil {
 		return nil, err
 	}
 
 	err = bucket.ForEach(func(k []byte, v []byte) error {
-		var r Record
-		if err := gob.NewDecoder(bytes.NewBuffer(v)).
-			Decode(&r); err != nil {
-			return fmt.Errorf("decode value - bucket=%q, key=%q: %w",
-				bucket.Inspect().Name, k, err)
+		r, err := decodeRecord(codec, bucketName, k, v)
+		if err != nil {
+			return err
 		}
 
-		records = append(records, r)
+		if len(r.Snapshot) > 0 {
+			records = append(records, r.Snapshot...)
+		} else {
+			records = append(records, r)
+		}
 		return nil
 	})
 	if err != nil {
-		var data T
-		return nil, fmt.Errorf("records bucket=%q: %w", data.Bucket(), err)
+		return nil, fmt.Errorf("records bucket=%q: %w", bucketName, err)
 	}
 
 	return records, nil
@@ -296,8 +579,62 @@ func valueFor[T any]() T {
 
 var ErrNotFound = errors.New("not found")
 
-func Load[T Storable](tx *Tx, id []byte) (T, error) {
+func Load[T Storable](tx Tx, id []byte) (T, error) {
+	includeAll := func(Record) bool { return true }
+
+	var data T
+	var found bool
+	cached, ok, err := cachedRecord(tx, data.Bucket(), id)
+	if err != nil {
+		return data, err
+	}
+
+	if ok {
+		data, found, err = assemble[T](func(f func(Record) bool) error {
+			eachUnfolded(cached, f)
+			return nil
+		}, includeAll)
+	} else {
+		data, found, err = loadWhere[T](tx, id, includeAll)
+	}
+	if err != nil {
+		return data, err
+	}
+
+	if !found {
+		return data, fmt.Errorf("load %q: %w", id, ErrNotFound)
+	}
+
+	return data, nil
+}
+
+// loadWhere reconstructs an entity from the latest records that
+// satisfy include and additionally reports if any non-retracted
+// record matched.
+func loadWhere[T Storable](tx Tx, id []byte, include func(r Record) bool) (T, bool, error) {
+	bucket, err := getBucketForType[T](tx, id)
+	if err != nil {
+		return valueFor[T](), false, err
+	}
+
+	var data T
+	codec, err := tx.codecFor(data.Bucket())
+	if err != nil {
+		return valueFor[T](), false, err
+	}
+
+	return assemble[T](func(f func(Record) bool) error {
+		return eachRecordReverse(codec, bucket, data.Bucket(), f)
+	}, include)
+}
+
+// assemble sets the fields of a new T from the records yielded by
+// each, latest first, and reports if any non-retracted record was
+// used.
+func assemble[T Storable](each func(f func(r Record) bool) error,
+	include func(r Record) bool) (T, bool, error) {
 	data := valueFor[T]()
+	found := false
 
 	fieldsToStore := collections.SetFromSliceValue(structFields(data),
 		func(f reflect.StructField) string {
@@ -306,43 +643,34 @@ func Load[T Storable](tx *Tx, id []byte) (T, error) {
 
 	strct := reflect.ValueOf(data).Elem()
 
-	bucket, err := getBucketForType[T](tx, id)
-	if err != nil {
-		return data, err
-	}
-
-	cursor := bucket.Cursor()
-	for k, v := cursor.Last(); v != nil; k, v = cursor.Prev() {
-		var r Record
-		if err := gob.NewDecoder(bytes.NewBuffer(v)).
-			Decode(&r); err != nil {
-			return data, fmt.Errorf("decode value - bucket=%q, key=%q: %w",
-				bucket.Inspect().Name, k, err)
+	err := each(func(r Record) bool {
+		if !fieldsToStore.Contains(r.Value) || !include(r) {
+			return true
 		}
 
-		if !fieldsToStore.Contains(r.Value) {
-			continue
+		if r.Retraction {
+			fieldsToStore.Remove(r.Value)
+			return !fieldsToStore.Empty()
 		}
+		found = true
 
 		attribute := reflect.ValueOf(r).FieldByName("Attribute").Elem()
 		// attribute := reflect.ValueOf(r.Attribute).Elem()
 		f := strct.FieldByName(r.Value)
 		if !(f.IsValid() && f.CanSet() &&
 			attribute.Type().AssignableTo(f.Type())) {
-			continue
+			return true
 		}
 		f.Set(attribute)
 
 		fieldsToStore.Remove(r.Value)
-		if fieldsToStore.Empty() {
-			break
-		}
-	}
+		return !fieldsToStore.Empty()
+	})
 
-	return data, nil
+	return data, found, err
 }
 
-func ListAll[T Storable](tx *Tx) ([]T, error) {
+func ListAll[T Storable](tx Tx) ([]T, error) {
 	results := make([]T, 0, 10)
 	bucket, err := getBucketForType[T](tx)
 	if err != nil {
@@ -351,6 +679,9 @@ func ListAll[T Storable](tx *Tx) ([]T, error) {
 
 	err = bucket.ForEachBucket(func(id []byte) error {
 		data, err := Load[T](tx, id)
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
 		if err != nil {
 			return err
 		}
@@ -364,7 +695,7 @@ func ListAll[T Storable](tx *Tx) ([]T, error) {
 	return results, nil
 }
 
-func ensureBucketExists(tx *Tx, buckets ...string) error {
+func ensureBucketExists(tx *WriteTx, buckets ...string) error {
 	var bucket *bbolt.Bucket
 	var err error
 	for _, bucketName := range buckets {
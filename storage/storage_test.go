@@ -72,13 +72,13 @@ func TestStoreAndLoad(t *testing.T) {
 	p := newPayload()
 	user := newUser()
 
-	err := store.Write(func(tx *storage.Tx) error {
+	err := store.Write(func(tx *storage.WriteTx) error {
 		return storage.Store(tx, p, user)
 	})
 	AssertNoError(t, err, "storage.Store")
 
 	var records []storage.Record
-	err = store.Read(func(tx *storage.Tx) error {
+	err = store.Read(func(tx *storage.ReadTx) error {
 		r, err := storage.Records[*payload](tx, p.Key)
 		records = r
 		return err
@@ -93,7 +93,7 @@ func TestStoreAndLoad(t *testing.T) {
 	// err = storage.Load(store, p2)
 
 	var p2 *payload
-	err = store.Read(func(tx *storage.Tx) error {
+	err = store.Read(func(tx *storage.ReadTx) error {
 		p, err := storage.Load[*payload](tx, p.Key)
 		p2 = p
 		return err
@@ -104,12 +104,12 @@ func TestStoreAndLoad(t *testing.T) {
 
 	// Edit a single field
 	p.String = "edited"
-	err = store.Write(func(tx *storage.Tx) error {
+	err = store.Write(func(tx *storage.WriteTx) error {
 		return storage.Store(tx, p, user)
 	})
 	AssertNoError(t, err, "storage.Store")
 
-	err = store.Read(func(tx *storage.Tx) error {
+	err = store.Read(func(tx *storage.ReadTx) error {
 		r, err := storage.Records[*payload](tx, p.Key)
 		records = r
 		return err
@@ -117,7 +117,7 @@ func TestStoreAndLoad(t *testing.T) {
 	AssertNoError(t, err, "storage.Records")
 	AssertEquals(t, 6, len(records), "record length")
 
-	err = store.Read(func(tx *storage.Tx) error {
+	err = store.Read(func(tx *storage.ReadTx) error {
 		_, err = storage.Load[*payload](tx, []byte("unknown-ID"))
 		return err
 	})
@@ -134,7 +134,7 @@ func TestListAll(t *testing.T) {
 	p2 := newPayload()
 	user := newUser()
 
-	err := store.Write(func(tx *storage.Tx) error {
+	err := store.Write(func(tx *storage.WriteTx) error {
 		if err := storage.Store(tx, p1, user); err != nil {
 			return err
 		}
@@ -144,7 +144,7 @@ func TestListAll(t *testing.T) {
 	AssertNoError(t, err, "storage.Store")
 
 	var records []*payload
-	err = store.Read(func(tx *storage.Tx) error {
+	err = store.Read(func(tx *storage.ReadTx) error {
 		r, err := storage.ListAll[*payload](tx)
 		records = r
 		return err
@@ -172,7 +172,7 @@ func TestTriggerFunctions(t *testing.T) {
 	store.RegisterBucket(storage.Bucket{
 		Name: "payload",
 		TriggerFuncs: []storage.TriggerFunc{
-			func(tx *storage.Tx, rs []storage.Record) error {
+			func(tx *storage.WriteTx, rs []storage.Record) error {
 				for _, r := range rs {
 					storedFields = append(storedFields, r.Value)
 				}
@@ -184,7 +184,7 @@ func TestTriggerFunctions(t *testing.T) {
 	p := newPayload()
 	user := newUser()
 
-	err := store.Write(func(tx *storage.Tx) error {
+	err := store.Write(func(tx *storage.WriteTx) error {
 		return storage.Store(tx, p, user)
 	})
 	AssertNoError(t, err, "storage.Store")
@@ -201,7 +201,7 @@ func TestTriggerFunctionRollback(t *testing.T) {
 	store.RegisterBucket(storage.Bucket{
 		Name: "payload",
 		TriggerFuncs: []storage.TriggerFunc{
-			func(tx *storage.Tx, rs []storage.Record) error {
+			func(tx *storage.WriteTx, rs []storage.Record) error {
 				return errors.New("test failure")
 			},
 		},
@@ -210,15 +210,134 @@ func TestTriggerFunctionRollback(t *testing.T) {
 	p := newPayload()
 	user := newUser()
 
-	err := store.Write(func(tx *storage.Tx) error {
+	err := store.Write(func(tx *storage.WriteTx) error {
 		return storage.Store(tx, p, user)
 	})
 	AssertError(t, err, "storage.Store")
 
-	err = store.Read(func(tx *storage.Tx) error {
+	err = store.Read(func(tx *storage.ReadTx) error {
 		_, err = storage.Load[*payload](tx, p.Key)
 		return err
 	})
 	AssertEquals(t, true, errors.Is(err, storage.ErrNotFound),
 		"storage.Records")
 }
+
+func TestStoreRevertedValue(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	for _, value := range []string{"a", "b", "a"} {
+		p.String = value
+		err := store.Write(func(tx *storage.WriteTx) error {
+			return storage.Store(tx, p, user)
+		})
+		AssertNoError(t, err, "storage.Store")
+	}
+
+	var p2 *payload
+	err := store.Read(func(tx *storage.ReadTx) error {
+		p, err := storage.Load[*payload](tx, p.Key)
+		p2 = p
+		return err
+	})
+	AssertNoError(t, err, "storage.Load")
+	AssertEquals(t, "a", p2.String, "reverted field")
+}
+
+func TestDelete(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p1 := newPayload()
+	p2 := newPayload()
+	user := newUser()
+
+	err := store.Write(func(tx *storage.WriteTx) error {
+		if err := storage.Store(tx, p1, user); err != nil {
+			return err
+		}
+
+		return storage.Store(tx, p2, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Delete[*payload](tx, p1.Key, user)
+	})
+	AssertNoError(t, err, "storage.Delete")
+
+	err = store.Read(func(tx *storage.ReadTx) error {
+		_, err = storage.Load[*payload](tx, p1.Key)
+		return err
+	})
+	AssertEquals(t, true, errors.Is(err, storage.ErrNotFound), "load deleted")
+
+	var records []*payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		r, err := storage.ListAll[*payload](tx)
+		records = r
+		return err
+	})
+	AssertNoError(t, err, "storage.ListAll")
+	AssertEquals(t, []*payload{p2}, records, "records")
+
+	var changes []storage.Change
+	err = store.Read(func(tx *storage.ReadTx) error {
+		c, err := storage.History[*payload](tx, p1.Key)
+		changes = c
+		return err
+	})
+	AssertNoError(t, err, "storage.History")
+	AssertEquals(t, 10, len(changes), "change count")
+	AssertEquals(t, true, changes[9].Retraction, "retraction")
+
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Delete[*payload](tx, p1.Key, user)
+	})
+	AssertEquals(t, true, errors.Is(err, storage.ErrNotFound), "delete deleted")
+
+	// Storing a deleted entity again revives it.
+	err = store.Write(func(tx *storage.WriteTx) error {
+		return storage.Store(tx, p1, user)
+	})
+	AssertNoError(t, err, "storage.Store")
+
+	var p3 *payload
+	err = store.Read(func(tx *storage.ReadTx) error {
+		p, err := storage.Load[*payload](tx, p1.Key)
+		p3 = p
+		return err
+	})
+	AssertNoError(t, err, "storage.Load")
+	AssertEquals(t, p1, p3, "revived record")
+}
+
+func TestReadValue(t *testing.T) {
+	store := newStorage()
+	defer os.Remove("storage-test.db")
+	defer store.Close()
+
+	p := newPayload()
+	user := newUser()
+
+	stored, err := storage.WriteValue(store, func(tx *storage.WriteTx) (*payload, error) {
+		if err := storage.Store(tx, p, user); err != nil {
+			return nil, err
+		}
+		return storage.Load[*payload](tx, p.Key)
+	})
+	AssertNoError(t, err, "storage.WriteValue")
+	AssertEquals(t, p, stored, "stored payload")
+
+	loaded, err := storage.ReadValue(store, func(tx *storage.ReadTx) (*payload, error) {
+		return storage.Load[*payload](tx, p.Key)
+	})
+	AssertNoError(t, err, "storage.ReadValue")
+	AssertEquals(t, p, loaded, "loaded payload")
+}